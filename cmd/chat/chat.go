@@ -3,30 +3,42 @@ package chat
 import (
 	"context"
 	"errors"
+	"io"
 	"os"
-	"strings"
 
+	"github.com/nt54hamnghi/seaq/cmd/compose"
 	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/cmd/flaggroup"
 	"github.com/nt54hamnghi/seaq/cmd/model"
+	"github.com/nt54hamnghi/seaq/cmd/strategy"
 	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/nt54hamnghi/seaq/pkg/loader"
 	"github.com/nt54hamnghi/seaq/pkg/repl"
 	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
 	"github.com/spf13/cobra"
 	"github.com/tmc/langchaingo/chains"
-	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/schema"
 	"github.com/tmc/langchaingo/textsplitter"
 )
 
 type chatOptions struct {
-	input      string
+	docs       []schema.Document
 	model      string
 	noStream   bool
-	inputFile  flag.FilePath
+	inputFile  flag.Path
 	configFile flag.FilePath
+	thinking   flaggroup.Thinking
+
+	// strategy to answer with, and its prompt
+	strategy       string
+	strategyPrompt string
 
 	// llm options
 	// TODO: add validation for temperature
 	temperature float64
+	// temperatureSet reports whether the temperature is set by a flag
+	temperatureSet bool
 }
 
 func NewChatCmd() *cobra.Command {
@@ -36,7 +48,10 @@ func NewChatCmd() *cobra.Command {
 		Use:     "chat",
 		Short:   "Open a chat session [beta]",
 		GroupID: "common",
-		PreRunE: config.Init,
+		PreRunE: compose.SequenceE(
+			config.Init,
+			flaggroup.ValidateGroups(&opts.thinking),
+		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			switch err := opts.parse(cmd, args); {
 			case errors.Is(err, fileio.ErrInteractiveInput):
@@ -55,62 +70,100 @@ func NewChatCmd() *cobra.Command {
 	flags.StringVarP(&opts.model, "model", "m", "", "model to use")
 	flags.BoolVar(&opts.noStream, "no-stream", false, "disable streaming mode")
 	flags.Float64Var(&opts.temperature, "temperature", 0.7, "temperature to use")
-	flags.VarP(&opts.inputFile, "input", "i", "input file")
+	flags.StringVarP(&opts.strategy, "strategy", "s", "", "strategy to answer with, e.g. cot")
+	flags.VarP(&opts.inputFile, "input", "i", "input file, or a directory to read as a codebase")
 	config.AddConfigFlag(cmd, &opts.configFile)
+	flaggroup.InitGroups(cmd, &opts.thinking)
 
 	// set up completion for model flag
 	err := cmd.RegisterFlagCompletionFunc("model", model.CompleteModelArgs)
 	if err != nil {
 		os.Exit(1)
 	}
+	err = cmd.RegisterFlagCompletionFunc("strategy", strategy.CompleteStrategyArgs)
+	if err != nil {
+		os.Exit(1)
+	}
 
 	return cmd
 }
 
-func (opts *chatOptions) parse(_ *cobra.Command, _ []string) error {
-	var (
-		input string
-		err   error
-	)
-
+func (opts *chatOptions) parse(cmd *cobra.Command, _ []string) error {
+	// documents, such as PDF or EPUB files, are loaded with their metadata, e.g. page numbers
 	if opts.inputFile != "" {
-		bytes, err := os.ReadFile(opts.inputFile.String())
+		docs, err := loader.LoadFile(cmd.Context(), opts.inputFile.String())
 		if err != nil {
 			return err
 		}
-		input = string(bytes)
+		opts.docs = docs
 	} else {
-		input, err = fileio.ReadPipedStdin()
+		input, err := fileio.ReadPipedStdin()
 		if err != nil {
 			return err
 		}
+		opts.docs = []schema.Document{{PageContent: input, Metadata: map[string]any{}}}
 	}
 
-	opts.input = input
 	opts.model = config.Model()
+	opts.temperatureSet = cmd.Flags().Changed("temperature")
+
+	if opts.strategy != "" {
+		s, err := config.GetStrategy(opts.strategy)
+		if err != nil {
+			return err
+		}
+		opts.strategyPrompt = s.Prompt
+	}
 
 	return nil
 }
 
 func run(ctx context.Context, opts chatOptions) error {
-	// load the document
-	loader := documentloaders.NewText(strings.NewReader(opts.input))
-	docs, err := loader.LoadAndSplit(ctx,
+	if err := model.EnsureModel(ctx, opts.model); err != nil {
+		return err
+	}
+
+	// split the documents
+	docs, err := textsplitter.SplitDocuments(
 		textsplitter.NewRecursiveCharacter(
 			textsplitter.WithChunkSize(750),
 			textsplitter.WithChunkOverlap(100),
 		),
+		opts.docs,
 	)
 	if err != nil {
 		return err
 	}
 
-	// initialize chatREPL
-	// nolint: contextcheck
-	chatREPL, err := repl.New(opts.model, docs,
+	replOpts := []repl.Option{
 		repl.WithContext(ctx),
 		repl.WithNoStream(opts.noStream),
-	)
+	}
+
+	if opts.strategyPrompt != "" {
+		replOpts = append(replOpts, repl.WithStrategy(opts.strategyPrompt))
+	}
+
+	if opts.thinking.Enabled {
+		// only the --save-thinking file is written, the REPL shows reasoning itself
+		var thinkingFile io.WriteCloser
+		if opts.thinking.File != "" {
+			thinkingFile, err = fileio.NewCreateOnlyFileWriter(opts.thinking.File)
+			if err != nil {
+				return err
+			}
+			defer thinkingFile.Close()
+		}
+		replOpts = append(replOpts, repl.WithThinking(opts.thinking.Budget, thinkingFile))
+
+		if opts.temperatureSet {
+			llm.WarnThinkingTemperature(opts.model, opts.temperature)
+		}
+	}
+
+	// initialize chatREPL
+	// nolint: contextcheck
+	chatREPL, err := repl.New(opts.model, docs, replOpts...)
 	if err != nil {
 		return err
 	}
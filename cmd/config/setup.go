@@ -1,7 +1,6 @@
 package config
 
 import (
-	_ "embed"
 	"errors"
 	"fmt"
 	"path/filepath"
@@ -113,18 +112,12 @@ func (i setupInput) setup(opts setupOptions) error {
 	return nil
 }
 
-//go:embed patterns/prime_mind/system.md
-var primeMindPattern []byte
-
-//go:embed patterns/improve_prompt/system.md
-var improvePromptPattern []byte
-
 // createDefaultPatternRepo creates the default pattern directory.
 // It will create directories for built-in patterns only if:
 //  1. i.patternRepo is the default config directory
 //  2. i.patternRepo doesn't already exist
 //
-// The default patterns created are "prime_mind" and "improve_prompt".
+// The default patterns created are the built-in ones, see config.BuiltinRepo.
 func (i setupInput) createDefaultPatternRepo() error {
 	if i.patternRepo != getDefaultPatternsRepo() {
 		return nil
@@ -138,20 +131,15 @@ func (i setupInput) createDefaultPatternRepo() error {
 
 	log.Info("setting up default patterns", "pattern_repo", i.patternRepo)
 
-	defaultPatterns := map[string][]byte{
-		"prime_mind":     primeMindPattern,
-		"improve_prompt": improvePromptPattern,
+	builtin := config.BuiltinRepo()
+	names, err := builtin.List()
+	if err != nil {
+		return err
 	}
-	for name, data := range defaultPatterns {
-		patDir := filepath.Join(i.patternRepo, name)
-		patFile := filepath.Join(patDir, "system.md")
-
+	for _, name := range names {
 		log.Info("creating pattern directory", "pattern", name)
-		if err := fs.MkdirAll(patDir, 0o755); err != nil {
-			return fmt.Errorf("creating pattern directory %s: %w", name, err)
-		}
-		if err := fs.WriteFile(patFile, data, 0o600); err != nil {
-			return fmt.Errorf("writing pattern file %s: %w", name, err)
+		if _, err := config.CopyPattern(builtin, name, i.patternRepo, name); err != nil {
+			return fmt.Errorf("copying pattern %s: %w", name, err)
 		}
 	}
 
@@ -1,6 +1,7 @@
 package connection
 
 import (
+	"github.com/nt54hamnghi/seaq/pkg/config"
 	"github.com/spf13/cobra"
 )
 
@@ -22,7 +23,29 @@ func NewConnectionCmd() *cobra.Command {
 		newCreateCmd(),
 		newListCmd(),
 		newRemoveCmd(),
+		newEditCmd(),
+		newTestCmd(),
 	)
 
 	return cmd
 }
+
+// nolint: revive
+func CompleteConnectionArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	// connections are read from the config file
+	// so it relies on the config file being fully loaded
+	if err := config.EnsureConfig(cmd, args); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	conns, err := config.ListConnections()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	providers := make([]string, 0, len(conns))
+	for _, c := range conns {
+		providers = append(providers, c.Provider)
+	}
+	return providers, cobra.ShellCompDirectiveNoFileComp
+}
@@ -1,6 +1,7 @@
 package connection
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -14,6 +15,10 @@ type createOptions struct {
 	baseURL    flag.URL
 	configFile flag.FilePath
 	envKey     string
+	models     []string
+	include    []string
+	exclude    []string
+	systemRole string
 }
 
 func newCreateCmd() *cobra.Command {
@@ -29,7 +34,21 @@ func newCreateCmd() *cobra.Command {
 		RunE: func(cmd *cobra.Command, args []string) error {
 			provider := args[0]
 
+			// the default env key is derived from the provider name
+			if cmd.Flags().Changed("env") {
+				if err := validateEnvKey(opts.envKey); err != nil {
+					return err
+				}
+			}
+
 			conn := llm.NewConnection(provider, opts.baseURL.String(), opts.envKey)
+			conn.Models = opts.models
+			conn.Include = opts.include
+			conn.Exclude = opts.exclude
+			conn.SystemRole = llm.SystemRole(opts.systemRole)
+			if err := conn.SystemRole.Validate(); err != nil {
+				return err
+			}
 			if err := config.AddConnection(conn); err != nil {
 				return fmt.Errorf("add connection: %w", err)
 			}
@@ -47,6 +66,12 @@ func newCreateCmd() *cobra.Command {
 		os.Exit(1)
 	}
 	flags.StringVar(&opts.envKey, "env", "", "Environment variable name for API key")
+	flags.StringSliceVar(&opts.models, "models", nil, "Static list of model IDs, skips model discovery")
+	flags.StringSliceVar(&opts.include, "include", nil, "Glob patterns of discovered models to keep")
+	flags.StringSliceVar(&opts.exclude, "exclude", nil, "Glob patterns of discovered models to drop")
+	flags.StringVar(&opts.systemRole, "system-role", "", "How patterns are sent: system, developer, generic or merge")
+	cmd.MarkFlagsMutuallyExclusive("models", "include")
+	cmd.MarkFlagsMutuallyExclusive("models", "exclude")
 	config.AddConfigFlag(cmd, &opts.configFile)
 
 	return cmd
@@ -63,3 +88,15 @@ func createArgs(cmd *cobra.Command, args []string) error {
 
 	return nil
 }
+
+// validateEnvKey checks that the name of the environment variable holding an API key
+// is a valid identifier.
+func validateEnvKey(name string) error {
+	if name == "" {
+		return errors.New("environment variable name cannot be empty")
+	}
+	if !llm.IsIdent(name) {
+		return fmt.Errorf("environment variable name %q must be a valid identifier", name)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package connection
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/spf13/cobra"
+)
+
+type editOptions struct {
+	baseURL    flag.URL
+	configFile flag.FilePath
+	envKey     string
+	provider   string
+}
+
+func newEditCmd() *cobra.Command {
+	var opts editOptions
+
+	cmd := &cobra.Command{
+		Use:   "edit [name]",
+		Short: "Edit an existing connection",
+		Long: "Edit an existing connection.\n\n" +
+			"If neither --url nor --env is set, the connection is edited interactively.",
+		Args:              cobra.ExactArgs(1),
+		ValidArgsFunction: CompleteConnectionArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			conn, err := opts.parse(cmd, args)
+			if err != nil {
+				return err
+			}
+
+			if err := config.UpdateConnection(conn); err != nil {
+				return fmt.Errorf("edit connection: %w", err)
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), conn.Provider)
+			return nil
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.Var(&opts.baseURL, "url", "Base URL")
+	flags.StringVar(&opts.envKey, "env", "", "Environment variable name for API key")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+// parse returns the edited connection, either from flags or from an interactive form.
+func (opts *editOptions) parse(cmd *cobra.Command, args []string) (llm.Connection, error) {
+	opts.provider = args[0]
+
+	cs, err := llm.GetConnectionSet()
+	if err != nil {
+		return llm.Connection{}, err
+	}
+
+	conn, ok := cs.Get(opts.provider)
+	if !ok {
+		return llm.Connection{}, fmt.Errorf("connection %q not found", opts.provider)
+	}
+
+	urlSet := cmd.Flags().Changed("url")
+	envSet := cmd.Flags().Changed("env")
+
+	if !urlSet && !envSet {
+		return editInteractively(conn)
+	}
+
+	if urlSet {
+		conn.BaseURL = opts.baseURL.String()
+	}
+	if envSet {
+		if err := validateEnvKey(opts.envKey); err != nil {
+			return llm.Connection{}, fmt.Errorf("invalid --env: %w", err)
+		}
+		conn.EnvKey = opts.envKey
+	}
+
+	return conn, nil
+}
+
+func editInteractively(conn llm.Connection) (llm.Connection, error) {
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Base URL").
+				Value(&conn.BaseURL).
+				Validate(func(s string) error {
+					_, err := url.ParseRequestURI(s)
+					return err
+				}),
+			huh.NewInput().
+				Title("Environment variable name for API key").
+				Value(&conn.EnvKey).
+				Validate(validateEnvKey),
+		),
+	)
+
+	if err := form.Run(); err != nil {
+		return llm.Connection{}, err
+	}
+
+	return conn, nil
+}
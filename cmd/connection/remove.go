@@ -16,12 +16,13 @@ func newRemoveCmd() *cobra.Command {
 	var opts removeOptions
 
 	cmd := &cobra.Command{
-		Use:          "remove [name]...",
-		Short:        "Remove a connection",
-		Aliases:      []string{"rm"},
-		Args:         cobra.MinimumNArgs(1),
-		SilenceUsage: true,
-		PreRunE:      config.Init,
+		Use:               "remove [name]...",
+		Short:             "Remove a connection",
+		Aliases:           []string{"rm"},
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: CompleteConnectionArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
 		RunE: func(cmd *cobra.Command, args []string) error { //nolint:revive
 			if err := config.RemoveConnection(args...); err != nil {
 				return fmt.Errorf("remove connection: %w", err)
@@ -0,0 +1,146 @@
+package connection
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/nt54hamnghi/seaq/pkg/util/pool"
+	"github.com/spf13/cobra"
+)
+
+type testOptions struct {
+	configFile flag.FilePath
+	model      string
+	timeout    time.Duration
+	providers  []string
+}
+
+func newTestCmd() *cobra.Command {
+	var opts testOptions
+
+	cmd := &cobra.Command{
+		Use:   "test [name]...",
+		Short: "Test connections",
+		Long: "Test connections by checking the API key, listing models and running a one-token completion.\n\n" +
+			"If no name is given, all connections are tested.",
+		ValidArgsFunction: CompleteConnectionArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return testRun(cmd, opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVarP(&opts.model, "model", "m", "", "model to run the completion against (default: first listed model)")
+	flags.DurationVarP(&opts.timeout, "timeout", "t", 30*time.Second, "timeout for each connection")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func (opts *testOptions) parse(cmd *cobra.Command, args []string) error {
+	if opts.model != "" && len(args) != 1 {
+		return errors.New("--model can only be used when testing a single connection")
+	}
+	if cmd.Flags().Changed("timeout") && opts.timeout <= 0 {
+		return errors.New("--timeout must be positive")
+	}
+	opts.providers = args
+	return nil
+}
+
+func testRun(cmd *cobra.Command, opts testOptions) error {
+	cs, err := llm.GetConnectionSet()
+	if err != nil {
+		return fmt.Errorf("list connections: %w", err)
+	}
+
+	conns := cs.AsSlice()
+	if len(opts.providers) > 0 {
+		conns = make([]llm.Connection, 0, len(opts.providers))
+		for _, p := range opts.providers {
+			conn, ok := cs.Get(p)
+			if !ok {
+				return fmt.Errorf("connection %q not found", p)
+			}
+			conns = append(conns, conn)
+		}
+	}
+
+	if len(conns) == 0 {
+		return errors.New("no connections to test")
+	}
+
+	ctx := cmd.Context()
+	results := pool.OrderedGoFunc(conns, func(c llm.Connection) (llm.CheckResult, error) {
+		ctx, cancel := context.WithTimeout(ctx, opts.timeout)
+		defer cancel()
+		return c.Check(ctx, opts.model), nil
+	})
+
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 4, ' ', 0)
+
+	const format = "%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, format, "PROVIDER", "STATUS", "MODELS", "MODEL", "LATENCY", "DETAILS")
+
+	failed := 0
+	for _, r := range results {
+		res := r.Output
+		status, details := "ok", ""
+		if !res.OK() {
+			failed++
+			status = "failed"
+			details = fmt.Sprintf("%s: %s", res.Stage, llm.DescribeError(res.Err))
+		}
+
+		fmt.Fprintf(w, format,
+			res.Provider,
+			status,
+			formatCount(res),
+			res.Model,
+			formatLatency(res),
+			details,
+		)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d connection(s) failed", failed, len(results))
+	}
+	return nil
+}
+
+func formatCount(res llm.CheckResult) string {
+	if res.Stage == llm.StageEnv || (res.Stage == llm.StageList && res.Models == 0) {
+		return "-"
+	}
+	return fmt.Sprint(res.Models)
+}
+
+// formatLatency shows the latency of listing models and of the completion, if it was run.
+func formatLatency(res llm.CheckResult) string {
+	if res.ListLatency == 0 {
+		return "-"
+	}
+
+	latency := "list " + res.ListLatency.Round(time.Millisecond).String()
+	if res.CompletionLatency > 0 {
+		latency += ", completion " + res.CompletionLatency.Round(time.Millisecond).String()
+	}
+	return latency
+}
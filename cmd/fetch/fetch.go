@@ -36,6 +36,10 @@ func NewFetchCmd() *cobra.Command {
 
 	cmd.AddCommand(
 		newPageCmd(),
+		newFileCmd(),
+		newSubtitleCmd(),
+		newMediaCmd(),
+		newRepoCmd(),
 		newUdemyCmd(),
 		newRedditCmd(),
 		newXCmd(),
@@ -0,0 +1,141 @@
+package fetch
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nt54hamnghi/seaq/cmd/flaggroup"
+	"github.com/nt54hamnghi/seaq/pkg/loader"
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/loader/epub"
+	"github.com/nt54hamnghi/seaq/pkg/loader/office"
+	"github.com/nt54hamnghi/seaq/pkg/loader/pdf"
+	"github.com/nt54hamnghi/seaq/pkg/util/ranges"
+	"github.com/spf13/cobra"
+)
+
+type fileOptions struct {
+	// global fetch options
+	fetchGlobalOptions
+
+	file      string
+	format    loader.Format
+	pageRange string
+	pages     ranges.Ranges
+	chapter   string
+}
+
+func newFileCmd() *cobra.Command {
+	var opts fileOptions
+
+	cmd := &cobra.Command{
+		Use:   "file [path]",
+		Short: "Extract text from a local document as Markdown",
+		Long: `Extract text from a local document as Markdown.
+
+Supported formats:
+  PDF    one document per page, selected with --pages
+  PPTX   one document per slide with its speaker notes, selected with --pages
+  EPUB   one document per chapter, selected with --chapter
+  DOCX   a single document
+  ODT    a single document
+
+Pages and slides are selected with a list of numbers and ranges, e.g. "3-10", "1,4-6" or "12-" for 12 to the end.
+Chapters are selected the same way, or by a part of their title, e.g. "introduction".`,
+		Aliases:      []string{"fil"},
+		Args:         cobra.ExactArgs(1),
+		PreRunE:      flaggroup.ValidateGroups(&opts.fetchGlobalOptions),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return fileRun(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVar(&opts.pageRange, "pages", "", "pages or slides to extract, e.g. 3-10 (default all)")
+	flags.StringVar(&opts.chapter, "chapter", "", "chapters to extract, by number or title, e.g. 2-4 (default all)")
+	flaggroup.InitGroups(cmd, &opts.fetchGlobalOptions)
+
+	return cmd
+}
+
+func (opts *fileOptions) parse(cmd *cobra.Command, args []string) error {
+	opts.file = args[0]
+
+	format, err := loader.DetectFormat(opts.file)
+	if err != nil {
+		return err
+	}
+	opts.format = format
+
+	flags := cmd.Flags()
+	switch {
+	case format == loader.FormatText:
+		return fmt.Errorf("unsupported file %s, supported formats: PDF, EPUB, DOCX, PPTX, ODT", opts.file)
+	case format == loader.FormatRepo:
+		return fmt.Errorf("%s is a directory, use seaq fetch repo to read its files", opts.file)
+	case flags.Changed("pages") && format != loader.FormatPDF && format != loader.FormatPPTX:
+		return fmt.Errorf("--pages only applies to PDF and PPTX files, %s is a %s file", opts.file, format)
+	case flags.Changed("chapter") && format != loader.FormatEPUB:
+		return fmt.Errorf("--chapter only applies to EPUB files, %s is a %s file", opts.file, format)
+	}
+
+	pages, err := ranges.Parse(opts.pageRange)
+	if err != nil {
+		return fmt.Errorf("--pages: %w", err)
+	}
+	opts.pages = pages
+	return nil
+}
+
+func fileRun(ctx context.Context, opts fileOptions) error {
+	fileLoader, err := newFileLoader(opts)
+	if err != nil {
+		return err
+	}
+
+	dest, err := opts.output.Writer()
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	if !opts.ignoreCache {
+		return loader.LoadAndCache(ctx, fileLoader, dest, opts.asJSON)
+	}
+
+	return loader.LoadAndWrite(ctx, fileLoader, dest, opts.asJSON)
+}
+
+func newFileLoader(opts fileOptions) (cache.CacheableLoader, error) {
+	switch opts.format {
+	case loader.FormatPDF:
+		return pdf.NewPDFLoader(
+			pdf.WithFile(opts.file),
+			pdf.WithPages(opts.pages),
+		), nil
+	case loader.FormatPPTX:
+		return office.NewOfficeLoader(
+			office.WithFile(opts.file),
+			office.WithSlides(opts.pages),
+		), nil
+	case loader.FormatEPUB:
+		epubOpts := []epub.Option{epub.WithFile(opts.file)}
+		// a chapter is selected by number, or else by title
+		if chapters, err := ranges.Parse(opts.chapter); err == nil {
+			epubOpts = append(epubOpts, epub.WithChapters(chapters))
+		} else {
+			epubOpts = append(epubOpts, epub.WithTitle(opts.chapter))
+		}
+		return epub.NewEPUBLoader(epubOpts...), nil
+	default:
+		if l := loader.NewDocumentLoader(opts.file, opts.format); l != nil {
+			return l, nil
+		}
+		return nil, fmt.Errorf("unsupported file %s", opts.file)
+	}
+}
@@ -0,0 +1,149 @@
+package fetch
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+
+	"github.com/nt54hamnghi/seaq/cmd/compose"
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/cmd/flaggroup"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/env"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/nt54hamnghi/seaq/pkg/loader"
+	"github.com/nt54hamnghi/seaq/pkg/loader/media"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+const (
+	openAIBaseURL = "https://api.openai.com/v1"
+	// whisperCPPBackend transcribes locally with a whisper.cpp binary
+	whisperCPPBackend = "whisper.cpp"
+)
+
+type mediaOptions struct {
+	// global fetch options
+	fetchGlobalOptions
+
+	file       string
+	backend    string
+	model      string
+	language   string
+	configFile flag.FilePath
+	interval   flaggroup.Interval
+}
+
+func newMediaCmd() *cobra.Command {
+	var opts mediaOptions
+
+	cmd := &cobra.Command{
+		Use:   "media [path]",
+		Short: "Transcribe the audio of a local audio or video file",
+		Long: `Transcribe the audio of a local audio or video file.
+
+The audio is extracted with ffmpeg and sent to a transcription backend:
+  openai        OpenAI's /audio/transcriptions endpoint, with OPENAI_API_KEY
+  <provider>    the OpenAI-compatible endpoint of a connection, e.g. Groq or a local whisper server
+  whisper.cpp   a local whisper.cpp binary, with --model set to a ggml model file
+
+The backend, model and language default to transcription.backend, transcription.model
+and transcription.language in the config file.`,
+		Aliases:      []string{"med"},
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE: compose.SequenceE(
+			config.Init,
+			flaggroup.ValidateGroups(&opts.interval, &opts.fetchGlobalOptions),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return mediaRun(cmd.Context(), opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVarP(&opts.backend, "backend", "b", "", "transcription backend: openai, a connection's provider or whisper.cpp")
+	flags.StringVarP(&opts.model, "model", "m", "", "transcription model, or the model file of whisper.cpp")
+	flags.StringVarP(&opts.language, "language", "l", "", "language of the audio, e.g. en (default detected)")
+	config.AddConfigFlag(cmd, &opts.configFile)
+	flaggroup.InitGroups(cmd, &opts.interval, &opts.fetchGlobalOptions)
+
+	return cmd
+}
+
+func (opts *mediaOptions) parse(_ *cobra.Command, args []string) error {
+	opts.file = args[0]
+
+	opts.backend = cmp.Or(opts.backend, viper.GetString("transcription.backend"), "openai")
+	opts.model = cmp.Or(opts.model, viper.GetString("transcription.model"))
+	opts.language = cmp.Or(opts.language, viper.GetString("transcription.language"))
+	return nil
+}
+
+func mediaRun(ctx context.Context, opts mediaOptions) error {
+	transcriber, err := newTranscriber(opts)
+	if err != nil {
+		return err
+	}
+
+	mediaLoader := media.NewMediaLoader(
+		media.WithFile(opts.file),
+		media.WithStart(opts.interval.Start),
+		media.WithEnd(opts.interval.End),
+		media.WithTranscriber(transcriber),
+	)
+
+	dest, err := opts.output.Writer()
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	if !opts.ignoreCache {
+		return loader.LoadAndCache(ctx, mediaLoader, dest, opts.asJSON)
+	}
+
+	return loader.LoadAndWrite(ctx, mediaLoader, dest, opts.asJSON)
+}
+
+// newTranscriber creates the transcriber of a backend,
+// which is either whisper.cpp, openai or the provider of a connection.
+func newTranscriber(opts mediaOptions) (media.Transcriber, error) {
+	switch opts.backend {
+	case whisperCPPBackend:
+		if opts.model == "" {
+			return nil, fmt.Errorf("%s needs a ggml model file, set it with --model or transcription.model", whisperCPPBackend)
+		}
+		binary := cmp.Or(viper.GetString("transcription.binary"), "whisper-cli")
+		return media.NewWhisperCPP(binary, opts.model, opts.language), nil
+	case "openai":
+		apiKey, err := env.OpenAIAPIKey()
+		if err != nil {
+			return nil, err
+		}
+		return media.NewAPITranscriber(openAIBaseURL, apiKey, cmp.Or(opts.model, "whisper-1"), opts.language), nil
+	default:
+		connections, err := llm.GetConnectionSet()
+		if err != nil {
+			return nil, err
+		}
+		conn, ok := connections.Get(opts.backend)
+		if !ok {
+			return nil, fmt.Errorf("unknown transcription backend %s, use openai, %s or the provider of a connection", opts.backend, whisperCPPBackend)
+		}
+		if opts.model == "" {
+			return nil, fmt.Errorf("%s has no default transcription model, set it with --model or transcription.model", opts.backend)
+		}
+		apiKey, err := env.Get(conn.EnvKey)
+		if err != nil {
+			return nil, err
+		}
+		return media.NewAPITranscriber(conn.BaseURL, apiKey, conn.ResolveModel(opts.model), opts.language), nil
+	}
+}
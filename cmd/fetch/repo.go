@@ -0,0 +1,100 @@
+package fetch
+
+import (
+	"context"
+	"errors"
+
+	"github.com/nt54hamnghi/seaq/cmd/flaggroup"
+	"github.com/nt54hamnghi/seaq/pkg/loader"
+	"github.com/nt54hamnghi/seaq/pkg/loader/repo"
+	"github.com/spf13/cobra"
+)
+
+type repoOptions struct {
+	// global fetch options
+	fetchGlobalOptions
+
+	dir       string
+	include   []string
+	exclude   []string
+	prefer    []string
+	maxTokens int
+}
+
+func newRepoCmd() *cobra.Command {
+	var opts repoOptions
+
+	cmd := &cobra.Command{
+		Use:   "repo [path]",
+		Short: "Pack the source code of a local directory as Markdown",
+		Long: `Pack the source code of a local directory as Markdown, one code block per file.
+
+Files ignored by .gitignore, binary files, files over 1 MB and vendored directories,
+such as node_modules and vendor, are skipped.
+
+Globs without a slash match file and directory names at any depth, e.g. "*.go",
+others match paths from the directory, e.g. "cmd/**/*.go".
+
+With --max-tokens, the files that fit in the budget are kept, in this order:
+files matching --prefer, the README and manifests such as go.mod, source code,
+documentation and data, tests, and generated files such as lockfiles.`,
+		Aliases:      []string{"code"},
+		Args:         cobra.MaximumNArgs(1),
+		PreRunE:      flaggroup.ValidateGroups(&opts.fetchGlobalOptions),
+		SilenceUsage: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return repoRun(cmd.Context(), opts)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringSliceVar(&opts.include, "include", nil, "only read files matching these globs, e.g. *.go,*.md")
+	flags.StringSliceVar(&opts.exclude, "exclude", nil, "skip files and directories matching these globs, e.g. *_test.go,docs")
+	flags.StringSliceVar(&opts.prefer, "prefer", nil, "read files matching these globs first within the token budget")
+	flags.IntVarP(&opts.maxTokens, "max-tokens", "t", 0, "approximate token budget, 0 for no budget")
+	flaggroup.InitGroups(cmd, &opts.fetchGlobalOptions)
+
+	return cmd
+}
+
+func (opts *repoOptions) parse(cmd *cobra.Command, args []string) error {
+	opts.dir = "."
+	if len(args) > 0 {
+		opts.dir = args[0]
+	}
+
+	flags := cmd.Flags()
+	switch {
+	case opts.maxTokens < 0:
+		return errors.New("--max-tokens cannot be negative")
+	case flags.Changed("prefer") && opts.maxTokens == 0:
+		return errors.New("--prefer can only be used with --max-tokens")
+	}
+	return nil
+}
+
+func repoRun(ctx context.Context, opts repoOptions) error {
+	repoLoader := repo.NewRepoLoader(
+		repo.WithDir(opts.dir),
+		repo.WithInclude(opts.include),
+		repo.WithExclude(opts.exclude),
+		repo.WithPrefer(opts.prefer),
+		repo.WithMaxTokens(opts.maxTokens),
+	)
+
+	dest, err := opts.output.Writer()
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	if !opts.ignoreCache {
+		return loader.LoadAndCache(ctx, repoLoader, dest, opts.asJSON)
+	}
+
+	return loader.LoadAndWrite(ctx, repoLoader, dest, opts.asJSON)
+}
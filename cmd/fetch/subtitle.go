@@ -0,0 +1,73 @@
+package fetch
+
+import (
+	"context"
+
+	"github.com/nt54hamnghi/seaq/cmd/flaggroup"
+	"github.com/nt54hamnghi/seaq/pkg/loader"
+	"github.com/nt54hamnghi/seaq/pkg/loader/subtitle"
+	"github.com/spf13/cobra"
+)
+
+type subtitleOptions struct {
+	// global fetch options
+	fetchGlobalOptions
+
+	file     string
+	interval flaggroup.Interval
+}
+
+func newSubtitleCmd() *cobra.Command {
+	var opts subtitleOptions
+
+	cmd := &cobra.Command{
+		Use:   "subtitle [path]",
+		Short: "Get captions from a local subtitle file",
+		Long: `Get captions from a local subtitle file.
+
+Supported formats: SRT (.srt), WebVTT (.vtt), SSA/ASS (.ssa, .ass), EBU STL (.stl) and TTML (.ttml).
+The format is picked from the file's extension.`,
+		Aliases:      []string{"sub"},
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE:      flaggroup.ValidateGroups(&opts.interval, &opts.fetchGlobalOptions),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return subtitleRun(cmd.Context(), opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flaggroup.InitGroups(cmd, &opts.interval, &opts.fetchGlobalOptions)
+
+	return cmd
+}
+
+func (opts *subtitleOptions) parse(_ *cobra.Command, args []string) error {
+	opts.file = args[0]
+	return nil
+}
+
+func subtitleRun(ctx context.Context, opts subtitleOptions) error {
+	subtitleLoader := subtitle.NewSubtitleLoader(
+		subtitle.WithFile(opts.file),
+		subtitle.WithStart(opts.interval.Start),
+		subtitle.WithEnd(opts.interval.End),
+	)
+
+	dest, err := opts.output.Writer()
+	if err != nil {
+		return err
+	}
+	defer dest.Close()
+
+	if !opts.ignoreCache {
+		return loader.LoadAndCache(ctx, subtitleLoader, dest, opts.asJSON)
+	}
+
+	return loader.LoadAndWrite(ctx, subtitleLoader, dest, opts.asJSON)
+}
@@ -52,6 +52,40 @@ func (f *FilePath) Type() string {
 	return "string"
 }
 
+// Path is the path of an existing file or directory.
+type Path string
+
+// String implements the pflag.Value interface
+// It returns the string representation of the Path
+func (p *Path) String() string {
+	return string(*p)
+}
+
+// Set implements the pflag.Value interface
+// It parses the input string and sets the Path
+func (p *Path) Set(s string) error {
+	// get absolute path
+	absPath, err := filepath.Abs(s)
+	if err != nil {
+		return err
+	}
+
+	if exists, err := fs.Exists(absPath); err != nil {
+		return fmt.Errorf("error checking %q: %w", absPath, err)
+	} else if !exists {
+		return fmt.Errorf("%q does not exist", absPath)
+	}
+
+	*p = Path(absPath)
+	return nil
+}
+
+// Type implements the pflag.Value interface
+// It returns the type of the Path flag in help message
+func (p *Path) Type() string {
+	return "string"
+}
+
 type DirPath string
 
 // String implements the pflag.Value interface
@@ -0,0 +1,138 @@
+package flaggroup
+
+import (
+	"errors"
+	"io"
+	"os"
+
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
+	"github.com/spf13/cobra"
+)
+
+const (
+	dimStart = "\x1b[2m"
+	dimEnd   = "\x1b[22m"
+)
+
+type Thinking struct {
+	Enabled bool
+	Budget  int
+	File    string
+}
+
+func (t *Thinking) Validate(cmd *cobra.Command, args []string) error { // nolint: revive
+	flags := cmd.Flags()
+	if !t.Enabled && (flags.Changed("thinking-budget") || flags.Changed("save-thinking")) {
+		return errors.New("--thinking-budget and --save-thinking can only be used with --thinking")
+	}
+
+	if t.Budget <= 0 {
+		return errors.New("thinking budget must be positive")
+	}
+
+	return nil
+}
+
+func (t *Thinking) Init(cmd *cobra.Command) {
+	flags := cmd.Flags()
+	flags.BoolVar(&t.Enabled, "thinking", false, "enable reasoning for models that support it")
+	flags.IntVar(&t.Budget, "thinking-budget", llm.DefaultThinkingBudget, "max tokens to spend on reasoning")
+	flags.StringVar(&t.File, "save-thinking", "", "save reasoning to a file")
+}
+
+// Writer returns a writer for reasoning content.
+// Reasoning is written to stderr, dimmed if it's a terminal,
+// and to the --save-thinking file if set.
+// It returns nil if thinking is disabled.
+func (t *Thinking) Writer() (*ThinkingWriter, error) {
+	if !t.Enabled {
+		return nil, nil // nolint: nilnil
+	}
+
+	w := &ThinkingWriter{
+		term: os.Stderr,
+		dim:  fileio.IsTerminal(os.Stderr),
+	}
+
+	if t.File != "" {
+		f, err := fileio.NewCreateOnlyFileWriter(t.File)
+		if err != nil {
+			return nil, err
+		}
+		w.file = f
+	}
+
+	return w, nil
+}
+
+// ThinkingWriter writes reasoning content to the terminal and, optionally, a file.
+type ThinkingWriter struct {
+	term io.Writer
+	file io.WriteCloser
+	dim  bool
+	// open reports whether reasoning has been written to the terminal
+	// since the last time the block was ended.
+	open bool
+}
+
+func (w *ThinkingWriter) Write(p []byte) (int, error) {
+	if w.file != nil {
+		if _, err := w.file.Write(p); err != nil {
+			return 0, err
+		}
+	}
+
+	w.open = true
+	if w.dim {
+		return len(p), writeAll(w.term, dimStart, string(p), dimEnd)
+	}
+	return w.term.Write(p)
+}
+
+// End ends the reasoning block on the terminal, if any,
+// so that content written after it starts on a new paragraph.
+func (w *ThinkingWriter) End() error {
+	if !w.open {
+		return nil
+	}
+	w.open = false
+	_, err := io.WriteString(w.term, "\n\n")
+	return err
+}
+
+// Close ends the reasoning block and closes the file, if any.
+func (w *ThinkingWriter) Close() error {
+	err := w.End()
+	if w.file != nil {
+		err = errors.Join(err, w.file.Close())
+	}
+	return err
+}
+
+// Separate returns a writer that ends the reasoning block before every write to dest.
+// It keeps reasoning and content apart when both are shown on the same terminal.
+func (w *ThinkingWriter) Separate(dest io.Writer) io.Writer {
+	return &separatedWriter{dest: dest, thinking: w}
+}
+
+type separatedWriter struct {
+	dest     io.Writer
+	thinking *ThinkingWriter
+}
+
+func (w *separatedWriter) Write(p []byte) (int, error) {
+	if err := w.thinking.End(); err != nil {
+		return 0, err
+	}
+	return w.dest.Write(p)
+}
+
+func writeAll(w io.Writer, parts ...string) error {
+	for _, p := range parts {
+		if _, err := io.WriteString(w, p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
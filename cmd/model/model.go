@@ -25,6 +25,7 @@ func NewModelCmd() *cobra.Command {
 		newGetCmd(),
 		newListCmd(),
 		newSetCmd(),
+		newPullCmd(),
 	)
 
 	return cmd
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/ollama/ollama/api"
+	"github.com/spf13/cobra"
+)
+
+const progressBarWidth = 30
+
+type pullOptions struct {
+	configFile flag.FilePath
+	model      string
+}
+
+func newPullCmd() *cobra.Command {
+	var opts pullOptions
+
+	cmd := &cobra.Command{
+		Use:          "pull [ollama/model]",
+		Short:        "Pull a model to the local Ollama server",
+		Args:         pullArgs,
+		SilenceUsage: true,
+		PreRunE:      config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return pullRun(cmd.Context(), cmd.ErrOrStderr(), opts)
+		},
+	}
+
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func pullArgs(cmd *cobra.Command, args []string) error {
+	if err := cobra.ExactArgs(1)(cmd, args); err != nil {
+		return err
+	}
+
+	if !llm.IsOllamaModel(args[0]) {
+		return errors.New("model must be in the format ollama/<name>")
+	}
+
+	return nil
+}
+
+func (opts *pullOptions) parse(_ *cobra.Command, args []string) error {
+	// pullArgs guarantees the "ollama/" prefix
+	_, opts.model, _ = strings.Cut(args[0], "/")
+	return nil
+}
+
+func pullRun(ctx context.Context, w io.Writer, opts pullOptions) error {
+	if err := llm.PullOllamaModel(ctx, opts.model, newProgressPrinter(w)); err != nil {
+		return fmt.Errorf("pull %s: %w", opts.model, err)
+	}
+
+	// end the progress line
+	fmt.Fprintln(w)
+	fmt.Fprintf(w, "Successfully pulled 'ollama/%s'\n", opts.model)
+	return nil
+}
+
+// EnsureModel makes sure an Ollama model is available locally before it's used.
+// It's a no-op for other providers or models that are already available.
+//
+// If the model is missing and `ollama.auto_pull` is enabled,
+// the user is asked whether to pull it. Otherwise, an error suggesting
+// `seaq model pull` is returned.
+func EnsureModel(ctx context.Context, name string) error {
+	if !llm.IsOllamaModel(name) || llm.HasModel(name) {
+		return nil
+	}
+
+	_, model, _ := strings.Cut(name, "/")
+	hint := fmt.Errorf("model %q is not available locally, run `seaq model pull %s` first", name, name)
+
+	opts, err := llm.GetOllamaOptions()
+	if err != nil {
+		return err
+	}
+	if !opts.AutoPull {
+		return hint
+	}
+
+	pull := false
+	err = huh.NewConfirm().
+		Title(fmt.Sprintf("Model %q is not available locally. Pull it now?", name)).
+		Value(&pull).
+		Run()
+	if err != nil {
+		return err
+	}
+	if !pull {
+		return hint
+	}
+
+	return pullRun(ctx, os.Stderr, pullOptions{model: model})
+}
+
+// newProgressPrinter returns a function that renders pull progress as a
+// single, continuously updated line on w.
+func newProgressPrinter(w io.Writer) api.PullProgressFunc {
+	var lastStatus string
+
+	return func(res api.ProgressResponse) error {
+		// finish the previous line when the status changes
+		if lastStatus != "" && res.Status != lastStatus {
+			fmt.Fprintln(w)
+		}
+		lastStatus = res.Status
+
+		if res.Total <= 0 {
+			fmt.Fprintf(w, "\r%s", res.Status)
+			return nil
+		}
+
+		fmt.Fprintf(w, "\r%s %s %s/%s",
+			res.Status,
+			renderProgressBar(res.Completed, res.Total, progressBarWidth),
+			formatBytes(res.Completed),
+			formatBytes(res.Total),
+		)
+		return nil
+	}
+}
+
+// renderProgressBar renders a text progress bar of the given width,
+// followed by the completion percentage.
+func renderProgressBar(completed, total int64, width int) string {
+	if total <= 0 || width <= 0 {
+		return ""
+	}
+
+	ratio := min(max(float64(completed)/float64(total), 0), 1)
+	filled := int(ratio * float64(width))
+
+	return fmt.Sprintf("[%s%s] %3.0f%%",
+		strings.Repeat("=", filled),
+		strings.Repeat(" ", width-filled),
+		ratio*100,
+	)
+}
+
+// formatBytes formats a byte count using binary units (e.g. 1.5 GiB).
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+
+	div, exp := int64(unit), 0
+	for v := n / unit; v >= unit; v /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
@@ -5,10 +5,12 @@ import (
 	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/nt54hamnghi/seaq/cmd/flag"
 	"github.com/nt54hamnghi/seaq/pkg/config"
 	"github.com/nt54hamnghi/seaq/pkg/github"
+	"github.com/nt54hamnghi/seaq/pkg/remote"
 	"github.com/nt54hamnghi/seaq/pkg/util/log"
 	"github.com/spf13/afero"
 	"github.com/spf13/cobra"
@@ -16,11 +18,14 @@ import (
 )
 
 type addOptions struct {
-	remote      flag.URL
+	remote      string
 	configFile  flag.FilePath
 	patternName string
 }
 
+// completionCacheAge is how long remote pattern listings are cached for completion.
+const completionCacheAge = 5 * time.Minute
+
 var fs = afero.Afero{
 	Fs: afero.NewOsFs(),
 }
@@ -31,7 +36,7 @@ func newAddCmd() *cobra.Command {
 
 	cmd := &cobra.Command{
 		Use:               "add [pattern-name]",
-		Short:             "Add a pattern from a remote GitHub repository",
+		Short:             "Add a pattern from a remote pattern source",
 		Args:              cobra.ExactArgs(1),
 		ValidArgsFunction: completeAddPatternArgs,
 		SilenceUsage:      true,
@@ -48,49 +53,75 @@ func newAddCmd() *cobra.Command {
 	flags := cmd.Flags()
 	flags.SortFlags = false
 
-	flags.VarP(&opts.remote, "remote", "r", "remote GitHub repository")
+	flags.StringVarP(&opts.remote, "remote", "r", "", "remote pattern source: GitHub or git URL, archive or local directory")
 	config.AddConfigFlag(cmd, &opts.configFile)
 
 	return cmd
 }
 
 func (opts *addOptions) parse(_ *cobra.Command, args []string) error {
+	if err := config.ValidatePatternName(args[0]); err != nil {
+		return err
+	}
 	opts.patternName = args[0]
 	return nil
 }
 
 func addRun(ctx context.Context, opts addOptions) error {
 	remoteURL := viper.GetString("pattern.remote")
-	repo, err := github.ParseRepositoryURL(remoteURL)
+	source, err := remote.Open(remoteURL)
 	if err != nil {
-		return fmt.Errorf("parsing repository URL: %w", err)
+		return err
 	}
 
 	// download the pattern content
 	log.Info("Downloading pattern", "pattern", opts.patternName)
-	content, err := repo.DownloadPattern(ctx, opts.patternName)
+	content, err := source.Download(ctx, opts.patternName)
 	if err != nil {
 		return fmt.Errorf("downloading pattern: %w", err)
 	}
 
+	revision, err := source.Revision(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving remote revision: %w", err)
+	}
+
 	patternRepo := viper.GetString("pattern.repo")
 	if patternRepo == "" {
 		return fmt.Errorf("unexpected: pattern repository is not set")
 	}
 
-	// create directory for the requested pattern if not exists
-	patternDir := filepath.Join(patternRepo, opts.patternName)
+	// create directory for the requested pattern if not exists,
+	// including the parent directories of nested patterns
+	patternDir, err := config.PatternDir(patternRepo, opts.patternName)
+	if err != nil {
+		return err
+	}
 	if err := fs.MkdirAll(patternDir, 0o755); err != nil {
 		return fmt.Errorf("creating %s pattern directory: %w", opts.patternName, err)
 	}
 
 	// write the pattern content to the file if it doesn't exist
-	patternFile := filepath.Join(patternDir, "system.md")
+	patternFile := filepath.Join(patternDir, config.PatternFile)
 	log.Info("Writing pattern", "pattern", opts.patternName, "file", patternFile)
 	if err := fs.SafeWriteReader(patternFile, strings.NewReader(content)); err != nil {
 		return fmt.Errorf("writing %s pattern file: %w", opts.patternName, err)
 	}
 
+	// track the upstream version so that `pattern sync` can update it later
+	manifest, err := config.LoadManifest(patternRepo)
+	if err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+	manifest.Remote = remoteURL
+	manifest.Patterns[opts.patternName] = config.ManifestEntry{
+		SHA:      github.BlobSHA([]byte(content)),
+		Revision: revision,
+	}
+	if err := manifest.Save(patternRepo); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
 	return nil
 }
 
@@ -100,16 +131,20 @@ func completeAddPatternArgs(cmd *cobra.Command, args []string, _ string) ([]stri
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	remote := viper.GetString("pattern.remote")
-	repo, err := github.ParseRepositoryURL(remote)
+	// completion runs on every TAB, so a slightly stale listing is fine
+	source, err := remote.Open(viper.GetString("pattern.remote"), remote.WithListingCache(completionCacheAge))
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	patterns, err := repo.GetPatternNames(cmd.Context())
+	patterns, err := source.Patterns(cmd.Context())
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	return patterns, cobra.ShellCompDirectiveNoFileComp
+	names := make([]string, len(patterns))
+	for i, p := range patterns {
+		names[i] = p.Name
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
 }
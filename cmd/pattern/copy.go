@@ -0,0 +1,83 @@
+package pattern
+
+import (
+	"fmt"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type copyOptions struct {
+	configFile flag.FilePath
+	src        string
+	dst        string
+}
+
+func newCopyCmd() *cobra.Command {
+	var opts copyOptions
+
+	cmd := &cobra.Command{
+		Use:   "copy [source-pattern] [new-pattern]",
+		Short: "Copy a pattern into the pattern repository",
+		Long: `Copy a pattern into the pattern repository, to customize it.
+
+The source pattern can come from any pattern source, and can be qualified with its source,
+e.g. "team:review". Without a new name, the copy keeps the name of the source pattern
+and shadows it.`,
+		Aliases:           []string{"cp"},
+		Args:              cobra.RangeArgs(1, 2),
+		ValidArgsFunction: completeCopyArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+
+			repo, resolved, err := config.FindPattern(opts.src, "")
+			if err != nil {
+				return err
+			}
+
+			file, err := config.CopyPattern(repo, resolved, config.Repo(), opts.dst)
+			if err != nil {
+				return err
+			}
+
+			cmd.Printf("Copied pattern '%s' from %s to %s\n", resolved, repo.Name, file)
+			return nil
+		},
+	}
+
+	// set up flags
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func (opts *copyOptions) parse(_ *cobra.Command, args []string) error {
+	opts.src = args[0]
+
+	if len(args) > 1 {
+		opts.dst = args[1]
+	} else {
+		_, opts.dst = config.SplitPatternName(opts.src)
+	}
+
+	if err := config.ValidatePatternName(opts.dst); err != nil {
+		return err
+	}
+	if config.Repo() == "" {
+		return fmt.Errorf("unexpected: pattern repository is not set")
+	}
+	return nil
+}
+
+// completeCopyArgs completes the source pattern only, the new name is free-form.
+func completeCopyArgs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return CompletePatternArgs(cmd, args, toComplete)
+}
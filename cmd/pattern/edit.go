@@ -0,0 +1,73 @@
+package pattern
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/env"
+	"github.com/spf13/cobra"
+)
+
+type editOptions struct {
+	configFile flag.FilePath
+}
+
+func newEditCmd() *cobra.Command {
+	var opts editOptions
+
+	cmd := &cobra.Command{
+		Use:   "edit [pattern-name]",
+		Short: "Edit a pattern in $EDITOR",
+		Long: `Open the system.md of a pattern in $VISUAL or $EDITOR, falling back to vi.
+
+Without a name, the default pattern is edited. The name can be qualified with a source,
+e.g. "team:review", to edit a pattern shadowed by another one.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: CompletePatternArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := config.Pattern()
+			if len(args) > 0 {
+				name = args[0]
+			}
+
+			repo, resolved, err := config.FindPattern(name, "")
+			if err != nil {
+				return err
+			}
+			if repo.Builtin() {
+				return fmt.Errorf("built-in pattern %s is read-only, run `seaq pattern eject %s` to customize it", resolved, resolved)
+			}
+
+			return openEditor(cmd.Context(), repo.PromptPath(resolved))
+		},
+	}
+
+	// set up flags
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+// openEditor opens a file in the user's editor and waits for it to exit.
+func openEditor(ctx context.Context, file string) error {
+	// the editor may come with arguments, e.g. "code --wait"
+	args := strings.Fields(env.Editor())
+	args = append(args, file)
+
+	cmd := exec.CommandContext(ctx, args[0], args[1:]...) // nolint: gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running editor %s: %w", args[0], err)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type ejectOptions struct {
+	configFile flag.FilePath
+	all        bool
+	names      []string
+}
+
+func newEjectCmd() *cobra.Command {
+	var opts ejectOptions
+
+	cmd := &cobra.Command{
+		Use:   "eject [pattern-name]...",
+		Short: "Copy built-in patterns into the pattern repository",
+		Long: `Copy built-in patterns into the pattern repository, to customize them.
+
+Ejected patterns shadow the built-in ones with the same name.`,
+		ValidArgsFunction: completeBuiltinArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+
+			builtin := config.BuiltinRepo()
+			for _, name := range opts.names {
+				file, err := config.CopyPattern(builtin, name, config.Repo(), name)
+				if err != nil {
+					return fmt.Errorf("ejecting pattern %s: %w", name, err)
+				}
+				cmd.Printf("Ejected pattern '%s' to %s\n", name, file)
+			}
+
+			return nil
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.BoolVarP(&opts.all, "all", "a", false, "eject all built-in patterns")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func (opts *ejectOptions) parse(_ *cobra.Command, args []string) error {
+	if config.Repo() == "" {
+		return errors.New("pattern repository is not set, set pattern.repo in the config file")
+	}
+
+	switch {
+	case opts.all && len(args) > 0:
+		return errors.New("--all and pattern names are mutually exclusive")
+	case opts.all:
+		names, err := config.BuiltinRepo().List()
+		if err != nil {
+			return err
+		}
+		opts.names = names
+	case len(args) == 0:
+		return errors.New("requires at least one pattern name, or --all")
+	default:
+		for _, arg := range args {
+			// accept names qualified with the built-in source, as shown by `pattern list`
+			source, name := config.SplitPatternName(arg)
+			if source != "" && source != config.BuiltinSource {
+				return fmt.Errorf("only built-in patterns can be ejected, got %s", arg)
+			}
+			opts.names = append(opts.names, name)
+		}
+	}
+
+	return nil
+}
+
+func completeBuiltinArgs(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
+	names, err := config.BuiltinRepo().List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,72 @@
+package pattern
+
+import (
+	"errors"
+	"os"
+
+	"github.com/nt54hamnghi/seaq/cmd/compose"
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/cmd/flaggroup"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+type exportOptions struct {
+	configFile flag.FilePath
+	output     flaggroup.Output
+}
+
+func newExportCmd() *cobra.Command {
+	var opts exportOptions
+
+	cmd := &cobra.Command{
+		Use:   "export <pattern-name>...",
+		Short: "Export patterns to a bundle",
+		Long: `Export patterns to a tar.gz bundle, to share them through any channel.
+
+The bundle holds the files of each pattern, including their front matter and pattern.yaml,
+and the upstream versions of the patterns downloaded from the remote. Names can be qualified
+with a source, e.g. "team:review". Import the bundle with ` + "`seaq pattern import`" + `,
+or use it as a remote with ` + "`seaq pattern add --remote bundle.tar.gz`" + `.`,
+		Example: `  seaq pattern export summarize extract_wisdom -o bundle.tar.gz
+  seaq pattern export team:review | ssh host seaq pattern import -`,
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: CompletePatternArgs,
+		SilenceUsage:      true,
+		PreRunE: compose.SequenceE(
+			config.Init,
+			flaggroup.ValidateGroups(&opts.output),
+		),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// a bundle is binary, it would garble the terminal
+			if opts.output.File == "" && fileio.IsTerminal(os.Stdout) {
+				return errors.New("refusing to write a bundle to a terminal, use --output or redirect the output")
+			}
+
+			dest, err := opts.output.Writer()
+			if err != nil {
+				return err
+			}
+			defer dest.Close()
+
+			exported, err := config.ExportBundle(dest, args)
+			if err != nil {
+				return err
+			}
+
+			// the bundle may be written to stdout, so report on stderr
+			log.Info("Exported patterns", "count", len(exported), "patterns", exported)
+			return nil
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flaggroup.InitGroups(cmd, &opts.output)
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
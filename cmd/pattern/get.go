@@ -2,6 +2,7 @@ package pattern
 
 import (
 	"fmt"
+	"io"
 	"text/tabwriter"
 
 	"github.com/nt54hamnghi/seaq/cmd/flag"
@@ -11,6 +12,8 @@ import (
 
 type getOptions struct {
 	configFile flag.FilePath
+	source     string
+	resolved   bool
 }
 
 func newGetCmd() *cobra.Command {
@@ -24,18 +27,92 @@ func newGetCmd() *cobra.Command {
 		SilenceUsage: true,
 		PreRunE:      config.Init,
 		RunE: func(cmd *cobra.Command, _ []string) error {
-			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
-			defer w.Flush()
-
-			fmt.Fprintf(w, "Pattern:\t%s\n", config.Pattern())
-			fmt.Fprintf(w, "Repo:\t%s\n", config.Repo())
-
-			return nil
+			return getRun(cmd.OutOrStdout(), opts)
 		},
 	}
 
 	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVarP(&opts.source, "source", "s", "", "look up the pattern in this source only")
+	flags.BoolVar(&opts.resolved, "resolved", false, "print the prompt of the pattern, with its includes resolved")
 	config.AddConfigFlag(cmd, &opts.configFile)
 
+	err := cmd.RegisterFlagCompletionFunc("source", completeSourceArgs)
+	if err != nil {
+		cobra.CheckErr(err)
+	}
+
 	return cmd
 }
+
+func getRun(out io.Writer, opts getOptions) error {
+	name := config.Pattern()
+	repo, resolved, err := config.FindPattern(name, opts.source)
+	if err != nil {
+		return err
+	}
+
+	if opts.resolved {
+		return printResolvedPrompt(out, repo, resolved)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer w.Flush()
+
+	fmt.Fprintf(w, "Pattern:\t%s\n", resolved)
+	fmt.Fprintf(w, "Source:\t%s\n", repo.Name)
+	if repo.Path == "" {
+		// built-in patterns are embedded, so they have no repository
+		fmt.Fprintf(w, "Path:\t%s\n", repo.PromptPath(resolved))
+		return nil
+	}
+	fmt.Fprintf(w, "Repo:\t%s\n", repo.Path)
+	fmt.Fprintf(w, "Path:\t%s\n", repo.PromptPath(resolved))
+
+	// patterns downloaded from a remote are tracked in the manifest of their repository
+	manifest, err := config.LoadManifest(repo.Path)
+	if err != nil {
+		return err
+	}
+	if entry, ok := manifest.Patterns[resolved]; ok {
+		fmt.Fprintf(w, "Remote:\t%s\n", manifest.Remote)
+		if entry.Revision != "" {
+			fmt.Fprintf(w, "Revision:\t%s\n", entry.Revision)
+		}
+	}
+
+	return nil
+}
+
+// printResolvedPrompt prints the final prompt of a pattern, as sent to models,
+// except for its variables which are only known when it runs.
+func printResolvedPrompt(out io.Writer, repo config.PatternRepo, name string) error {
+	p, err := repo.ReadPattern(name)
+	if err != nil {
+		return err
+	}
+	if p, err = config.ResolveIncludes(p); err != nil {
+		return err
+	}
+
+	_, err = io.WriteString(out, p.Prompt)
+	return err
+}
+
+func completeSourceArgs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if err := config.EnsureConfig(cmd, args); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	repos, err := config.PatternRepos()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, len(repos))
+	for i, r := range repos {
+		names[i] = r.Name
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,102 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
+	"github.com/spf13/cobra"
+)
+
+type importOptions struct {
+	configFile flag.FilePath
+	conflict   string
+	bundle     string
+}
+
+func newImportCmd() *cobra.Command {
+	var opts importOptions
+
+	cmd := &cobra.Command{
+		Use:   "import <bundle>",
+		Short: "Import patterns from a bundle",
+		Long: `Import the patterns of a tar.gz bundle, created with ` + "`seaq pattern export`" + `,
+into the pattern repository. Use "-" to read the bundle from stdin.
+
+Patterns already in the repository are skipped by default. Use --conflict=overwrite to replace
+them, or --conflict=rename to import them under a new name, e.g. summarize_2.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE:      config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return importRun(cmd.OutOrStdout(), opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVar(&opts.conflict, "conflict", string(config.ConflictSkip), "how to import existing patterns: skip, overwrite or rename")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	err := cmd.RegisterFlagCompletionFunc("conflict", cobra.FixedCompletions(
+		[]string{string(config.ConflictSkip), string(config.ConflictOverwrite), string(config.ConflictRename)},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	if err != nil {
+		cobra.CheckErr(err)
+	}
+
+	return cmd
+}
+
+func (opts *importOptions) parse(_ *cobra.Command, args []string) error {
+	if config.Repo() == "" {
+		return errors.New("pattern repository is not set, set pattern.repo in the config file")
+	}
+	if args[0] == "-" && fileio.IsTerminal(os.Stdin) {
+		return errors.New("no bundle piped to stdin")
+	}
+	opts.bundle = args[0]
+	return config.ConflictPolicy(opts.conflict).Validate()
+}
+
+func importRun(out io.Writer, opts importOptions) error {
+	var r io.Reader = os.Stdin
+	if opts.bundle != "-" {
+		f, err := os.Open(opts.bundle)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	results, err := config.ImportBundle(r, config.Repo(), config.ConflictPolicy(opts.conflict))
+	// report what has been imported, even if some patterns failed
+	printImportResults(out, results)
+	return err
+}
+
+func printImportResults(out io.Writer, results []config.ImportResult) {
+	if len(results) == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+
+	const format = "%s\t%s\t%s\n"
+	fmt.Fprintf(w, format, "NAME", "STATUS", "IMPORTED AS")
+	for _, r := range results {
+		fmt.Fprintf(w, format, r.Name, r.Status, r.ImportedAs)
+	}
+}
@@ -1,7 +1,12 @@
 package pattern
 
 import (
+	"encoding/json"
 	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/nt54hamnghi/seaq/cmd/flag"
 	"github.com/nt54hamnghi/seaq/pkg/config"
@@ -10,6 +15,9 @@ import (
 
 type listOptions struct {
 	configFile flag.FilePath
+	long       bool
+	json       bool
+	tags       []string
 }
 
 func newListCmd() *cobra.Command {
@@ -23,21 +31,127 @@ func newListCmd() *cobra.Command {
 		SilenceUsage: true,
 		PreRunE:      config.Init,
 		RunE: func(cmd *cobra.Command, args []string) error { // nolint: revive
-			pats, err := config.ListPatterns()
-			if err != nil {
-				return err
-			}
-
-			for _, p := range pats {
-				fmt.Println(p)
-			}
-
-			return nil
+			return listRun(cmd.OutOrStdout(), opts)
 		},
 	}
 
 	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.BoolVarP(&opts.long, "long", "l", false, "show the source, status and metadata of patterns")
+	flags.BoolVar(&opts.json, "json", false, "print patterns as JSON")
+	flags.StringSliceVarP(&opts.tags, "tag", "t", nil, "only list patterns with all of these tags")
 	config.AddConfigFlag(cmd, &opts.configFile)
 
+	cmd.MarkFlagsMutuallyExclusive("long", "json")
+
 	return cmd
 }
+
+func listRun(out io.Writer, opts listOptions) error {
+	entries, err := config.ListPatternEntries()
+	if err != nil {
+		return err
+	}
+
+	entries = filterByTags(entries, opts.tags)
+
+	switch {
+	case opts.json:
+		return printEntriesJSON(out, entries)
+	case opts.long:
+		printEntriesLong(out, entries)
+	default:
+		printEntries(out, entries)
+	}
+
+	return nil
+}
+
+// filterByTags keeps the entries having all tags.
+func filterByTags(entries []config.PatternEntry, tags []string) []config.PatternEntry {
+	if len(tags) == 0 {
+		return entries
+	}
+
+	filtered := make([]config.PatternEntry, 0, len(entries))
+	for _, e := range entries {
+		hasAll := true
+		for _, t := range tags {
+			if !e.Meta.HasTag(t) {
+				hasAll = false
+				break
+			}
+		}
+		if hasAll {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered
+}
+
+func entryStatus(e config.PatternEntry) string {
+	if e.Shadowed() {
+		return "shadowed by " + e.ShadowedBy
+	}
+	return "active"
+}
+
+// printEntries prints one pattern name per line, so that the output can be piped,
+// e.g. to fzf. Shadowed patterns are printed with the qualified name they're reachable with.
+func printEntries(out io.Writer, entries []config.PatternEntry) {
+	for _, e := range entries {
+		if e.Shadowed() {
+			fmt.Fprintln(out, e.Source+":"+e.Name)
+			continue
+		}
+		fmt.Fprintln(out, e.Name)
+	}
+}
+
+func printEntriesLong(out io.Writer, entries []config.PatternEntry) {
+	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+
+	const format = "%s\t%s\t%s\t%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, format, "NAME", "SOURCE", "STATUS", "TAGS", "MODEL", "TEMPERATURE", "DESCRIPTION")
+	for _, e := range entries {
+		temperature := ""
+		if e.Meta.Temperature != nil {
+			temperature = strconv.FormatFloat(*e.Meta.Temperature, 'g', -1, 64)
+		}
+		fmt.Fprintf(w, format,
+			e.Name,
+			e.Source,
+			entryStatus(e),
+			strings.Join(e.Meta.Tags, ","),
+			e.Meta.Model,
+			temperature,
+			e.Meta.Description,
+		)
+	}
+}
+
+// entryJSON is the JSON representation of a pattern entry.
+type entryJSON struct {
+	Name       string `json:"name"`
+	Source     string `json:"source"`
+	ShadowedBy string `json:"shadowed_by,omitempty"`
+	config.PatternMeta
+}
+
+func printEntriesJSON(out io.Writer, entries []config.PatternEntry) error {
+	items := make([]entryJSON, len(entries))
+	for i, e := range entries {
+		items[i] = entryJSON{
+			Name:        e.Name,
+			Source:      e.Source,
+			ShadowedBy:  e.ShadowedBy,
+			PatternMeta: e.Meta,
+		}
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(items)
+}
@@ -0,0 +1,90 @@
+package pattern
+
+import (
+	"fmt"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type newOptions struct {
+	configFile  flag.FilePath
+	template    flag.FilePath
+	patternName string
+	description string
+	tags        []string
+	edit        bool
+}
+
+func newNewCmd() *cobra.Command {
+	var opts newOptions
+
+	cmd := &cobra.Command{
+		Use:   "new [pattern-name]",
+		Short: "Create a pattern from a template",
+		Long: `Create a pattern in the pattern repository from a template.
+
+The template is a Go template of system.md, where {{ .Name }}, {{ .Description }}
+and {{ .Tags }} are set from the arguments and flags.`,
+		Args:         cobra.ExactArgs(1),
+		SilenceUsage: true,
+		PreRunE:      config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+
+			content := ""
+			if opts.template != "" {
+				data, err := fs.ReadFile(opts.template.String())
+				if err != nil {
+					return err
+				}
+				content = string(data)
+			}
+
+			content, err := config.RenderPatternTemplate(content, config.PatternTemplateData{
+				Name:        opts.patternName,
+				Description: opts.description,
+				Tags:        opts.tags,
+			})
+			if err != nil {
+				return err
+			}
+
+			file, err := config.CreatePattern(config.Repo(), opts.patternName, content)
+			if err != nil {
+				return err
+			}
+			cmd.Printf("Created pattern '%s' at %s\n", opts.patternName, file)
+
+			if opts.edit {
+				return openEditor(cmd.Context(), file)
+			}
+			return nil
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVarP(&opts.description, "description", "d", "", "description of the pattern")
+	flags.StringSliceVarP(&opts.tags, "tag", "t", nil, "tags of the pattern")
+	flags.Var(&opts.template, "template", "template file of system.md, instead of the default one")
+	flags.BoolVarP(&opts.edit, "edit", "e", false, "open the pattern in $EDITOR once created")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func (opts *newOptions) parse(_ *cobra.Command, args []string) error {
+	if err := config.ValidatePatternName(args[0]); err != nil {
+		return err
+	}
+	if config.Repo() == "" {
+		return fmt.Errorf("unexpected: pattern repository is not set")
+	}
+	opts.patternName = args[0]
+	return nil
+}
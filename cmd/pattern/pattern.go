@@ -24,6 +24,16 @@ func NewPatternCmd() *cobra.Command {
 		newListCmd(),
 		newSetCmd(),
 		newAddCmd(),
+		newSyncCmd(),
+		newSearchCmd(),
+		newNewCmd(),
+		newEditCmd(),
+		newCopyCmd(),
+		newRemoveCmd(),
+		newTestCmd(),
+		newEjectCmd(),
+		newExportCmd(),
+		newImportCmd(),
 	)
 
 	return cmd
@@ -36,9 +46,18 @@ func CompletePatternArgs(cmd *cobra.Command, args []string, _ string) ([]string,
 		return nil, cobra.ShellCompDirectiveError
 	}
 
-	patterns, err := config.ListPatterns()
+	entries, err := config.ListPatternEntries()
 	if err != nil {
 		return nil, cobra.ShellCompDirectiveError
 	}
+
+	// shadowed patterns can only be used when qualified with their source
+	patterns := make([]string, len(entries))
+	for i, e := range entries {
+		patterns[i] = e.Name
+		if e.Shadowed() {
+			patterns[i] = e.Source + ":" + e.Name
+		}
+	}
 	return patterns, cobra.ShellCompDirectiveNoFileComp
 }
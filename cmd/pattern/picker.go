@@ -0,0 +1,103 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/huh"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
+)
+
+// maxSuggestions is the number of suggestions shown when a picker can't be used.
+const maxSuggestions = 5
+
+// ResolvePattern returns the pattern a possibly partial name refers to.
+//
+// An existing pattern is returned as is. Otherwise, the patterns matching the name
+// are offered in an interactive picker, or suggested in the error if there is no terminal.
+func ResolvePattern(name string) (string, error) {
+	if config.HasPattern(name) {
+		return name, nil
+	}
+	return PickPattern(name)
+}
+
+// PickPattern lets the user pick a pattern interactively among those matching a query,
+// or among all patterns if the query is empty.
+// The picker is shown on stderr, so that it works when stdout is redirected.
+func PickPattern(query string) (string, error) {
+	var results []config.SearchResult
+	if query == "" {
+		entries, err := config.ListPatternEntries()
+		if err != nil {
+			return "", err
+		}
+		for _, e := range entries {
+			if !e.Shadowed() {
+				results = append(results, config.SearchResult{Name: e.Name, Source: e.Source, Description: e.Meta.Description})
+			}
+		}
+	} else {
+		var err error
+		if results, err = config.SearchPatterns(query); err != nil {
+			return "", err
+		}
+	}
+
+	if len(results) == 0 {
+		return "", &config.Unsupported{Type: "pattern", Key: query}
+	}
+
+	if !fileio.IsTerminal(os.Stderr) {
+		names := make([]string, 0, maxSuggestions)
+		for _, r := range results[:min(len(results), maxSuggestions)] {
+			names = append(names, r.Name)
+		}
+		return "", fmt.Errorf("%w, did you mean: %s?",
+			&config.Unsupported{Type: "pattern", Key: query},
+			strings.Join(names, ", "),
+		)
+	}
+
+	options := make([]huh.Option[string], len(results))
+	for i, r := range results {
+		label := r.Name
+		if r.Description != "" {
+			label += " - " + r.Description
+		}
+		options[i] = huh.NewOption(label, r.Name)
+	}
+
+	title := "Pick a pattern"
+	if query != "" {
+		title = fmt.Sprintf("Patterns matching %q", query)
+	}
+
+	var picked string
+	err := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title(title).
+				Options(options...).
+				Filtering(true).
+				Height(min(len(options)+2, 15)).
+				Value(&picked),
+		),
+	).
+		WithOutput(os.Stderr).
+		// stdin may be piped input, so read keys from the terminal
+		WithProgramOptions(tea.WithInputTTY()).
+		Run()
+	if err != nil {
+		if errors.Is(err, huh.ErrUserAborted) {
+			return "", errors.New("no pattern picked")
+		}
+		return "", err
+	}
+
+	return picked, nil
+}
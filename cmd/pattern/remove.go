@@ -0,0 +1,110 @@
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/huh"
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/spf13/cobra"
+)
+
+type removeOptions struct {
+	configFile flag.FilePath
+	yes        bool
+}
+
+func newRemoveCmd() *cobra.Command {
+	var opts removeOptions
+
+	cmd := &cobra.Command{
+		Use:   "remove [pattern-name]...",
+		Short: "Remove patterns from the pattern repository",
+		Long: `Remove patterns from the pattern repository, after confirmation.
+
+Only patterns of the pattern repository set by pattern.repo can be removed,
+patterns of shared and project repositories are left untouched.`,
+		Aliases:           []string{"rm"},
+		Args:              cobra.MinimumNArgs(1),
+		ValidArgsFunction: completeRemoveArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			repo := config.Repo()
+			for _, name := range args {
+				if err := config.ValidatePatternName(name); err != nil {
+					return err
+				}
+				if !config.NewPatternRepo(config.PersonalSource, repo).Has(name) {
+					return &config.Unsupported{Type: "pattern", Key: name}
+				}
+			}
+
+			if !opts.yes {
+				confirmed, err := confirmRemove(args)
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					return nil
+				}
+			}
+
+			for _, name := range args {
+				if err := config.RemovePattern(repo, name); err != nil {
+					return fmt.Errorf("removing pattern %s: %w", name, err)
+				}
+				if name == config.Pattern() {
+					log.Warn("removed the default pattern, set another one with `seaq pattern set`", "pattern", name)
+				}
+				cmd.Printf("Removed pattern '%s'\n", name)
+			}
+
+			return nil
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.BoolVarP(&opts.yes, "yes", "y", false, "remove without confirmation")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func confirmRemove(names []string) (bool, error) {
+	if !fileio.IsTerminal(os.Stdin) {
+		return false, errors.New("cannot confirm without a terminal, use --yes to remove anyway")
+	}
+
+	confirmed := false
+	err := huh.NewConfirm().
+		Title(fmt.Sprintf("Remove %s?", strings.Join(names, ", "))).
+		Affirmative("Remove").
+		Negative("Cancel").
+		Value(&confirmed).
+		Run()
+	if err != nil {
+		return false, err
+	}
+	return confirmed, nil
+}
+
+// completeRemoveArgs completes the patterns of the pattern repository.
+func completeRemoveArgs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	if err := config.EnsureConfig(cmd, args); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names, err := config.ListPatternsInRepo(config.Repo())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
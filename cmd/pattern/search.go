@@ -0,0 +1,116 @@
+package pattern
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/remote"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// remoteSource is the source of remote patterns in search results.
+const remoteSource = "remote"
+
+type searchOptions struct {
+	configFile    flag.FilePath
+	query         string
+	includeRemote bool
+	limit         int
+}
+
+func newSearchCmd() *cobra.Command {
+	var opts searchOptions
+
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search patterns by name, metadata and content",
+		Long: `Search patterns by name, metadata and content.
+
+Names are fuzzy matched, e.g. "exwis" matches "extract_wisdom".
+The words of the query are also matched against the tags, description and prompt of patterns.`,
+		Args:         cobra.MinimumNArgs(1),
+		SilenceUsage: true,
+		PreRunE:      config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			opts.query = strings.Join(args, " ")
+			return searchRun(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.BoolVar(&opts.includeRemote, "include-remote", false, "also search the names of patterns in pattern.remote")
+	flags.IntVarP(&opts.limit, "limit", "n", 10, "maximum number of results, 0 for no limit")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func searchRun(ctx context.Context, out io.Writer, opts searchOptions) error {
+	results, err := config.SearchPatterns(opts.query)
+	if err != nil {
+		return err
+	}
+
+	if opts.includeRemote {
+		remoteResults, err := searchRemote(ctx, opts.query, results)
+		if err != nil {
+			return err
+		}
+		results = append(results, remoteResults...)
+		config.SortSearchResults(results)
+	}
+
+	if opts.limit > 0 && len(results) > opts.limit {
+		results = results[:opts.limit]
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+
+	const format = "%s\t%s\t%s\n"
+	fmt.Fprintf(w, format, "NAME", "SOURCE", "DESCRIPTION")
+	for _, r := range results {
+		fmt.Fprintf(w, format, r.Name, r.Source, r.Description)
+	}
+
+	return nil
+}
+
+// searchRemote ranks the remote patterns that are not available locally by name.
+// Their content is not searched, as it would take a request per pattern.
+func searchRemote(ctx context.Context, query string, local []config.SearchResult) ([]config.SearchResult, error) {
+	source, err := remote.Open(viper.GetString("pattern.remote"), remote.WithListingCache(completionCacheAge))
+	if err != nil {
+		return nil, err
+	}
+
+	patterns, err := source.Patterns(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing remote patterns: %w", err)
+	}
+
+	found := make(map[string]bool, len(local))
+	for _, r := range local {
+		found[r.Name] = true
+	}
+
+	var results []config.SearchResult
+	for _, p := range patterns {
+		if found[p.Name] {
+			continue
+		}
+		if score := config.ScorePattern(query, config.PatternContent{Name: p.Name}); score > 0 {
+			results = append(results, config.SearchResult{Name: p.Name, Source: remoteSource, Score: score})
+		}
+	}
+
+	return results, nil
+}
@@ -15,15 +15,26 @@ func newSetCmd() *cobra.Command {
 	var opts setOptions
 
 	cmd := &cobra.Command{
-		Use:               "set",
-		Short:             "Set the default pattern",
+		Use:   "set [pattern-name]",
+		Short: "Set the default pattern",
+		Long: `Set the default pattern.
+
+Without a name, or with a partial name, the pattern is picked interactively.`,
 		Aliases:           []string{"use"},
-		Args:              cobra.ExactArgs(1),
+		Args:              cobra.MaximumNArgs(1),
 		SilenceUsage:      true,
 		ValidArgsFunction: CompletePatternArgs,
 		PreRunE:           config.Init,
 		RunE: func(cmd *cobra.Command, args []string) error { // nolint: revive
-			name := args[0]
+			var query string
+			if len(args) > 0 {
+				query = args[0]
+			}
+
+			name, err := ResolvePattern(query)
+			if err != nil {
+				return err
+			}
 
 			if err := config.UsePattern(name); err != nil {
 				return err
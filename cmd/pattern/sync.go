@@ -0,0 +1,390 @@
+package pattern
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/github"
+	"github.com/nt54hamnghi/seaq/pkg/remote"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type syncOptions struct {
+	remote       string
+	configFile   flag.FilePath
+	dryRun       bool
+	diff         bool
+	switchRemote bool
+	patterns     []string
+}
+
+func newSyncCmd() *cobra.Command {
+	var opts syncOptions
+
+	cmd := &cobra.Command{
+		Use:   "sync [pattern-name]...",
+		Short: "Download and update patterns from the remote repository",
+		Long: `Download and update patterns from the remote repository.
+
+All patterns of the remote are synced, unless some are given as arguments.
+Patterns edited locally are left alone, use --diff to see how they differ from upstream.
+
+Syncing from a different remote than the one patterns were downloaded from
+requires --switch-remote. Patterns the new remote doesn't have are then kept, but no longer synced.`,
+		ValidArgsFunction: completeAddPatternArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return syncRun(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVarP(&opts.remote, "remote", "r", "", "remote pattern source: GitHub or git URL, archive or local directory")
+	flags.BoolVar(&opts.dryRun, "dry-run", false, "show what would change without writing anything")
+	flags.BoolVar(&opts.diff, "diff", false, "show the diff of locally modified patterns against upstream")
+	flags.BoolVar(&opts.switchRemote, "switch-remote", false, "sync from a different remote than the one patterns were downloaded from")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func (opts *syncOptions) parse(_ *cobra.Command, args []string) error {
+	for _, name := range args {
+		if err := config.ValidatePatternName(name); err != nil {
+			return err
+		}
+	}
+	opts.patterns = args
+	return nil
+}
+
+func syncRun(ctx context.Context, out io.Writer, opts syncOptions) error {
+	remoteURL := viper.GetString("pattern.remote")
+	source, err := remote.Open(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	patternRepo := config.Repo()
+	if patternRepo == "" {
+		return errors.New("unexpected: pattern repository is not set")
+	}
+
+	log.Info("Listing remote patterns", "remote", remoteURL)
+	patterns, err := source.Patterns(ctx)
+	if err != nil {
+		return fmt.Errorf("listing remote patterns: %w", err)
+	}
+
+	revision, err := source.Revision(ctx)
+	if err != nil {
+		return fmt.Errorf("resolving remote revision: %w", err)
+	}
+
+	upstream := make(map[string]string, len(patterns))
+	for _, p := range patterns {
+		upstream[p.Name] = p.SHA
+	}
+
+	manifest, err := config.LoadManifest(patternRepo)
+	if err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+
+	// patterns tracked from another remote would look removed upstream, and be deleted
+	if switched(manifest.Remote, remoteURL) {
+		if !opts.switchRemote {
+			return fmt.Errorf(
+				"patterns were downloaded from %s, not %s: use --switch-remote to sync from the new remote",
+				manifest.Remote, remoteURL,
+			)
+		}
+		log.Info("Switching remote", "from", manifest.Remote, "to", remoteURL)
+		manifest.SwitchRemote(remoteURL, upstream)
+	}
+
+	// only plan the selected patterns, the manifest is saved in full
+	planned := manifest
+	if len(opts.patterns) > 0 {
+		upstream, planned, err = selectPatterns(opts.patterns, upstream, manifest)
+		if err != nil {
+			return err
+		}
+	}
+
+	local, err := localBlobSHAs(patternRepo, upstream, planned)
+	if err != nil {
+		return err
+	}
+
+	items := config.PlanSync(upstream, local, planned)
+
+	if !opts.dryRun {
+		manifest.Remote = remoteURL
+		applyErr := applySync(ctx, source, revision, patternRepo, items, manifest)
+		// save what has been applied, even if some patterns failed
+		if err := manifest.Save(patternRepo); err != nil {
+			return errors.Join(applyErr, fmt.Errorf("writing manifest: %w", err))
+		}
+		if applyErr != nil {
+			return applyErr
+		}
+	}
+
+	if revision != "" {
+		fmt.Fprintf(out, "Remote %s at commit %s\n", remoteURL, revision)
+	}
+	printSyncReport(out, items)
+
+	if opts.diff {
+		return printSyncDiffs(ctx, out, source, patternRepo, items)
+	}
+
+	return nil
+}
+
+// switched reports whether a remote is different from the one the manifest tracks.
+// Pinning the same remote to another ref isn't a switch.
+func switched(tracked string, remoteURL string) bool {
+	if tracked == "" {
+		return false
+	}
+	trackedURL, _ := remote.SplitRef(tracked)
+	newURL, _ := remote.SplitRef(remoteURL)
+	return trackedURL != newURL
+}
+
+// selectPatterns restricts upstream patterns and the manifest to the given names.
+func selectPatterns(
+	names []string,
+	upstream map[string]string,
+	manifest config.Manifest,
+) (map[string]string, config.Manifest, error) {
+	selectedUpstream := make(map[string]string, len(names))
+	selected := config.Manifest{
+		Remote:   manifest.Remote,
+		Patterns: make(map[string]config.ManifestEntry, len(names)),
+	}
+
+	for _, name := range names {
+		sha, inUpstream := upstream[name]
+		entry, tracked := manifest.Patterns[name]
+		if !inUpstream && !tracked {
+			return nil, config.Manifest{}, fmt.Errorf("pattern %q not found in remote", name)
+		}
+		if inUpstream {
+			selectedUpstream[name] = sha
+		}
+		if tracked {
+			selected.Patterns[name] = entry
+		}
+	}
+
+	return selectedUpstream, selected, nil
+}
+
+// localBlobSHAs computes the blob SHA of local patterns that exist upstream or are tracked.
+func localBlobSHAs(
+	patternRepo string,
+	upstream map[string]string,
+	manifest config.Manifest,
+) (map[string]string, error) {
+	local := make(map[string]string)
+
+	add := func(name string) error {
+		path, err := config.PatternPath(patternRepo, name)
+		if err != nil {
+			return err
+		}
+
+		content, err := fs.ReadFile(path)
+		if err != nil {
+			exists, _ := fs.Exists(path)
+			if !exists {
+				return nil
+			}
+			return err
+		}
+
+		local[name] = github.BlobSHA(content)
+		return nil
+	}
+
+	for name := range upstream {
+		if err := add(name); err != nil {
+			return nil, err
+		}
+	}
+	for name := range manifest.Patterns {
+		if err := add(name); err != nil {
+			return nil, err
+		}
+	}
+
+	return local, nil
+}
+
+// applySync writes, updates and removes local patterns according to the plan,
+// and records their upstream version in the manifest.
+func applySync(
+	ctx context.Context,
+	source remote.PatternSource,
+	revision string,
+	patternRepo string,
+	items []config.SyncItem,
+	manifest config.Manifest,
+) error {
+	for _, item := range items {
+		switch item.Status {
+		case config.SyncAdded, config.SyncUpdated:
+			log.Info("Downloading pattern", "pattern", item.Name)
+			sha, err := writePattern(ctx, source, patternRepo, item.Name)
+			if err != nil {
+				return fmt.Errorf("syncing %s: %w", item.Name, err)
+			}
+			manifest.Patterns[item.Name] = config.ManifestEntry{SHA: sha, Revision: revision}
+		case config.SyncUnchanged:
+			manifest.Patterns[item.Name] = config.ManifestEntry{SHA: item.Upstream, Revision: revision}
+		case config.SyncRemoved:
+			if err := removePattern(patternRepo, item.Name); err != nil {
+				return fmt.Errorf("removing %s: %w", item.Name, err)
+			}
+			delete(manifest.Patterns, item.Name)
+		case config.SyncModified:
+			// local edits are left alone
+		}
+	}
+
+	return nil
+}
+
+// writePattern downloads a pattern and writes it to the repository, overwriting any existing file.
+// It returns the blob SHA of the written content.
+func writePattern(ctx context.Context, source remote.PatternSource, patternRepo string, name string) (string, error) {
+	content, err := source.Download(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	path, err := config.PatternPath(patternRepo, name)
+	if err != nil {
+		return "", err
+	}
+	if err := fs.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := fs.WriteFile(path, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+
+	return github.BlobSHA([]byte(content)), nil
+}
+
+// removePattern removes a pattern's system.md and its directory if it's left empty.
+func removePattern(patternRepo string, name string) error {
+	path, err := config.PatternPath(patternRepo, name)
+	if err != nil {
+		return err
+	}
+
+	if err := fs.Remove(path); err != nil {
+		if exists, _ := fs.Exists(path); exists {
+			return err
+		}
+	}
+
+	if empty, _ := fs.IsEmpty(filepath.Dir(path)); empty {
+		return fs.Remove(filepath.Dir(path))
+	}
+	return nil
+}
+
+func printSyncReport(out io.Writer, items []config.SyncItem) {
+	counts := make(map[config.SyncStatus]int)
+
+	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
+	for _, item := range items {
+		counts[item.Status]++
+		if item.Status == config.SyncUnchanged {
+			continue
+		}
+
+		status := string(item.Status)
+		if item.Status == config.SyncModified {
+			status = "modified locally, skipped"
+			if item.Upstream == "" {
+				status = "modified locally, removed upstream"
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\n", item.Name, status)
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "%d added, %d updated, %d removed, %d modified locally, %d unchanged\n",
+		counts[config.SyncAdded],
+		counts[config.SyncUpdated],
+		counts[config.SyncRemoved],
+		counts[config.SyncModified],
+		counts[config.SyncUnchanged],
+	)
+}
+
+// printSyncDiffs prints a unified diff between each locally modified pattern and upstream.
+func printSyncDiffs(
+	ctx context.Context,
+	out io.Writer,
+	source remote.PatternSource,
+	patternRepo string,
+	items []config.SyncItem,
+) error {
+	for _, item := range items {
+		if item.Status != config.SyncModified || item.Upstream == "" {
+			continue
+		}
+
+		upstream, err := source.Download(ctx, item.Name)
+		if err != nil {
+			return fmt.Errorf("downloading %s: %w", item.Name, err)
+		}
+
+		path, err := config.PatternPath(patternRepo, item.Name)
+		if err != nil {
+			return err
+		}
+		local, err := fs.ReadFile(path)
+		if err != nil {
+			return err
+		}
+
+		diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+			A:        difflib.SplitLines(upstream),
+			B:        difflib.SplitLines(string(local)),
+			FromFile: "upstream/" + item.Name,
+			ToFile:   "local/" + item.Name,
+			Context:  3,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintln(out)
+		fmt.Fprint(out, diff)
+	}
+
+	return nil
+}
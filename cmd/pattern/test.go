@@ -0,0 +1,200 @@
+package pattern
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/eval"
+	"github.com/spf13/cobra"
+)
+
+type testOptions struct {
+	configFile  flag.FilePath
+	patternName string
+	model       string
+	judge       string
+	mock        bool
+	record      bool
+	replay      bool
+	parallel    int
+	cases       []string
+}
+
+func newTestCmd() *cobra.Command {
+	var opts testOptions
+
+	cmd := &cobra.Command{
+		Use:   "test [pattern-name]",
+		Short: "Run the regression tests of a pattern",
+		Long: `Run the regression tests of a pattern, defined in a tests.yaml file next to its system.md.
+
+Without a name, the default pattern is tested. Cases run concurrently, and the command
+fails if any case fails. To run offline, use the mock model with --mock, or replay outputs
+recorded with --record using --replay. With --mock, judge assertions are skipped,
+unless a judge model is given with --judge-model.`,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: CompletePatternArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return testRun(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVarP(&opts.model, "model", "m", "", "model to run all cases with")
+	flags.StringVar(&opts.judge, "judge-model", "", "model grading judge assertions")
+	flags.BoolVar(&opts.mock, "mock", false, "run all cases with the mock model, offline")
+	flags.BoolVar(&opts.record, "record", false, "record the outputs of the models")
+	flags.BoolVar(&opts.replay, "replay", false, "replay recorded outputs instead of calling models")
+	flags.IntVarP(&opts.parallel, "parallel", "j", 4, "maximum number of cases run at once")
+	flags.StringSliceVar(&opts.cases, "case", nil, "only run these cases")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	cmd.MarkFlagsMutuallyExclusive("mock", "record", "replay")
+	cmd.MarkFlagsMutuallyExclusive("mock", "model")
+
+	return cmd
+}
+
+func (opts *testOptions) parse(cmd *cobra.Command, args []string) error {
+	opts.patternName = config.Pattern()
+	if len(args) > 0 {
+		opts.patternName = args[0]
+	}
+
+	// --model is bound to the config, only override the models of cases when it's set
+	if !cmd.Flags().Changed("model") {
+		opts.model = ""
+	}
+	if opts.mock {
+		opts.model = eval.MockModel
+	}
+
+	return nil
+}
+
+func testRun(ctx context.Context, out io.Writer, opts testOptions) error {
+	repo, name, err := config.FindPattern(opts.patternName, "")
+	if err != nil {
+		return err
+	}
+
+	pattern, err := repo.ReadPattern(name)
+	if err != nil {
+		return err
+	}
+	if pattern, err = config.ResolveIncludes(pattern); err != nil {
+		return err
+	}
+
+	suite, err := eval.LoadSuite(repo.FS, name)
+	if err != nil {
+		return err
+	}
+
+	if len(opts.cases) > 0 {
+		suite.Cases = slices.DeleteFunc(suite.Cases, func(c eval.Case) bool {
+			return !slices.Contains(opts.cases, c.Name)
+		})
+		if len(suite.Cases) == 0 {
+			return fmt.Errorf("no case named %s", strings.Join(opts.cases, ", "))
+		}
+	}
+
+	defaultModel := pattern.Meta.Model
+	if defaultModel == "" {
+		defaultModel = config.Model()
+	}
+
+	runner := eval.Runner{
+		Completer:    eval.LLMCompleter,
+		Model:        opts.model,
+		DefaultModel: defaultModel,
+		Judge:        opts.judge,
+		Temperature:  0.7,
+		Parallel:     opts.parallel,
+	}
+
+	recordingFile := filepath.Join(filepath.Dir(repo.PromptPath(name)), eval.RecordingFile)
+	var recording *eval.Recording
+	if opts.record || opts.replay {
+		if recording, err = eval.LoadRecording(recordingFile); err != nil {
+			return err
+		}
+	}
+	switch {
+	case opts.record:
+		runner.Completer = recording.Record(eval.LLMCompleter)
+	case opts.replay:
+		runner.Completer = recording.Replay()
+	}
+
+	results := runner.Run(ctx, pattern, suite)
+
+	if opts.record {
+		if err := recording.Save(recordingFile); err != nil {
+			return fmt.Errorf("saving recording: %w", err)
+		}
+	}
+
+	failed := printTestResults(out, results)
+	if failed > 0 {
+		return fmt.Errorf("%d of %d cases failed", failed, len(results))
+	}
+	return nil
+}
+
+// printTestResults prints a table of results and a summary, and returns the number of failed cases.
+func printTestResults(out io.Writer, results []eval.Result) int {
+	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
+
+	const format = "%s\t%s\t%s\t%s\n"
+	fmt.Fprintf(w, format, "CASE", "MODEL", "RESULT", "DETAILS")
+
+	failed := 0
+	for _, r := range results {
+		status := "PASS"
+		if !r.Passed() {
+			status = "FAIL"
+			failed++
+		}
+		fmt.Fprintf(w, format, r.Case, r.Model, status, testDetails(r))
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\n%d passed, %d failed\n", len(results)-failed, failed)
+	return failed
+}
+
+// testDetails summarizes why a case failed, or which of its assertions were skipped.
+func testDetails(r eval.Result) string {
+	if r.Err != nil {
+		return "error: " + r.Err.Error()
+	}
+
+	var details []string
+	for _, a := range r.Assertions {
+		switch {
+		case a.Skipped:
+			details = append(details, fmt.Sprintf("%s skipped: %s", a.Assertion, a.Message))
+		case !a.Passed && a.Message != "":
+			details = append(details, fmt.Sprintf("%s: %s", a.Assertion, a.Message))
+		case !a.Passed:
+			details = append(details, a.Assertion)
+		}
+	}
+	return strings.Join(details, "; ")
+}
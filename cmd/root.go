@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/nt54hamnghi/seaq/cmd/chat"
@@ -16,8 +18,11 @@ import (
 	"github.com/nt54hamnghi/seaq/cmd/flaggroup"
 	"github.com/nt54hamnghi/seaq/cmd/model"
 	"github.com/nt54hamnghi/seaq/cmd/pattern"
+	"github.com/nt54hamnghi/seaq/cmd/strategy"
+	suggestCmd "github.com/nt54hamnghi/seaq/cmd/suggest"
 	"github.com/nt54hamnghi/seaq/pkg/config"
 	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/nt54hamnghi/seaq/pkg/loader"
 	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
 	"github.com/nt54hamnghi/seaq/pkg/util/log"
 	"github.com/tmc/langchaingo/llms"
@@ -34,15 +39,24 @@ type rootOptions struct {
 	input       string
 	model       string
 	noStream    bool
-	inputFile   flag.FilePath
+	inputFile   flag.Path
 	output      flaggroup.Output
+	thinking    flaggroup.Thinking
 	pattern     string
 	patternRepo string
+	prompt      string
+	vars        map[string]string
 	verbose     bool
 
+	// strategy layered on top of the pattern, and its prompt
+	strategy       string
+	strategyPrompt string
+
 	// llm options
 	// TODO: add validation for temperature
 	temperature float64
+	// temperatureSet reports whether the temperature is set by a flag or the pattern
+	temperatureSet bool
 }
 
 func New() *cobra.Command {
@@ -55,7 +69,7 @@ func New() *cobra.Command {
 		SilenceUsage: true,
 		PreRunE: compose.SequenceE(
 			config.Init,
-			flaggroup.ValidateGroups(&opts.output),
+			flaggroup.ValidateGroups(&opts.output, &opts.thinking),
 		),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			switch err := opts.parse(cmd, args); {
@@ -75,18 +89,17 @@ func New() *cobra.Command {
 	return cmd
 }
 
-func (opts *rootOptions) parse(_ *cobra.Command, _ []string) error {
+func (opts *rootOptions) parse(cmd *cobra.Command, _ []string) error {
 	var (
 		input string
 		err   error
 	)
 
 	if opts.inputFile != "" {
-		bytes, err := os.ReadFile(opts.inputFile.String())
+		input, err = loader.ReadFile(cmd.Context(), opts.inputFile.String())
 		if err != nil {
 			return err
 		}
-		input = string(bytes)
 	} else {
 		input, err = fileio.ReadPipedStdin()
 		if err != nil {
@@ -96,30 +109,78 @@ func (opts *rootOptions) parse(_ *cobra.Command, _ []string) error {
 
 	opts.input = input
 	opts.model = config.Model()
+
+	// a partial pattern name is completed by picking among the matching patterns,
+	// and "auto" picks the pattern suggested for the input
+	flags := cmd.Flags()
+	if flags.Changed("pattern") {
+		var name string
+		if opts.pattern == suggestCmd.AutoPattern {
+			best, err := suggestCmd.BestPattern(cmd.Context(), input)
+			if err != nil {
+				return err
+			}
+			log.Info("Suggested pattern", "pattern", best.Name, "reasons", strings.Join(best.Reasons, "; "))
+			name = best.Name
+		} else if name, err = pattern.ResolvePattern(opts.pattern); err != nil {
+			return err
+		}
+		if err := config.UsePattern(name); err != nil {
+			return err
+		}
+	}
 	opts.pattern = config.Pattern()
 
+	// construct the prompt from the pattern
+	pat, err := config.GetPattern()
+	if err != nil {
+		return err
+	}
+	if opts.prompt, err = pat.Render(opts.vars); err != nil {
+		return err
+	}
+
+	// the pattern's metadata takes precedence over the config, but not over flags
+	if meta := pat.Meta; meta.Model != "" && !flags.Changed("model") {
+		opts.model = meta.Model
+	}
+	if meta := pat.Meta; meta.Temperature != nil && !flags.Changed("temperature") {
+		opts.temperature = *meta.Temperature
+	}
+	opts.temperatureSet = flags.Changed("temperature") || pat.Meta.Temperature != nil
+
+	// the strategy is layered on top of the pattern's prompt
+	if opts.strategy != "" {
+		s, err := config.GetStrategy(opts.strategy)
+		if err != nil {
+			return err
+		}
+		opts.strategyPrompt = s.Prompt
+	}
+
 	return nil
 }
 
 func run(ctx context.Context, opts rootOptions) error {
+	// pulling a model may take a while, so do it before setting the timeout
+	if err := model.EnsureModel(ctx, opts.model); err != nil {
+		return err
+	}
+
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Minute)
 	defer cancel()
 
 	if opts.verbose {
 		log.Info("completion",
 			"config", viper.ConfigFileUsed(),
-			"model", config.Model(),
-			"pattern", config.Pattern(),
+			"model", opts.model,
+			"pattern", opts.pattern,
+			"strategy", opts.strategy,
+			"temperature", opts.temperature,
 		)
 		fmt.Fprintln(os.Stderr)
 	}
 
-	// construct the prompt from pattern and scraped content
-	prompt, err := config.GetPrompt()
-	if err != nil {
-		return err
-	}
-
 	// construct the model
 	// nolint: contextcheck
 	model, err := llm.New(opts.model)
@@ -133,14 +194,43 @@ func run(ctx context.Context, opts rootOptions) error {
 	}
 	defer dest.Close()
 
+	// reasoning goes to stderr and the optional file, keeping the output clean
+	thinking, err := opts.thinking.Writer()
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = dest
+	if thinking != nil {
+		defer thinking.Close()
+
+		if !llms.SupportsReasoningModel(model) {
+			log.Warn("model may not support reasoning, --thinking might have no effect", "model", opts.model)
+		}
+		if opts.temperatureSet {
+			llm.WarnThinkingTemperature(opts.model, opts.temperature)
+		}
+
+		model = llm.WithThinking(model, opts.thinking.Budget,
+			func(_ context.Context, chunk []byte) error {
+				_, err := thinking.Write(chunk)
+				return err
+			},
+		)
+		out = thinking.Separate(dest)
+	}
+
 	// run the completion
-	msgs := llm.PrepareMessages(opts.model, prompt, opts.input, opts.hint)
+	msgs, err := llm.PrepareMessages(opts.model, opts.strategyPrompt, opts.prompt, opts.input, opts.hint)
+	if err != nil {
+		return err
+	}
 	if opts.noStream {
-		return llm.CreateCompletion(ctx, model, dest, msgs,
+		return llm.CreateCompletion(ctx, model, out, msgs,
 			llms.WithTemperature(opts.temperature),
 		)
 	}
-	return llm.CreateStreamCompletion(ctx, model, dest, msgs,
+	return llm.CreateStreamCompletion(ctx, model, out, msgs,
 		llms.WithTemperature(opts.temperature),
 	)
 }
@@ -153,14 +243,16 @@ func setupFlags(cmd *cobra.Command, opts *rootOptions) {
 	flags.StringVar(&opts.hint, "hint", "", "optional context to guide the LLM's focus")
 	flags.BoolVar(&opts.noStream, "no-stream", false, "disable streaming mode")
 	flags.Float64Var(&opts.temperature, "temperature", 0.7, "temperature to use")
-	flags.StringVarP(&opts.pattern, "pattern", "p", "", "pattern to use")
+	flags.StringVarP(&opts.pattern, "pattern", "p", "", "pattern to use, or auto to pick the one suggested for the input")
 	flags.StringVarP(&opts.patternRepo, "repo", "r", "", "path to the pattern repository")
-	flags.VarP(&opts.inputFile, "input", "i", "input file")
+	flags.StringToStringVar(&opts.vars, "var", nil, "value of a pattern variable, as name=value")
+	flags.StringVarP(&opts.strategy, "strategy", "s", "", "strategy to layer on top of the pattern, e.g. cot")
+	flags.VarP(&opts.inputFile, "input", "i", "input file, or a directory to read as a codebase")
 	config.AddConfigFlag(cmd, &opts.configFile)
 	flags.BoolVarP(&opts.verbose, "verbose", "V", false, "verbose output")
 
 	// flag groups
-	flaggroup.InitGroups(cmd, &opts.output)
+	flaggroup.InitGroups(cmd, &opts.output, &opts.thinking)
 
 	// register completion function
 	err := cmd.RegisterFlagCompletionFunc("pattern", pattern.CompletePatternArgs)
@@ -171,6 +263,10 @@ func setupFlags(cmd *cobra.Command, opts *rootOptions) {
 	if err != nil {
 		cobra.CheckErr(err)
 	}
+	err = cmd.RegisterFlagCompletionFunc("strategy", strategy.CompleteStrategyArgs)
+	if err != nil {
+		cobra.CheckErr(err)
+	}
 }
 
 func addCommands(cmd *cobra.Command) {
@@ -180,6 +276,8 @@ func addCommands(cmd *cobra.Command) {
 		model.NewModelCmd(),
 		fetch.NewFetchCmd(),
 		pattern.NewPatternCmd(),
+		strategy.NewStrategyCmd(),
+		suggestCmd.NewSuggestCmd(),
 		connection.NewConnectionCmd(),
 		configCmd.NewConfigCmd(),
 	)
@@ -0,0 +1,193 @@
+package strategy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/remote"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+type addOptions struct {
+	remote     string
+	configFile flag.FilePath
+	all        bool
+	force      bool
+	names      []string
+}
+
+func newAddCmd() *cobra.Command {
+	var opts addOptions
+
+	cmd := &cobra.Command{
+		Use:   "add [strategy-name]...",
+		Short: "Add strategies from the remote pattern source",
+		Long: `Add strategies from the strategies directory of the remote pattern source,
+e.g. https://github.com/danielmiessler/fabric, to the local strategy directory.`,
+		ValidArgsFunction: completeAddStrategyArgs,
+		SilenceUsage:      true,
+		PreRunE:           config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := opts.parse(cmd, args); err != nil {
+				return err
+			}
+			return addRun(cmd.Context(), cmd.OutOrStdout(), opts)
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.StringVarP(&opts.remote, "remote", "r", "", "remote pattern source: GitHub or git URL, archive or local directory")
+	flags.BoolVarP(&opts.all, "all", "a", false, "add all strategies of the remote")
+	flags.BoolVarP(&opts.force, "force", "f", false, "overwrite existing strategies")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func (opts *addOptions) parse(_ *cobra.Command, args []string) error {
+	switch {
+	case opts.all && len(args) > 0:
+		return errors.New("--all and strategy names are mutually exclusive")
+	case !opts.all && len(args) == 0:
+		return errors.New("requires at least one strategy name, or --all")
+	}
+	opts.names = args
+	return nil
+}
+
+func addRun(ctx context.Context, out io.Writer, opts addOptions) error {
+	remoteURL := viper.GetString("pattern.remote")
+	source, err := openStrategySource(remoteURL)
+	if err != nil {
+		return err
+	}
+
+	log.Info("Listing remote strategies", "remote", remoteURL)
+	available, err := source.Strategies(ctx)
+	if err != nil {
+		return fmt.Errorf("listing remote strategies: %w", err)
+	}
+
+	selected := available
+	if !opts.all {
+		if selected, err = selectStrategies(opts.names, available); err != nil {
+			return err
+		}
+	}
+
+	dir, err := config.StrategyDir()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("creating strategy directory: %w", err)
+	}
+
+	for _, s := range selected {
+		log.Info("Downloading strategy", "strategy", s.Name)
+		content, err := source.DownloadStrategy(ctx, s.File)
+		if err != nil {
+			return fmt.Errorf("downloading strategy %s: %w", s.Name, err)
+		}
+
+		// only write strategies that can be used
+		if _, err := config.ParseStrategy(s.File, []byte(content)); err != nil {
+			return err
+		}
+
+		file := filepath.Join(dir, s.File)
+		if err := writeStrategy(file, content, opts.force); err != nil {
+			return fmt.Errorf("writing strategy %s: %w", s.Name, err)
+		}
+		fmt.Fprintf(out, "Added strategy '%s' to %s\n", s.Name, file)
+	}
+
+	return nil
+}
+
+// openStrategySource opens a remote pattern source, to access its strategies.
+func openStrategySource(remoteURL string) (remote.StrategySource, error) {
+	source, err := remote.Open(remoteURL)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies, ok := source.(remote.StrategySource)
+	if !ok {
+		return nil, fmt.Errorf("remote %s does not provide strategies", remoteURL)
+	}
+	return strategies, nil
+}
+
+// selectStrategies picks the strategies with the given names.
+func selectStrategies(names []string, available []remote.Strategy) ([]remote.Strategy, error) {
+	selected := make([]remote.Strategy, 0, len(names))
+	for _, name := range names {
+		i := slices.IndexFunc(available, func(s remote.Strategy) bool {
+			return s.Name == name
+		})
+		if i == -1 {
+			return nil, fmt.Errorf("strategy %q not found in remote", name)
+		}
+		selected = append(selected, available[i])
+	}
+	return selected, nil
+}
+
+// writeStrategy writes a strategy file, refusing to overwrite an existing one unless force is set.
+func writeStrategy(file string, content string, force bool) error {
+	flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if !force {
+		flags |= os.O_EXCL
+	}
+
+	f, err := os.OpenFile(file, flags, 0o644)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("%s already exists, use --force to overwrite it", file)
+		}
+		return err
+	}
+
+	if _, err := io.WriteString(f, content); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func completeAddStrategyArgs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	// the config file must be loaded before we access "pattern.remote"
+	if err := config.EnsureConfig(cmd, args); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	source, err := openStrategySource(viper.GetString("pattern.remote"))
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	strategies, err := source.Strategies(cmd.Context())
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, 0, len(strategies))
+	for _, s := range strategies {
+		if !slices.Contains(args, s.Name) {
+			names = append(names, s.Name)
+		}
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,63 @@
+package strategy
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+type listOptions struct {
+	configFile flag.FilePath
+}
+
+func newListCmd() *cobra.Command {
+	var opts listOptions
+
+	cmd := &cobra.Command{
+		Use:          "list",
+		Short:        "List available strategies",
+		Aliases:      []string{"ls"},
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		PreRunE:      config.Init,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return listRun(cmd.OutOrStdout())
+		},
+	}
+
+	// set up flags
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	return cmd
+}
+
+func listRun(out io.Writer) error {
+	strategies, err := config.ListStrategies()
+	if err != nil {
+		return err
+	}
+
+	if len(strategies) == 0 {
+		dir, err := config.StrategyDir()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "No strategies found in %s, run `seaq strategy add --all` to download them\n", dir)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
+	defer w.Flush()
+
+	const format = "%s\t%s\n"
+	fmt.Fprintf(w, format, "NAME", "DESCRIPTION")
+	for _, s := range strategies {
+		fmt.Fprintf(w, format, s.Name, s.Description)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package strategy
+
+import (
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+func NewStrategyCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "strategy",
+		Short:        "Manage strategies",
+		Aliases:      []string{"strat"},
+		Args:         cobra.NoArgs,
+		SilenceUsage: true,
+		GroupID:      "management",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return cmd.Usage()
+		},
+	}
+
+	cmd.AddCommand(
+		newListCmd(),
+		newAddCmd(),
+	)
+
+	return cmd
+}
+
+func CompleteStrategyArgs(cmd *cobra.Command, args []string, _ string) ([]string, cobra.ShellCompDirective) {
+	// the strategy directory can be set in the config file
+	if err := config.EnsureConfig(cmd, args); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	strategies, err := config.ListStrategies()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	names := make([]string, len(strategies))
+	for i, s := range strategies {
+		names[i] = s.Name
+	}
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,197 @@
+package suggest
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nt54hamnghi/seaq/cmd/flag"
+	"github.com/nt54hamnghi/seaq/cmd/model"
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/nt54hamnghi/seaq/pkg/loader"
+	"github.com/nt54hamnghi/seaq/pkg/suggest"
+	"github.com/nt54hamnghi/seaq/pkg/util/fileio"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// AutoPattern is the pattern name that selects the pattern suggested for the input.
+const AutoPattern = "auto"
+
+// defaultLimit is the default number of suggestions.
+// It's also how many patterns AutoPattern picks from, so that it picks the top suggestion.
+const defaultLimit = 3
+
+type suggestOptions struct {
+	configFile flag.FilePath
+	inputFile  flag.Path
+	input      string
+	method     string
+	model      string
+	limit      int
+}
+
+func NewSuggestCmd() *cobra.Command {
+	var opts suggestOptions
+
+	cmd := &cobra.Command{
+		Use:   "suggest",
+		Short: "Suggest patterns for an input",
+		Long: `Suggest the patterns best suited to process an input, with the reasons why.
+
+The input is profiled by kind (text, code or transcript), source, language and length.
+The output of ` + "`seaq fetch --json`" + ` is recognized, to tell where the input comes from.
+Patterns are ranked by a classifier model, which reads their name, description and tags,
+or by similarity between the embeddings of the input and of their description.
+Run the best suggestion directly with ` + "`seaq -p auto`" + `.`,
+		Args:         cobra.NoArgs,
+		GroupID:      "common",
+		SilenceUsage: true,
+		PreRunE:      config.Init,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch err := opts.parse(cmd, args); {
+			case errors.Is(err, fileio.ErrInteractiveInput):
+				return cmd.Usage()
+			case err != nil:
+				return err
+			default:
+				return suggestRun(cmd.Context(), cmd.OutOrStdout(), opts)
+			}
+		},
+	}
+
+	// set up flags
+	flags := cmd.Flags()
+	flags.SortFlags = false
+	flags.VarP(&opts.inputFile, "input", "i", "input file, or a directory to read as a codebase")
+	flags.StringVar(&opts.method, "method", "", "ranking method: classifier or embedding (default classifier)")
+	flags.StringVarP(&opts.model, "model", "m", "", "classifier model, or embedding model with --method embedding")
+	flags.IntVarP(&opts.limit, "limit", "n", defaultLimit, "maximum number of suggestions")
+	config.AddConfigFlag(cmd, &opts.configFile)
+
+	err := cmd.RegisterFlagCompletionFunc("method", cobra.FixedCompletions(
+		[]string{suggest.MethodClassifier, suggest.MethodEmbedding},
+		cobra.ShellCompDirectiveNoFileComp,
+	))
+	if err != nil {
+		cobra.CheckErr(err)
+	}
+	err = cmd.RegisterFlagCompletionFunc("model", model.CompleteModelArgs)
+	if err != nil {
+		cobra.CheckErr(err)
+	}
+
+	return cmd
+}
+
+func (opts *suggestOptions) parse(cmd *cobra.Command, _ []string) error {
+	if opts.inputFile != "" {
+		input, err := loader.ReadFile(cmd.Context(), opts.inputFile.String())
+		if err != nil {
+			return err
+		}
+		opts.input = input
+	} else {
+		input, err := fileio.ReadPipedStdin()
+		if err != nil {
+			return err
+		}
+		opts.input = input
+	}
+
+	if opts.limit <= 0 {
+		return errors.New("--limit must be positive")
+	}
+	return nil
+}
+
+func suggestRun(ctx context.Context, out io.Writer, opts suggestOptions) error {
+	profile, text := suggest.Inspect(opts.input)
+
+	suggestions, err := Suggest(ctx, profile, text, opts.method, opts.model, opts.limit)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(out, "Input: %s\n\n", profile)
+
+	w := tabwriter.NewWriter(out, 0, 0, 4, ' ', 0)
+	const format = "%s\t%s\t%s\n"
+	fmt.Fprintf(w, format, "PATTERN", "SCORE", "REASONS")
+	for _, s := range suggestions {
+		fmt.Fprintf(w, format, s.Name, fmt.Sprintf("%.2f", s.Score), strings.Join(s.Reasons, "; "))
+	}
+	w.Flush()
+
+	fmt.Fprintf(out, "\nRun the best one with: seaq -p %s\n", suggestions[0].Name)
+	return nil
+}
+
+// Suggest ranks the patterns for a profiled input and returns at most limit suggestions, best first.
+//
+// The method and model default to the `suggest.method` config key, and to `suggest.model`
+// for the classifier, falling back to the default model, or to `suggest.embedding_model`
+// for embeddings, falling back to llm.DefaultEmbeddingModel.
+func Suggest(
+	ctx context.Context,
+	profile suggest.Profile,
+	text string,
+	method string,
+	modelName string,
+	limit int,
+) ([]suggest.Suggestion, error) {
+	if method == "" {
+		method = viper.GetString("suggest.method")
+	}
+
+	var ranker suggest.Ranker
+	switch method {
+	case "", suggest.MethodClassifier:
+		modelName = cmp.Or(modelName, viper.GetString("suggest.model"), config.Model())
+		if err := model.EnsureModel(ctx, modelName); err != nil {
+			return nil, err
+		}
+		ranker = &suggest.ClassifierRanker{Model: modelName, Complete: suggest.Complete, Limit: limit}
+	case suggest.MethodEmbedding:
+		modelName = cmp.Or(modelName, viper.GetString("suggest.embedding_model"), llm.DefaultEmbeddingModel)
+		r, err := suggest.NewEmbeddingRanker(modelName)
+		if err != nil {
+			return nil, err
+		}
+		ranker = r
+	default:
+		return nil, fmt.Errorf("invalid method %q, must be one of: classifier, embedding", method)
+	}
+
+	entries, err := config.ListPatternEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	// shadowed patterns are only reachable through their qualified name, leave them out
+	candidates := make([]suggest.Candidate, 0, len(entries))
+	for _, e := range entries {
+		if !e.Shadowed() {
+			candidates = append(candidates, suggest.Candidate{Name: e.Name, Meta: e.Meta})
+		}
+	}
+
+	return suggest.Suggest(ctx, ranker, profile, text, candidates, limit)
+}
+
+// BestPattern returns the pattern best suited to an input, with the configured method.
+func BestPattern(ctx context.Context, input string) (suggest.Suggestion, error) {
+	profile, text := suggest.Inspect(input)
+
+	// rank as many patterns as `seaq suggest` does, so that the top suggestion is the same
+	suggestions, err := Suggest(ctx, profile, text, "", "", defaultLimit)
+	if err != nil {
+		return suggest.Suggestion{}, fmt.Errorf("suggesting a pattern: %w", err)
+	}
+	return suggestions[0], nil
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// ErrPatternExists is returned when creating a pattern that already exists in a repository.
+var ErrPatternExists = errors.New("pattern already exists")
+
+// defaultPatternTemplate is the template of new patterns, see RenderPatternTemplate.
+const defaultPatternTemplate = `---
+description: {{ printf "%q" .Description }}
+tags: [{{ join .Tags ", " }}]
+---
+
+# IDENTITY and PURPOSE
+
+You are an expert at ...
+
+# STEPS
+
+- ...
+
+# OUTPUT INSTRUCTIONS
+
+- Only output Markdown.
+
+# INPUT
+
+INPUT:
+`
+
+// PatternTemplateData is the data available to the template of a new pattern.
+type PatternTemplateData struct {
+	Name        string
+	Description string
+	Tags        []string
+}
+
+// RenderPatternTemplate renders the content of a new pattern's system.md
+// from a Go template, or from the default template if tmpl is empty.
+func RenderPatternTemplate(tmpl string, data PatternTemplateData) (string, error) {
+	if tmpl == "" {
+		tmpl = defaultPatternTemplate
+	}
+
+	t, err := template.New(data.Name).
+		Funcs(template.FuncMap{"join": strings.Join}).
+		Parse(tmpl)
+	if err != nil {
+		return "", fmt.Errorf("parsing pattern template: %w", err)
+	}
+
+	var sb strings.Builder
+	if err := t.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("rendering pattern template: %w", err)
+	}
+	return sb.String(), nil
+}
+
+// CreatePattern creates a pattern in a repository with the given system.md content,
+// and returns the path of its prompt file. It fails if the pattern already exists.
+func CreatePattern(repo string, name string, content string) (string, error) {
+	if repo == "" {
+		return "", ErrEmptyRepo
+	}
+
+	dir, err := PatternDir(repo, name)
+	if err != nil {
+		return "", err
+	}
+	file := filepath.Join(dir, PatternFile)
+
+	if _, err := os.Stat(file); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrPatternExists, name)
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(file, []byte(content), 0o644); err != nil {
+		return "", err
+	}
+
+	return file, nil
+}
+
+// CopyPattern copies a pattern from a repository to another directory-backed repository,
+// possibly under a new name, and returns the path of the copied prompt file.
+//
+// The files of the pattern's directory are copied, such as system.md and pattern.yaml,
+// but not its subdirectories, which may hold nested patterns.
+// It fails if the destination pattern already exists.
+func CopyPattern(src PatternRepo, srcName string, repo string, dstName string) (string, error) {
+	if repo == "" {
+		return "", ErrEmptyRepo
+	}
+	if !src.Has(srcName) {
+		return "", &Unsupported{Type: "pattern", Key: srcName}
+	}
+
+	dir, err := PatternDir(repo, dstName)
+	if err != nil {
+		return "", err
+	}
+	if _, err := os.Stat(filepath.Join(dir, PatternFile)); err == nil {
+		return "", fmt.Errorf("%w: %s", ErrPatternExists, dstName)
+	}
+
+	entries, err := fs.ReadDir(src.FS, srcName)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		data, err := fs.ReadFile(src.FS, path.Join(srcName, e.Name()))
+		if err != nil {
+			return "", err
+		}
+		if err := os.WriteFile(filepath.Join(dir, e.Name()), data, 0o644); err != nil {
+			return "", err
+		}
+	}
+
+	return filepath.Join(dir, PatternFile), nil
+}
+
+// RemovePattern removes a pattern from a directory-backed repository.
+//
+// The files of the pattern's directory are removed, but not its subdirectories,
+// which may hold nested patterns. Directories left empty are removed too,
+// and the pattern is no longer tracked in the repository's manifest.
+func RemovePattern(repo string, name string) error {
+	if repo == "" {
+		return ErrEmptyRepo
+	}
+
+	dir, err := PatternDir(repo, name)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(filepath.Join(dir, PatternFile)); err != nil {
+		return &Unsupported{Type: "pattern", Key: name}
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	// remove the directories left empty, up to the repository
+	for d := dir; d != filepath.Clean(repo); d = filepath.Dir(d) {
+		if os.Remove(d) != nil {
+			break
+		}
+	}
+
+	manifest, err := LoadManifest(repo)
+	if err != nil {
+		return err
+	}
+	if _, ok := manifest.Patterns[name]; ok {
+		delete(manifest.Patterns, name)
+		return manifest.Save(repo)
+	}
+
+	return nil
+}
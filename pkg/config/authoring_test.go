@@ -0,0 +1,130 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderPatternTemplate(t *testing.T) {
+	r := require.New(t)
+
+	// the default template renders valid front matter
+	content, err := RenderPatternTemplate("", PatternTemplateData{
+		Name:        "translate",
+		Description: "Translate: keep the tone",
+		Tags:        []string{"writing", "translation"},
+	})
+	r.NoError(err)
+
+	meta, prompt, err := splitFrontMatter(content)
+	r.NoError(err)
+	r.Equal(PatternMeta{
+		Description: "Translate: keep the tone",
+		Tags:        []string{"writing", "translation"},
+	}, meta)
+	r.Contains(prompt, "# IDENTITY and PURPOSE")
+
+	content, err = RenderPatternTemplate("# {{ .Name }}", PatternTemplateData{Name: "translate"})
+	r.NoError(err)
+	r.Equal("# translate", content)
+
+	_, err = RenderPatternTemplate("{{ .Name", PatternTemplateData{})
+	r.Error(err)
+}
+
+func TestCreatePattern(t *testing.T) {
+	r := require.New(t)
+	repo := t.TempDir()
+
+	file, err := CreatePattern(repo, "review/security", "prompt")
+	r.NoError(err)
+	r.Equal(filepath.Join(repo, "review", "security", PatternFile), file)
+
+	data, err := os.ReadFile(file)
+	r.NoError(err)
+	r.Equal("prompt", string(data))
+
+	_, err = CreatePattern(repo, "review/security", "other prompt")
+	r.ErrorIs(err, ErrPatternExists)
+
+	_, err = CreatePattern(repo, "../escape", "prompt")
+	r.Error(err)
+
+	_, err = CreatePattern("", "summarize", "prompt")
+	r.ErrorIs(err, ErrEmptyRepo)
+}
+
+func TestCopyPattern(t *testing.T) {
+	r := require.New(t)
+
+	src := t.TempDir()
+	writeFile(t, filepath.Join(src, "review", PatternFile), "review prompt")
+	writeFile(t, filepath.Join(src, "review", PatternMetaFile), "description: Review code\n")
+	writeFile(t, filepath.Join(src, "review", "security", PatternFile), "security prompt")
+
+	dst := t.TempDir()
+	srcRepo := NewPatternRepo("team", src)
+
+	file, err := CopyPattern(srcRepo, "review", dst, "my/review")
+	r.NoError(err)
+	r.Equal(filepath.Join(dst, "my", "review", PatternFile), file)
+
+	copied, err := NewPatternRepo(PersonalSource, dst).ReadPattern("my/review")
+	r.NoError(err)
+	r.Equal("review prompt", copied.Prompt)
+	r.Equal("Review code", copied.Meta.Description)
+
+	// nested patterns are not copied
+	pats, err := ListPatternsInRepo(dst)
+	r.NoError(err)
+	r.Equal([]string{"my/review"}, pats)
+
+	_, err = CopyPattern(srcRepo, "review", dst, "my/review")
+	r.ErrorIs(err, ErrPatternExists)
+
+	_, err = CopyPattern(srcRepo, "missing", dst, "missing")
+	r.Error(err)
+}
+
+func TestRemovePattern(t *testing.T) {
+	r := require.New(t)
+
+	repo := t.TempDir()
+	writeFile(t, filepath.Join(repo, "review", PatternFile), "review prompt")
+	writeFile(t, filepath.Join(repo, "review", PatternMetaFile), "description: Review code\n")
+	writeFile(t, filepath.Join(repo, "review", "security", PatternFile), "security prompt")
+	writeFile(t, filepath.Join(repo, "team", "summarize", PatternFile), "summarize prompt")
+
+	m := Manifest{Patterns: map[string]ManifestEntry{
+		"review":         {SHA: "1"},
+		"team/summarize": {SHA: "2"},
+	}}
+	r.NoError(m.Save(repo))
+
+	// nested patterns are kept
+	r.NoError(RemovePattern(repo, "review"))
+	pats, err := ListPatternsInRepo(repo)
+	r.NoError(err)
+	r.Equal([]string{"review/security", "team/summarize"}, pats)
+	r.NoFileExists(filepath.Join(repo, "review", PatternMetaFile))
+
+	// empty directories are removed, up to the repository
+	r.NoError(RemovePattern(repo, "team/summarize"))
+	r.NoDirExists(filepath.Join(repo, "team"))
+	r.DirExists(repo)
+
+	m, err = LoadManifest(repo)
+	r.NoError(err)
+	r.Empty(m.Patterns)
+
+	r.Error(RemovePattern(repo, "missing"))
+}
+
+func writeFile(t *testing.T, name string, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+	require.NoError(t, os.WriteFile(name, []byte(content), 0o644))
+}
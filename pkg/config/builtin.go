@@ -0,0 +1,21 @@
+package config
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// BuiltinSource is the name of the repository of the patterns shipped with seaq.
+const BuiltinSource = "builtin"
+
+//go:embed builtin
+var builtinFS embed.FS
+
+// BuiltinRepo returns the repository of the patterns embedded in seaq.
+// It comes last in lookup order, so that seaq works without any pattern repository,
+// and its patterns can be customized by ejecting them, see CopyPattern.
+func BuiltinRepo() PatternRepo {
+	// the directory is embedded, so it always exists
+	sub, _ := fs.Sub(builtinFS, "builtin")
+	return PatternRepo{Name: BuiltinSource, FS: sub}
+}
@@ -0,0 +1,449 @@
+package config
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// BundleDir is the directory holding patterns in a bundle.
+//
+// A bundle is a tar.gz archive following the layout of pattern libraries,
+// so it can also be used as a remote pattern source:
+//
+//	patterns/<name>/system.md      the prompt, with its front matter
+//	patterns/<name>/pattern.yaml   any other file of the pattern's directory
+//	.seaq-manifest.json            the upstream versions of the patterns, if they are tracked
+const BundleDir = "patterns"
+
+// maxBundleFileSize is the size above which a file of a bundle is rejected,
+// as bundles aren't trusted and patterns are small text files.
+const maxBundleFileSize = 1 << 20
+
+// ConflictPolicy is how a pattern of a bundle is imported when the repository already has it.
+type ConflictPolicy string
+
+const (
+	// ConflictSkip leaves the existing pattern alone.
+	ConflictSkip ConflictPolicy = "skip"
+	// ConflictOverwrite replaces the existing pattern.
+	ConflictOverwrite ConflictPolicy = "overwrite"
+	// ConflictRename imports the pattern under a new name, e.g. summarize_2.
+	ConflictRename ConflictPolicy = "rename"
+)
+
+// Validate checks if the policy is one of the supported policies.
+func (p ConflictPolicy) Validate() error {
+	switch p {
+	case ConflictSkip, ConflictOverwrite, ConflictRename:
+		return nil
+	default:
+		return fmt.Errorf("invalid conflict policy %q, must be one of: skip, overwrite, rename", p)
+	}
+}
+
+// ImportStatus is the outcome of importing a pattern.
+type ImportStatus string
+
+const (
+	ImportAdded       ImportStatus = "added"
+	ImportSkipped     ImportStatus = "skipped"
+	ImportOverwritten ImportStatus = "overwritten"
+	ImportRenamed     ImportStatus = "renamed"
+)
+
+// ImportResult is the outcome of importing a pattern of a bundle.
+type ImportResult struct {
+	// Name is the name of the pattern in the bundle.
+	Name string
+	// ImportedAs is the name of the pattern in the repository, empty if it was skipped.
+	ImportedAs string
+	Status     ImportStatus
+}
+
+// ExportBundle writes the patterns with the given names to w, as a bundle.
+// Names are looked up in the pattern repositories, and can be qualified with a source,
+// e.g. "team:review". It returns the names of the patterns in the bundle.
+func ExportBundle(w io.Writer, names []string) ([]string, error) {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	now := time.Now()
+
+	writeFile := func(name string, data []byte) error {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Mode:     0o644,
+			Size:     int64(len(data)),
+			ModTime:  now,
+			Typeflag: tar.TypeReg,
+		})
+		if err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	}
+
+	manifest := Manifest{Patterns: make(map[string]ManifestEntry)}
+	exported := make([]string, 0, len(names))
+
+	for _, qualified := range names {
+		repo, name, err := FindPattern(qualified, "")
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(exported, name) {
+			return nil, fmt.Errorf("pattern %s is exported twice, patterns of a bundle must have distinct names", name)
+		}
+		exported = append(exported, name)
+
+		entries, err := fs.ReadDir(repo.FS, name)
+		if err != nil {
+			return nil, err
+		}
+		// only the files of the pattern's directory, its subdirectories may hold nested patterns
+		for _, e := range entries {
+			if !e.Type().IsRegular() {
+				continue
+			}
+			data, err := fs.ReadFile(repo.FS, path.Join(name, e.Name()))
+			if err != nil {
+				return nil, err
+			}
+			if err := writeFile(path.Join(BundleDir, name, e.Name()), data); err != nil {
+				return nil, err
+			}
+		}
+
+		if err := trackExport(&manifest, repo, name); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(manifest.Patterns) > 0 {
+		data, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		if err := writeFile(ManifestFile, append(data, '\n')); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	if err := gw.Close(); err != nil {
+		return nil, err
+	}
+
+	return exported, nil
+}
+
+// trackExport records the upstream version of an exported pattern in the bundle's manifest,
+// if its repository tracks it. A bundle has a single remote, so patterns downloaded
+// from another remote than the first tracked pattern are not tracked.
+// Repositories without a directory, such as the built-in patterns, have no manifest.
+func trackExport(manifest *Manifest, repo PatternRepo, name string) error {
+	if repo.Path == "" {
+		return nil
+	}
+
+	m, err := LoadManifest(repo.Path)
+	if err != nil {
+		return fmt.Errorf("reading manifest of %s: %w", repo.Name, err)
+	}
+
+	entry, ok := m.Patterns[name]
+	if !ok || (manifest.Remote != "" && manifest.Remote != m.Remote) {
+		return nil
+	}
+	manifest.Remote = m.Remote
+	manifest.Patterns[name] = entry
+	return nil
+}
+
+// ImportBundle reads a bundle from r and copies its patterns to a directory-backed repository.
+// Patterns already in the repository are handled according to policy.
+//
+// The upstream versions of the bundle's manifest are merged into the repository's manifest,
+// so that `pattern sync` can update imported patterns, unless the repository tracks another remote.
+func ImportBundle(r io.Reader, repo string, policy ConflictPolicy) ([]ImportResult, error) {
+	if repo == "" {
+		return nil, ErrEmptyRepo
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.MkdirTemp("", "seaq-bundle-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmp)
+
+	if err := extractBundle(r, tmp); err != nil {
+		return nil, err
+	}
+	fsys := os.DirFS(tmp)
+
+	info, err := fs.Stat(fsys, BundleDir)
+	if err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("not a pattern bundle, no %s directory found", BundleDir)
+	}
+	patterns, err := fs.Sub(fsys, BundleDir)
+	if err != nil {
+		return nil, err
+	}
+	src := PatternRepo{Name: "bundle", FS: patterns}
+
+	names, err := src.List()
+	if err != nil {
+		return nil, err
+	}
+	if len(names) == 0 {
+		return nil, errors.New("bundle has no patterns")
+	}
+
+	upstream := Manifest{Patterns: make(map[string]ManifestEntry)}
+	if data, err := fs.ReadFile(fsys, ManifestFile); err == nil {
+		if err := json.Unmarshal(data, &upstream); err != nil {
+			return nil, fmt.Errorf("parsing manifest of bundle: %w", err)
+		}
+	}
+
+	manifest, err := LoadManifest(repo)
+	if err != nil {
+		return nil, err
+	}
+	tracked := manifest.Remote == "" || manifest.Remote == upstream.Remote
+
+	results := make([]ImportResult, 0, len(names))
+	for _, name := range names {
+		res, err := importPattern(src, name, repo, policy)
+		if err != nil {
+			return results, fmt.Errorf("importing %s: %w", name, err)
+		}
+		results = append(results, res)
+
+		// the replaced pattern is no longer the upstream version the manifest tracks
+		if _, ok := manifest.Patterns[name]; ok && res.Status == ImportOverwritten {
+			delete(manifest.Patterns, name)
+			if err := manifest.Save(repo); err != nil {
+				return results, fmt.Errorf("writing manifest: %w", err)
+			}
+		}
+
+		// a renamed pattern has no upstream counterpart
+		entry, ok := upstream.Patterns[name]
+		if !ok || !tracked || res.ImportedAs != name {
+			continue
+		}
+		manifest.Remote = upstream.Remote
+		manifest.Patterns[name] = entry
+		if err := manifest.Save(repo); err != nil {
+			return results, fmt.Errorf("writing manifest: %w", err)
+		}
+	}
+
+	return results, nil
+}
+
+// importPattern copies a pattern of a bundle to a repository according to the conflict policy.
+func importPattern(src PatternRepo, name string, repo string, policy ConflictPolicy) (ImportResult, error) {
+	res := ImportResult{Name: name, ImportedAs: name, Status: ImportAdded}
+
+	exists, err := patternExists(repo, name)
+	if err != nil {
+		return ImportResult{}, err
+	}
+
+	if exists {
+		switch policy {
+		case ConflictSkip:
+			return ImportResult{Name: name, Status: ImportSkipped}, nil
+		case ConflictOverwrite:
+			if err := replacePattern(src, name, repo); err != nil {
+				return ImportResult{}, err
+			}
+			res.Status = ImportOverwritten
+			return res, nil
+		case ConflictRename:
+			if res.ImportedAs, err = freeName(repo, name); err != nil {
+				return ImportResult{}, err
+			}
+			res.Status = ImportRenamed
+		}
+	}
+
+	if _, err := CopyPattern(src, name, repo, res.ImportedAs); err != nil {
+		return ImportResult{}, err
+	}
+	return res, nil
+}
+
+// replacePattern replaces the files of an existing pattern with those of a pattern of src.
+// The new files are written next to the old ones first, so that a failure leaves the old version intact.
+// Subdirectories are left alone, as they may hold nested patterns.
+func replacePattern(src PatternRepo, name string, repo string) error {
+	dir, err := PatternDir(repo, name)
+	if err != nil {
+		return err
+	}
+	old, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	entries, err := fs.ReadDir(src.FS, name)
+	if err != nil {
+		return err
+	}
+
+	// staged maps file names to their new version, removed if not renamed
+	staged := make(map[string]string)
+	defer func() {
+		for _, tmp := range staged {
+			os.Remove(tmp)
+		}
+	}()
+
+	for _, e := range entries {
+		if !e.Type().IsRegular() {
+			continue
+		}
+		data, err := fs.ReadFile(src.FS, path.Join(name, e.Name()))
+		if err != nil {
+			return err
+		}
+		tmp, err := writeTemp(dir, e.Name(), data)
+		if err != nil {
+			return err
+		}
+		staged[e.Name()] = tmp
+	}
+
+	// the old files the new version doesn't have
+	for _, e := range old {
+		if _, ok := staged[e.Name()]; ok || e.IsDir() {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			return err
+		}
+	}
+
+	for name, tmp := range staged {
+		if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeTemp writes data to a new hidden file of a directory, and returns its path.
+func writeTemp(dir string, name string, data []byte) (string, error) {
+	f, err := os.CreateTemp(dir, "."+name+".*")
+	if err != nil {
+		return "", err
+	}
+	_, err = f.Write(data)
+	if err == nil {
+		err = f.Chmod(0o644)
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return f.Name(), nil
+}
+
+// patternExists reports whether a directory-backed repository has a pattern.
+func patternExists(repo string, name string) (bool, error) {
+	dir, err := PatternDir(repo, name)
+	if err != nil {
+		return false, err
+	}
+	_, err = os.Stat(filepath.Join(dir, PatternFile))
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, fs.ErrNotExist):
+		return false, nil
+	default:
+		return false, err
+	}
+}
+
+// freeName returns the first name not used in a repository, numbering the given one,
+// e.g. summarize_2, summarize_3 and so on.
+func freeName(repo string, name string) (string, error) {
+	for i := 2; ; i++ {
+		candidate := name + "_" + strconv.Itoa(i)
+		exists, err := patternExists(repo, candidate)
+		if err != nil || !exists {
+			return candidate, err
+		}
+	}
+}
+
+// extractBundle extracts the regular files of a tar.gz archive into a directory.
+func extractBundle(r io.Reader, dir string) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("reading bundle: %w", err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("reading bundle: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		// a bundle is not trusted, entries must not escape it, e.g. "../etc/passwd"
+		name := path.Clean(hdr.Name)
+		if !fs.ValidPath(name) {
+			return fmt.Errorf("invalid path %q in bundle", hdr.Name)
+		}
+
+		// the header size isn't trusted either, reading stops past the limit
+		data, err := io.ReadAll(io.LimitReader(tr, maxBundleFileSize+1))
+		if err != nil {
+			return fmt.Errorf("reading bundle: %w", err)
+		}
+		if len(data) > maxBundleFileSize {
+			return fmt.Errorf("file %q in bundle is larger than %d bytes", hdr.Name, maxBundleFileSize)
+		}
+
+		file := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, data, 0o644); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package config
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func (s *PatternRepoTestSuite) TestExportImportBundle() {
+	r := s.Require()
+
+	// front matter and other files of the pattern travel with it
+	summarize := "---\ndescription: Summarize\n---\n\nteam summarize"
+	s.writePattern(s.team, "summarize", summarize)
+	r.NoError(os.WriteFile(filepath.Join(s.team, "summarize", PatternMetaFile), []byte("tags: [writing]\n"), 0o644))
+	r.NoError(Manifest{
+		Remote:   "https://github.com/danielmiessler/fabric",
+		Patterns: map[string]ManifestEntry{"summarize": {SHA: "s1", Revision: "c1"}},
+	}.Save(s.team))
+
+	var bundle bytes.Buffer
+	exported, err := ExportBundle(&bundle, []string{"team:summarize", "review/security"})
+	r.NoError(err)
+	r.Equal([]string{"summarize", "review/security"}, exported)
+
+	_, err = ExportBundle(&bytes.Buffer{}, []string{"summarize", "team:summarize"})
+	r.Error(err)
+
+	_, err = ExportBundle(&bytes.Buffer{}, []string{"unknown"})
+	r.Error(err)
+
+	testCases := []struct {
+		name     string
+		policy   ConflictPolicy
+		want     []ImportResult
+		wantFile string
+	}{
+		{
+			name:   "skip",
+			policy: ConflictSkip,
+			want: []ImportResult{
+				{Name: "review/security", ImportedAs: "review/security", Status: ImportAdded},
+				{Name: "summarize", Status: ImportSkipped},
+			},
+			wantFile: "summarize/system.md",
+		},
+		{
+			name:   "overwrite",
+			policy: ConflictOverwrite,
+			want: []ImportResult{
+				{Name: "review/security", ImportedAs: "review/security", Status: ImportAdded},
+				{Name: "summarize", ImportedAs: "summarize", Status: ImportOverwritten},
+			},
+			wantFile: "summarize/system.md",
+		},
+		{
+			name:   "rename",
+			policy: ConflictRename,
+			want: []ImportResult{
+				{Name: "review/security", ImportedAs: "review/security", Status: ImportAdded},
+				{Name: "summarize", ImportedAs: "summarize_2", Status: ImportRenamed},
+			},
+			wantFile: "summarize_2/system.md",
+		},
+	}
+
+	for _, tt := range testCases {
+		s.Run(tt.name, func() {
+			repo := s.T().TempDir()
+			s.writePattern(repo, "summarize", "local summarize")
+
+			results, err := ImportBundle(bytes.NewReader(bundle.Bytes()), repo, tt.policy)
+			r.NoError(err)
+			r.Equal(tt.want, results)
+
+			security, err := os.ReadFile(filepath.Join(repo, "review", "security", PatternFile))
+			r.NoError(err)
+			r.Equal("team security", string(security))
+
+			got, err := os.ReadFile(filepath.Join(repo, filepath.FromSlash(tt.wantFile)))
+			r.NoError(err)
+			if tt.policy == ConflictSkip {
+				r.Equal("local summarize", string(got))
+				return
+			}
+			r.Equal(summarize, string(got))
+
+			dir := filepath.Dir(filepath.Join(repo, filepath.FromSlash(tt.wantFile)))
+			r.FileExists(filepath.Join(dir, PatternMetaFile))
+
+			// only patterns imported under their own name are tracked
+			manifest, err := LoadManifest(repo)
+			r.NoError(err)
+			if tt.policy == ConflictOverwrite {
+				r.Equal("https://github.com/danielmiessler/fabric", manifest.Remote)
+				r.Equal(map[string]ManifestEntry{"summarize": {SHA: "s1", Revision: "c1"}}, manifest.Patterns)
+			} else {
+				r.Empty(manifest.Patterns)
+			}
+		})
+	}
+}
+
+func TestImportBundle_Invalid(t *testing.T) {
+	archive := func(files map[string]string) []byte {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		tw := tar.NewWriter(gz)
+		for name, content := range files {
+			require.NoError(t, tw.WriteHeader(&tar.Header{
+				Name:     name,
+				Mode:     0o644,
+				Size:     int64(len(content)),
+				Typeflag: tar.TypeReg,
+			}))
+			_, err := tw.Write([]byte(content))
+			require.NoError(t, err)
+		}
+		require.NoError(t, tw.Close())
+		require.NoError(t, gz.Close())
+		return buf.Bytes()
+	}
+
+	testCases := []struct {
+		name   string
+		bundle []byte
+		policy ConflictPolicy
+	}{
+		{
+			name:   "not gzipped",
+			bundle: []byte("patterns/summarize/system.md"),
+			policy: ConflictSkip,
+		},
+		{
+			name:   "no patterns directory",
+			bundle: archive(map[string]string{"summarize/system.md": "summarize"}),
+			policy: ConflictSkip,
+		},
+		{
+			name:   "escaping path",
+			bundle: archive(map[string]string{"../patterns/summarize/system.md": "summarize"}),
+			policy: ConflictSkip,
+		},
+		{
+			name:   "file too large",
+			bundle: archive(map[string]string{"patterns/summarize/system.md": strings.Repeat("a", maxBundleFileSize+1)}),
+			policy: ConflictSkip,
+		},
+		{
+			name:   "no patterns",
+			bundle: archive(map[string]string{"patterns/README.md": "readme"}),
+			policy: ConflictSkip,
+		},
+		{
+			name:   "invalid policy",
+			bundle: archive(map[string]string{"patterns/summarize/system.md": "summarize"}),
+			policy: "merge",
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := t.TempDir()
+			_, err := ImportBundle(bytes.NewReader(tt.bundle), repo, tt.policy)
+			r.Error(err)
+
+			entries, err := os.ReadDir(repo)
+			r.NoError(err)
+			r.Empty(entries)
+		})
+	}
+}
+
+// failingFS fails to read one of its files.
+type failingFS struct {
+	fstest.MapFS
+	fail string
+}
+
+func (f failingFS) ReadFile(name string) ([]byte, error) {
+	if name == f.fail {
+		return nil, errors.New("read failed")
+	}
+	return f.MapFS.ReadFile(name)
+}
+
+func TestImportPattern_Overwrite(t *testing.T) {
+	r := require.New(t)
+
+	repo := t.TempDir()
+	dir := filepath.Join(repo, "summarize")
+	r.NoError(os.MkdirAll(filepath.Join(dir, "nested"), 0o755))
+	r.NoError(os.WriteFile(filepath.Join(dir, PatternFile), []byte("local summarize"), 0o644))
+	r.NoError(os.WriteFile(filepath.Join(dir, "notes.md"), []byte("notes"), 0o644))
+	r.NoError(os.WriteFile(filepath.Join(dir, "nested", PatternFile), []byte("nested"), 0o644))
+
+	files := fstest.MapFS{
+		"summarize/system.md":    {Data: []byte("bundle summarize")},
+		"summarize/pattern.yaml": {Data: []byte("tags: [writing]\n")},
+	}
+
+	// a failed copy leaves the local pattern as it was
+	src := PatternRepo{Name: "bundle", FS: failingFS{MapFS: files, fail: "summarize/pattern.yaml"}}
+	_, err := importPattern(src, "summarize", repo, ConflictOverwrite)
+	r.Error(err)
+
+	entries, err := os.ReadDir(dir)
+	r.NoError(err)
+	names := make([]string, len(entries))
+	for i, e := range entries {
+		names[i] = e.Name()
+	}
+	r.Equal([]string{"nested", "notes.md", PatternFile}, names)
+	got, err := os.ReadFile(filepath.Join(dir, PatternFile))
+	r.NoError(err)
+	r.Equal("local summarize", string(got))
+
+	// otherwise, the files are replaced and nested patterns are kept
+	res, err := importPattern(PatternRepo{Name: "bundle", FS: files}, "summarize", repo, ConflictOverwrite)
+	r.NoError(err)
+	r.Equal(ImportResult{Name: "summarize", ImportedAs: "summarize", Status: ImportOverwritten}, res)
+
+	entries, err = os.ReadDir(dir)
+	r.NoError(err)
+	names = names[:0]
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	r.Equal([]string{"nested", PatternMetaFile, PatternFile}, names)
+	got, err = os.ReadFile(filepath.Join(dir, PatternFile))
+	r.NoError(err)
+	r.Equal("bundle summarize", string(got))
+}
@@ -18,17 +18,55 @@ const App = "seaq"
 // ```go
 //	type SeaqConfig struct {
 //		Connections []struct {
-//			BaseURL  string `yaml:"base_url"`
-//			Provider string `yaml:"provider"`
+//			BaseURL   string   `yaml:"base_url"`
+//			Provider  string   `yaml:"provider"`
+//			EnvKey    string   `yaml:"env_key"`
+//			Models    []string `yaml:"models"`
+//			Include   []string `yaml:"include"`
+//			Exclude   []string `yaml:"exclude"`
+//			Overrides []struct {
+//				Model      string `yaml:"model"`
+//				Alias      string `yaml:"alias"`
+//				SystemRole string `yaml:"system_role"`
+//			} `yaml:"overrides"`
+//			SystemRole string `yaml:"system_role"`
 //		} `yaml:"connections"`
 //		Model struct {
 //			Name string `yaml:"name"`
 //		} `yaml:"model"`
+//		Ollama struct {
+//			KeepAlive string `yaml:"keep_alive"`
+//			NumCtx    int    `yaml:"num_ctx"`
+//			NumGPU    *int   `yaml:"num_gpu"`
+//			AutoPull  bool   `yaml:"auto_pull"`
+//		} `yaml:"ollama"`
 //		Pattern struct {
 //			Name   string `yaml:"name"`
 //			Repo   string `yaml:"repo"`
-//			Remote string `yaml:"remote"`
+//			Repos  []struct {
+//				Name string `yaml:"name"`
+//				Path string `yaml:"path"`
+//			} `yaml:"repos"`
+//			Remote string `yaml:"remote"` // GitHub or git URL, archive or local directory, optionally pinned with @ref
 //		} `yaml:"pattern"`
+//		Strategy struct {
+//			Dir string `yaml:"dir"` // defaults to the strategies directory next to the config file
+//		} `yaml:"strategy"`
+//		Suggest struct {
+//			Method         string `yaml:"method"`          // classifier or embedding, defaults to classifier
+//			Model          string `yaml:"model"`           // classifier model, defaults to model.name
+//			EmbeddingModel string `yaml:"embedding_model"` // defaults to openai/text-embedding-3-small
+//		} `yaml:"suggest"`
+//		Transcription struct {
+//			Backend  string `yaml:"backend"`  // openai, a connection's provider or whisper.cpp, defaults to openai
+//			Model    string `yaml:"model"`    // defaults to whisper-1 for openai, a ggml model file for whisper.cpp
+//			Language string `yaml:"language"` // defaults to the detected language
+//			Binary   string `yaml:"binary"`   // whisper.cpp binary, defaults to whisper-cli
+//		} `yaml:"transcription"`
+//		SystemRoles []struct {
+//			Model string `yaml:"model"`
+//			Role  string `yaml:"role"`
+//		} `yaml:"system_roles"`
 //	}
 // ```
 //
@@ -38,14 +76,41 @@ const App = "seaq"
 // connections:
 //   - base_url: https://api.groq.com/openai/v1
 //     provider: groq
+//   - base_url: http://localhost:8080/v1
+//     provider: local
+//     system_role: merge
 //   - base_url: https://openrouter.ai/api/v1
 //     provider: openrouter
+//     include: ["anthropic/*"]
+//     exclude: ["*:free"]
+//     overrides:
+//       - model: anthropic/claude-sonnet-4
+//         alias: sonnet
 // model:
 //   name: anthropic/claude-3-5-sonnet-latest
+// ollama:
+//   keep_alive: 30m
+//   num_ctx: 8192
+//   num_gpu: 0
+//   auto_pull: true
 // pattern:
 //   name: take_note
 //   repo: /home/user/.config/seaq/patterns
+//   repos:
+//     - name: team
+//       path: /mnt/shared/team-patterns
 //   remote: https://github.com/danielmiessler/fabric
+// strategy:
+//   dir: /home/user/.config/seaq/strategies
+// suggest:
+//   method: classifier
+//   model: groq/llama-3.1-8b-instant
+// transcription:
+//   backend: groq
+//   model: whisper-large-v3
+// system_roles:
+//   - model: openai/o3*
+//     role: developer
 // ```
 
 // flagBindings maps CLI flags to their corresponding config keys
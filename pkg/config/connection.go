@@ -26,6 +26,20 @@ func AddConnection(conn llm.Connection) error {
 	return viper.WriteConfig()
 }
 
+func UpdateConnection(conn llm.Connection) error {
+	cs, err := llm.GetConnectionSet()
+	if err != nil {
+		return err
+	}
+
+	if !cs.Update(conn) {
+		return fmt.Errorf("connection %q not found", conn.Provider)
+	}
+
+	viper.Set("connections", cs.AsSlice())
+	return viper.WriteConfig()
+}
+
 func RemoveConnection(providers ...string) error {
 	cs, err := llm.GetConnectionSet()
 	if err != nil {
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// PartialExt is the extension of partial files, which hold prompt sections shared by patterns.
+const PartialExt = ".md"
+
+// maxIncludeDepth bounds nested includes, well beyond any reasonable use.
+const maxIncludeDepth = 16
+
+// ErrIncludeCycle is returned when a prompt includes itself, directly or not.
+var ErrIncludeCycle = errors.New("include cycle")
+
+// includeRe matches include directives, e.g. {{ include "partials/markdown_notes" }}.
+var includeRe = regexp.MustCompile(`\{\{\s*include\s+"([^"]*)"\s*\}\}`)
+
+// ResolveIncludes replaces the include directives of a pattern's prompt
+// with the content they refer to, see ResolvePrompt.
+func ResolveIncludes(p PatternContent) (PatternContent, error) {
+	repos, err := PatternRepos()
+	if err != nil {
+		return PatternContent{}, err
+	}
+
+	if p.Prompt, err = ResolvePrompt(repos, p.Name, p.Prompt); err != nil {
+		return PatternContent{}, err
+	}
+	return p, nil
+}
+
+// ResolvePrompt replaces the include directives of a prompt, such as
+// {{ include "partials/markdown_notes" }}, with the content they refer to.
+//
+// An include refers either to a partial file, e.g. partials/markdown_notes.md,
+// or to another pattern, whose prompt is included without its front matter.
+// It's looked up in repos in order, like patterns, and can be qualified with a source,
+// e.g. {{ include "team:partials/tone" }}. Includes are resolved recursively.
+//
+// name identifies the prompt in errors and cycle detection.
+func ResolvePrompt(repos []PatternRepo, name string, prompt string) (string, error) {
+	return resolveIncludes(repos, prompt, []string{name})
+}
+
+func resolveIncludes(repos []PatternRepo, prompt string, stack []string) (string, error) {
+	if len(stack) > maxIncludeDepth {
+		return "", fmt.Errorf("includes nested too deep: %s", strings.Join(stack, " -> "))
+	}
+
+	var resolveErr error
+	resolved := includeRe.ReplaceAllStringFunc(prompt, func(directive string) string {
+		if resolveErr != nil {
+			return directive
+		}
+
+		name := includeRe.FindStringSubmatch(directive)[1]
+		for _, s := range stack {
+			if s == name {
+				resolveErr = fmt.Errorf("%w: %s -> %s", ErrIncludeCycle, strings.Join(stack, " -> "), name)
+				return directive
+			}
+		}
+
+		content, err := readInclude(repos, name)
+		if err != nil {
+			resolveErr = fmt.Errorf("including %q in %q: %w", name, stack[len(stack)-1], err)
+			return directive
+		}
+
+		content, err = resolveIncludes(repos, content, append(stack[:len(stack):len(stack)], name))
+		if err != nil {
+			resolveErr = err
+			return directive
+		}
+
+		// the directive usually stands on its own line, which already ends with a newline
+		return strings.TrimRight(content, "\r\n")
+	})
+	if resolveErr != nil {
+		return "", resolveErr
+	}
+
+	return resolved, nil
+}
+
+// readInclude reads the content an include refers to, from the first repository providing it.
+func readInclude(repos []PatternRepo, qualified string) (string, error) {
+	source, name := SplitPatternName(qualified)
+	if err := ValidatePatternName(name); err != nil {
+		return "", err
+	}
+
+	found := false
+	for _, r := range repos {
+		if source != "" && r.Name != source {
+			continue
+		}
+		found = true
+
+		data, err := fs.ReadFile(r.FS, name+PartialExt)
+		if err == nil {
+			_, content, err := splitFrontMatter(string(data))
+			return content, err
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return "", err
+		}
+
+		if r.Has(name) {
+			p, err := readPattern(r.FS, name)
+			return p.Prompt, err
+		}
+	}
+
+	if source != "" && !found {
+		return "", &Unsupported{Type: "pattern source", Key: source}
+	}
+	return "", fmt.Errorf("no partial %s nor pattern %s found", path.Clean(name)+PartialExt, name)
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolvePrompt(t *testing.T) {
+	personal := PatternRepo{
+		Name: PersonalSource,
+		FS: fstest.MapFS{
+			"partials/tone.md":    {Data: []byte("Be concise.\n")},
+			"partials/notes.md":   {Data: []byte("---\ndescription: notes\n---\n# NOTES\n{{ include \"partials/tone\" }}\n")},
+			"partials/loop_a.md":  {Data: []byte("{{ include \"partials/loop_b\" }}")},
+			"partials/loop_b.md":  {Data: []byte("{{ include \"partials/loop_a\" }}")},
+			"summarize/system.md": {Data: []byte("---\ndescription: summarize\n---\nSummarize.")},
+			"recursive/system.md": {Data: []byte("{{ include \"recursive\" }}")},
+		},
+	}
+	team := PatternRepo{
+		Name: "team",
+		FS: fstest.MapFS{
+			"partials/tone.md":   {Data: []byte("Be friendly.")},
+			"partials/footer.md": {Data: []byte("-- the team")},
+		},
+	}
+	repos := []PatternRepo{personal, team}
+
+	testCases := []struct {
+		name    string
+		prompt  string
+		want    string
+		wantErr error
+	}{
+		{
+			name:   "no include",
+			prompt: "Translate {{ .language }}.",
+			want:   "Translate {{ .language }}.",
+		},
+		{
+			name:   "partial",
+			prompt: "# TONE\n{{ include \"partials/tone\" }}\n# INPUT",
+			want:   "# TONE\nBe concise.\n# INPUT",
+		},
+		{
+			name:   "nested, without front matter",
+			prompt: "{{include \"partials/notes\"}}",
+			want:   "# NOTES\nBe concise.",
+		},
+		{
+			name:   "pattern",
+			prompt: "{{ include \"summarize\" }}",
+			want:   "Summarize.",
+		},
+		{
+			name:   "later repository",
+			prompt: "{{ include \"partials/footer\" }}",
+			want:   "-- the team",
+		},
+		{
+			name:   "qualified with a source",
+			prompt: "{{ include \"team:partials/tone\" }}",
+			want:   "Be friendly.",
+		},
+		{
+			name:    "cycle",
+			prompt:  "{{ include \"partials/loop_a\" }}",
+			wantErr: ErrIncludeCycle,
+		},
+		{
+			name:    "self",
+			prompt:  "{{ include \"recursive\" }}",
+			wantErr: ErrIncludeCycle,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			got, err := ResolvePrompt(repos, "recursive", tt.prompt)
+			if tt.wantErr != nil {
+				r.ErrorIs(err, tt.wantErr)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+
+	for _, prompt := range []string{
+		`{{ include "partials/missing" }}`,
+		`{{ include "../partials/tone" }}`,
+		`{{ include "" }}`,
+		`{{ include "unknown:partials/tone" }}`,
+	} {
+		_, err := ResolvePrompt(repos, "test", prompt)
+		r.Error(err, prompt)
+	}
+}
+
+func (s *PatternRepoTestSuite) TestGetPattern_Includes() {
+	r := s.Require()
+
+	s.writePattern(s.personal, "notes", "# NOTES\n{{ include \"partials/format\" }}\n")
+	writeFile(s.T(), filepath.Join(s.team, "partials", "format.md"), "Use bullet points.\n")
+	viper.Set("pattern.name", "notes")
+
+	prompt, err := GetPrompt()
+	r.NoError(err)
+	r.Equal("# NOTES\nUse bullet points.\n", prompt)
+}
@@ -0,0 +1,150 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+// ManifestFile is the name of the file, at the root of a pattern repository,
+// that tracks which upstream version each downloaded pattern comes from.
+const ManifestFile = ".seaq-manifest.json"
+
+// Manifest records the upstream version of patterns downloaded from a remote.
+type Manifest struct {
+	// Remote is the URL of the remote the patterns were last downloaded from,
+	// including the ref it is pinned to, if any.
+	Remote string `json:"remote"`
+	// Patterns maps pattern names to their upstream version.
+	Patterns map[string]ManifestEntry `json:"patterns"`
+}
+
+// ManifestEntry is the upstream version of a downloaded pattern.
+type ManifestEntry struct {
+	// SHA is the git blob SHA of the pattern's system.md when it was downloaded.
+	SHA string `json:"sha"`
+	// Revision is the SHA of the upstream commit the pattern was downloaded from,
+	// empty if the remote is not versioned.
+	Revision string `json:"revision,omitempty"`
+}
+
+// LoadManifest reads the manifest of a pattern repository.
+// An empty manifest is returned if the repository has none.
+func LoadManifest(repo string) (Manifest, error) {
+	m := Manifest{Patterns: make(map[string]ManifestEntry)}
+
+	data, err := os.ReadFile(filepath.Join(repo, ManifestFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return m, nil
+		}
+		return Manifest{}, err
+	}
+
+	if err := json.Unmarshal(data, &m); err != nil {
+		return Manifest{}, err
+	}
+	if m.Patterns == nil {
+		m.Patterns = make(map[string]ManifestEntry)
+	}
+
+	return m, nil
+}
+
+// Save writes the manifest to the root of a pattern repository.
+func (m Manifest) Save(repo string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(repo, ManifestFile), append(data, '\n'), 0o644)
+}
+
+// SwitchRemote makes the manifest track patterns from a new remote.
+// Patterns tracked from the previous remote that the new one doesn't have are untracked,
+// so that syncing leaves them alone instead of removing them.
+func (m *Manifest) SwitchRemote(remote string, upstream map[string]string) {
+	maps.DeleteFunc(m.Patterns, func(name string, _ ManifestEntry) bool {
+		_, ok := upstream[name]
+		return !ok
+	})
+	m.Remote = remote
+}
+
+// SyncStatus is the outcome of syncing a pattern with its remote.
+type SyncStatus string
+
+const (
+	// SyncAdded means the pattern is new upstream, or missing locally.
+	SyncAdded SyncStatus = "added"
+	// SyncUpdated means the pattern changed upstream and is unmodified locally.
+	SyncUpdated SyncStatus = "updated"
+	// SyncRemoved means the pattern was removed upstream and is unmodified locally.
+	SyncRemoved SyncStatus = "removed"
+	// SyncUnchanged means the local pattern matches upstream.
+	SyncUnchanged SyncStatus = "unchanged"
+	// SyncModified means the pattern was edited locally and is left alone.
+	SyncModified SyncStatus = "modified"
+)
+
+// SyncItem describes how a pattern is synced.
+type SyncItem struct {
+	Name   string
+	Status SyncStatus
+	// Upstream is the blob SHA of the upstream pattern, empty if it was removed upstream.
+	Upstream string
+	// Local is the blob SHA of the local pattern, empty if it doesn't exist.
+	Local string
+}
+
+// PlanSync decides how each pattern is synced with its remote.
+//
+// upstream and local map pattern names to the blob SHA of their system.md,
+// remotely and in the local repository. The manifest tells which upstream version
+// a local pattern was downloaded from, so that local edits can be told apart from upstream changes.
+// Local patterns that were never downloaded from the remote are ignored.
+func PlanSync(upstream map[string]string, local map[string]string, m Manifest) []SyncItem {
+	names := make(map[string]struct{})
+	for name := range upstream {
+		names[name] = struct{}{}
+	}
+	for name := range m.Patterns {
+		names[name] = struct{}{}
+	}
+
+	items := make([]SyncItem, 0, len(names))
+	for _, name := range slices.Sorted(maps.Keys(names)) {
+		up, inUpstream := upstream[name]
+		loc := local[name]
+		entry, tracked := m.Patterns[name]
+
+		item := SyncItem{Name: name, Upstream: up, Local: loc}
+
+		switch {
+		case !inUpstream:
+			// tracked, but removed upstream
+			if loc == "" || loc == entry.SHA {
+				item.Status = SyncRemoved
+			} else {
+				item.Status = SyncModified
+			}
+		case loc == "":
+			item.Status = SyncAdded
+		case loc == up:
+			item.Status = SyncUnchanged
+		case tracked && loc == entry.SHA:
+			item.Status = SyncUpdated
+		default:
+			// edited locally, or an untracked local pattern with the same name
+			item.Status = SyncModified
+		}
+
+		items = append(items, item)
+	}
+
+	return items
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestManifest_LoadSave(t *testing.T) {
+	r := require.New(t)
+
+	repo := t.TempDir()
+
+	// a repository without manifest has an empty one
+	m, err := LoadManifest(repo)
+	r.NoError(err)
+	r.Empty(m.Remote)
+	r.Empty(m.Patterns)
+
+	m.Remote = "https://github.com/owner/repo/tree/main/patterns"
+	m.Patterns["summarize"] = ManifestEntry{SHA: "abc"}
+	r.NoError(m.Save(repo))
+
+	got, err := LoadManifest(repo)
+	r.NoError(err)
+	r.Equal(m, got)
+}
+
+func TestManifest_SwitchRemote(t *testing.T) {
+	r := require.New(t)
+
+	m := Manifest{
+		Remote: "https://github.com/owner/old",
+		Patterns: map[string]ManifestEntry{
+			"summarize":   {SHA: "s1"},
+			"old/only":    {SHA: "o1"},
+			"old/another": {SHA: "a1"},
+		},
+	}
+	upstream := map[string]string{"summarize": "s2", "new": "n1"}
+
+	m.SwitchRemote("https://github.com/owner/new", upstream)
+	r.Equal("https://github.com/owner/new", m.Remote)
+	r.Equal(map[string]ManifestEntry{"summarize": {SHA: "s1"}}, m.Patterns)
+
+	// patterns only tracked from the old remote are no longer planned for removal
+	local := map[string]string{"summarize": "s1", "old/only": "o1", "old/another": "a1"}
+	for _, item := range PlanSync(upstream, local, m) {
+		r.NotEqual(SyncRemoved, item.Status, item.Name)
+	}
+}
+
+func TestPlanSync(t *testing.T) {
+	upstream := map[string]string{
+		"new":          "n1",
+		"missing":      "m2",
+		"same":         "s1",
+		"changed":      "c2",
+		"edited":       "e2",
+		"untracked":    "u1",
+		"edited/again": "a1",
+	}
+	local := map[string]string{
+		"same":         "s1",
+		"changed":      "c1",
+		"edited":       "e9",
+		"untracked":    "u9",
+		"edited/again": "a9",
+		"gone":         "g1",
+		"gone/edited":  "x9",
+	}
+	manifest := Manifest{Patterns: map[string]ManifestEntry{
+		"missing":      {SHA: "m1"},
+		"same":         {SHA: "s0"},
+		"changed":      {SHA: "c1"},
+		"edited":       {SHA: "e1"},
+		"edited/again": {SHA: "a1"},
+		"gone":         {SHA: "g1"},
+		"gone/deleted": {SHA: "d1"},
+		"gone/edited":  {SHA: "x1"},
+	}}
+
+	r := require.New(t)
+
+	got := PlanSync(upstream, local, manifest)
+	r.Equal([]SyncItem{
+		{Name: "changed", Status: SyncUpdated, Upstream: "c2", Local: "c1"},
+		{Name: "edited", Status: SyncModified, Upstream: "e2", Local: "e9"},
+		{Name: "edited/again", Status: SyncModified, Upstream: "a1", Local: "a9"},
+		{Name: "gone", Status: SyncRemoved, Local: "g1"},
+		{Name: "gone/deleted", Status: SyncRemoved},
+		{Name: "gone/edited", Status: SyncModified, Local: "x9"},
+		{Name: "missing", Status: SyncAdded, Upstream: "m2"},
+		{Name: "new", Status: SyncAdded, Upstream: "n1"},
+		{Name: "same", Status: SyncUnchanged, Upstream: "s1", Local: "s1"},
+		{Name: "untracked", Status: SyncModified, Upstream: "u1", Local: "u9"},
+	}, got)
+}
@@ -0,0 +1,163 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PatternMetaFile is the name of the optional file holding a pattern's metadata,
+// as an alternative to front matter in system.md.
+const PatternMetaFile = "pattern.yaml"
+
+// frontMatterDelim delimits YAML front matter at the top of system.md.
+const frontMatterDelim = "---"
+
+// PatternMeta describes a pattern and how it should be run.
+//
+// Metadata is read from YAML front matter at the top of system.md,
+// or from a pattern.yaml file next to it, which takes precedence.
+//
+// ```yaml
+// description: Summarize a piece of content
+// tags: [writing, summary]
+// model: anthropic/claude-sonnet-4-5
+// temperature: 0.3
+// variables: [language]
+// input: text
+// ```
+type PatternMeta struct {
+	// Description is a short summary of what the pattern does.
+	Description string `yaml:"description" json:"description,omitempty"`
+	// Tags categorize the pattern, e.g. "writing" or "code".
+	Tags []string `yaml:"tags" json:"tags,omitempty"`
+	// Model is the recommended model, used unless --model is set.
+	Model string `yaml:"model" json:"model,omitempty"`
+	// Temperature is the default temperature, used unless --temperature is set.
+	Temperature *float64 `yaml:"temperature" json:"temperature,omitempty"`
+	// Variables are the names of the variables the prompt requires, set with --var.
+	Variables []string `yaml:"variables" json:"variables,omitempty"`
+	// Input is the kind of input the pattern expects, e.g. "text", "code" or "transcript".
+	Input string `yaml:"input" json:"input,omitempty"`
+}
+
+// HasTag reports whether the pattern has a tag, ignoring case.
+func (m PatternMeta) HasTag(tag string) bool {
+	return slices.ContainsFunc(m.Tags, func(t string) bool {
+		return strings.EqualFold(t, tag)
+	})
+}
+
+// PatternContent is a pattern's prompt and metadata.
+type PatternContent struct {
+	Name   string
+	Prompt string
+	Meta   PatternMeta
+}
+
+// Render returns the prompt with its variables replaced by their values.
+//
+// Prompts of patterns declaring variables are Go templates, where variables are referenced
+// as {{ .name }}. Prompts without variables are returned as is.
+func (p PatternContent) Render(vars map[string]string) (string, error) {
+	if len(p.Meta.Variables) == 0 {
+		return p.Prompt, nil
+	}
+
+	for _, v := range p.Meta.Variables {
+		if _, ok := vars[v]; !ok {
+			return "", fmt.Errorf("pattern %q requires variable %q, set it with --var %s=<value>", p.Name, v, v)
+		}
+	}
+
+	tmpl, err := template.New(p.Name).Option("missingkey=error").Parse(p.Prompt)
+	if err != nil {
+		return "", fmt.Errorf("parsing pattern %q: %w", p.Name, err)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("rendering pattern %q: %w", p.Name, err)
+	}
+
+	return buf.String(), nil
+}
+
+// readPattern reads the prompt and metadata of a pattern in a file system.
+func readPattern(fsys fs.FS, name string) (PatternContent, error) {
+	content, err := fs.ReadFile(fsys, path.Join(name, PatternFile))
+	if err != nil {
+		return PatternContent{}, err
+	}
+
+	meta, prompt, err := splitFrontMatter(string(content))
+	if err != nil {
+		return PatternContent{}, fmt.Errorf("reading front matter of pattern %q: %w", name, err)
+	}
+
+	data, err := fs.ReadFile(fsys, path.Join(name, PatternMetaFile))
+	switch {
+	case err == nil:
+		if meta, err = parseMeta(data); err != nil {
+			return PatternContent{}, fmt.Errorf("reading %s of pattern %q: %w", PatternMetaFile, name, err)
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return PatternContent{}, err
+	}
+
+	return PatternContent{Name: name, Prompt: prompt, Meta: meta}, nil
+}
+
+// splitFrontMatter separates the YAML front matter of a prompt from its content.
+// A prompt without front matter has empty metadata.
+func splitFrontMatter(content string) (PatternMeta, string, error) {
+	rest, ok := cutLine(content, frontMatterDelim)
+	if !ok {
+		return PatternMeta{}, content, nil
+	}
+
+	// find the closing delimiter, on a line of its own
+	var front strings.Builder
+	for rest != "" {
+		line, next, _ := strings.Cut(rest, "\n")
+		rest = next
+		if strings.TrimRight(line, "\r") == frontMatterDelim {
+			meta, err := parseMeta([]byte(front.String()))
+			return meta, strings.TrimLeft(rest, "\r\n"), err
+		}
+		front.WriteString(line)
+		front.WriteByte('\n')
+	}
+
+	return PatternMeta{}, "", errors.New("front matter is not closed")
+}
+
+// cutLine removes the first line of s if it is exactly line.
+func cutLine(s string, line string) (string, bool) {
+	first, rest, found := strings.Cut(s, "\n")
+	if !found || strings.TrimRight(first, "\r") != line {
+		return s, false
+	}
+	return rest, true
+}
+
+func parseMeta(data []byte) (PatternMeta, error) {
+	var meta PatternMeta
+
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	// catch typos in field names
+	dec.KnownFields(true)
+	if err := dec.Decode(&meta); err != nil && !errors.Is(err, io.EOF) {
+		return PatternMeta{}, err
+	}
+
+	return meta, nil
+}
@@ -0,0 +1,192 @@
+package config
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitFrontMatter(t *testing.T) {
+	temperature := 0.3
+
+	testCases := []struct {
+		name       string
+		content    string
+		wantMeta   PatternMeta
+		wantPrompt string
+		wantErr    bool
+	}{
+		{
+			name:       "no front matter",
+			content:    "# IDENTITY\nYou summarize.",
+			wantPrompt: "# IDENTITY\nYou summarize.",
+		},
+		{
+			name: "front matter",
+			content: `---
+description: Summarize content
+tags: [writing, summary]
+model: openai/gpt-4o
+temperature: 0.3
+variables: [language]
+input: text
+---
+
+# IDENTITY
+You summarize.`,
+			wantMeta: PatternMeta{
+				Description: "Summarize content",
+				Tags:        []string{"writing", "summary"},
+				Model:       "openai/gpt-4o",
+				Temperature: &temperature,
+				Variables:   []string{"language"},
+				Input:       "text",
+			},
+			wantPrompt: "# IDENTITY\nYou summarize.",
+		},
+		{
+			name:       "crlf",
+			content:    "---\r\ndescription: Summarize\r\n---\r\nprompt",
+			wantMeta:   PatternMeta{Description: "Summarize"},
+			wantPrompt: "prompt",
+		},
+		{
+			name:       "empty front matter",
+			content:    "---\n---\nprompt",
+			wantPrompt: "prompt",
+		},
+		{
+			name:       "horizontal rule later",
+			content:    "prompt\n---\nmore",
+			wantPrompt: "prompt\n---\nmore",
+		},
+		{
+			name:    "not closed",
+			content: "---\ndescription: Summarize\nprompt",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			content: "---\ndescripton: Summarize\n---\nprompt",
+			wantErr: true,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			meta, prompt, err := splitFrontMatter(tt.content)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.wantMeta, meta)
+			r.Equal(tt.wantPrompt, prompt)
+		})
+	}
+}
+
+func TestReadPattern(t *testing.T) {
+	fsys := fstest.MapFS{
+		"front/system.md":   {Data: []byte("---\ndescription: from front matter\n---\nfront prompt")},
+		"file/system.md":    {Data: []byte("---\ndescription: from front matter\n---\nfile prompt")},
+		"file/pattern.yaml": {Data: []byte("description: from file\ntags: [code]\n")},
+		"bare/system.md":    {Data: []byte("bare prompt")},
+	}
+
+	testCases := []struct {
+		name       string
+		pattern    string
+		wantMeta   PatternMeta
+		wantPrompt string
+	}{
+		{
+			name:       "front matter",
+			pattern:    "front",
+			wantMeta:   PatternMeta{Description: "from front matter"},
+			wantPrompt: "front prompt",
+		},
+		{
+			name:       "pattern.yaml takes precedence",
+			pattern:    "file",
+			wantMeta:   PatternMeta{Description: "from file", Tags: []string{"code"}},
+			wantPrompt: "file prompt",
+		},
+		{
+			name:       "no metadata",
+			pattern:    "bare",
+			wantPrompt: "bare prompt",
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			p, err := readPattern(fsys, tt.pattern)
+			r.NoError(err)
+			r.Equal(tt.pattern, p.Name)
+			r.Equal(tt.wantMeta, p.Meta)
+			r.Equal(tt.wantPrompt, p.Prompt)
+		})
+	}
+}
+
+func TestPatternContent_Render(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern PatternContent
+		vars    map[string]string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "no variables is left as is",
+			pattern: PatternContent{Prompt: "keep {{ .language }} as is"},
+			want:    "keep {{ .language }} as is",
+		},
+		{
+			name: "variables",
+			pattern: PatternContent{
+				Prompt: "Translate to {{ .language }}.",
+				Meta:   PatternMeta{Variables: []string{"language"}},
+			},
+			vars: map[string]string{"language": "French"},
+			want: "Translate to French.",
+		},
+		{
+			name: "missing variable",
+			pattern: PatternContent{
+				Prompt: "Translate to {{ .language }}.",
+				Meta:   PatternMeta{Variables: []string{"language"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "undeclared variable",
+			pattern: PatternContent{
+				Prompt: "Translate to {{ .language }} in {{ .tone }} tone.",
+				Meta:   PatternMeta{Variables: []string{"language"}},
+			},
+			vars:    map[string]string{"language": "French"},
+			wantErr: true,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			got, err := tt.pattern.Render(tt.vars)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+}
@@ -2,12 +2,20 @@ package config
 
 import (
 	"errors"
+	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/spf13/viper"
 )
 
+// PatternFile is the name of the file holding a pattern's prompt.
+const PatternFile = "system.md"
+
 // region: --- errors
 
 var (
@@ -25,19 +33,11 @@ func Pattern() string {
 	return viper.GetString("pattern.name")
 }
 
+// HasPattern reports whether a pattern is available in any repository.
+// The name can be qualified with a source, e.g. "team:review".
 func HasPattern(name string) bool {
-	pats, err := ListPatterns()
-	if err != nil {
-		return false
-	}
-
-	for _, p := range pats {
-		if p == name {
-			return true
-		}
-	}
-
-	return false
+	_, _, err := FindPattern(name, "")
+	return err == nil
 }
 
 func UsePattern(name string) error {
@@ -48,52 +48,135 @@ func UsePattern(name string) error {
 	return nil
 }
 
-func GetPrompt() (string, error) {
+// GetPattern reads the prompt and metadata of the default pattern
+// from the first repository that provides it, see PatternRepos.
+// The includes of the prompt are resolved, see ResolvePrompt.
+func GetPattern() (PatternContent, error) {
 	pat := Pattern()
 	if pat == "" {
-		return "", ErrEmptyPattern
+		return PatternContent{}, ErrEmptyPattern
 	}
 
-	repo := Repo()
-	if repo == "" {
-		return "", ErrEmptyRepo
+	repo, name, err := FindPattern(pat, "")
+	if err != nil {
+		return PatternContent{}, err
 	}
 
-	path := filepath.Join(repo, pat, "system.md")
+	p, err := repo.ReadPattern(name)
+	if err != nil {
+		return PatternContent{}, err
+	}
+	return ResolveIncludes(p)
+}
 
-	prompt, err := os.ReadFile(path) // read the pattern
+// GetPrompt reads the prompt of the default pattern, without its front matter
+// and with its includes resolved.
+func GetPrompt() (string, error) {
+	p, err := GetPattern()
 	if err != nil {
-		if os.IsNotExist(err) {
-			return "", &Unsupported{Type: "pattern", Key: pat}
-		}
 		return "", err
 	}
-
-	return string(prompt), nil
+	return p.Prompt, nil
 }
 
-// ListPatterns returns a list of available patterns
-// in the repository specified in the config.
+// ListPatterns returns a sorted list of available patterns
+// across all repositories, without duplicates.
 func ListPatterns() ([]string, error) {
-	return ListPatternsInRepo(Repo())
+	entries, err := ListPatternEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	pats := make([]string, 0, len(entries))
+	for _, e := range entries {
+		if !e.Shadowed() {
+			pats = append(pats, e.Name)
+		}
+	}
+	return pats, nil
 }
 
-// ListPatternsInRepo returns a list of available patterns in a given repository
+// ValidatePatternName checks that a pattern name is a relative, slash-separated path
+// that stays within the pattern repository, e.g. "summarize" or "team/review/security".
+func ValidatePatternName(name string) error {
+	if name == "" {
+		return ErrEmptyPattern
+	}
+
+	if strings.Contains(name, "\\") || path.IsAbs(name) || filepath.IsAbs(name) {
+		return fmt.Errorf("invalid pattern name %q: must be a relative path separated by '/'", name)
+	}
+
+	for _, seg := range strings.Split(name, "/") {
+		if seg == "" || seg == "." || seg == ".." {
+			return fmt.Errorf("invalid pattern name %q: empty, '.' and '..' segments are not allowed", name)
+		}
+	}
+
+	return nil
+}
+
+// PatternDir returns the directory of a pattern in a repository.
+// Nested patterns, such as "team/review/security", live in nested directories.
+func PatternDir(repo string, name string) (string, error) {
+	if err := ValidatePatternName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(repo, filepath.FromSlash(name)), nil
+}
+
+// PatternPath returns the path to the prompt file of a pattern in a repository.
+func PatternPath(repo string, name string) (string, error) {
+	dir, err := PatternDir(repo, name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, PatternFile), nil
+}
+
+// ListPatternsInRepo returns a sorted list of available patterns in a given repository.
+//
+// A pattern is a directory containing a system.md file, at any depth.
+// Nested patterns are named by their slash-separated path relative to the repository,
+// e.g. "team/review/security". Hidden directories are skipped.
 func ListPatternsInRepo(repo string) ([]string, error) {
 	if repo == "" {
 		return nil, ErrEmptyRepo
 	}
+	return ListPatternsInFS(os.DirFS(repo))
+}
+
+// ListPatternsInFS returns a sorted list of patterns in a file system,
+// following the same layout as ListPatternsInRepo.
+func ListPatternsInFS(fsys fs.FS) ([]string, error) {
+	var pats []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !d.IsDir() || p == "." {
+			return nil
+		}
 
-	dirs, err := os.ReadDir(repo)
+		if strings.HasPrefix(d.Name(), ".") {
+			return fs.SkipDir
+		}
+
+		info, err := fs.Stat(fsys, path.Join(p, PatternFile))
+		if err != nil || info.IsDir() {
+			// not a pattern, but it may contain nested patterns
+			return nil // nolint: nilerr
+		}
+
+		// fs.FS paths are always slash-separated
+		pats = append(pats, p)
+		return nil
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	pats := make([]string, 0, len(dirs))
-	for _, d := range dirs {
-		if d.IsDir() {
-			pats = append(pats, d.Name())
-		}
-	}
+	slices.Sort(pats)
 	return pats, nil
 }
@@ -0,0 +1,73 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidatePatternName(t *testing.T) {
+	testCases := []struct {
+		name    string
+		pattern string
+		wantErr bool
+	}{
+		{name: "flat", pattern: "summarize", wantErr: false},
+		{name: "nested", pattern: "team/review/security", wantErr: false},
+		{name: "empty", pattern: "", wantErr: true},
+		{name: "absolute", pattern: "/etc/passwd", wantErr: true},
+		{name: "parent", pattern: "../secrets", wantErr: true},
+		{name: "nested parent", pattern: "team/../../secrets", wantErr: true},
+		{name: "current", pattern: "./summarize", wantErr: true},
+		{name: "empty segment", pattern: "team//review", wantErr: true},
+		{name: "trailing slash", pattern: "team/", wantErr: true},
+		{name: "backslash", pattern: `team\review`, wantErr: true},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			err := ValidatePatternName(tt.pattern)
+			if tt.wantErr {
+				r.Error(err)
+			} else {
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func TestListPatternsInRepo(t *testing.T) {
+	r := require.New(t)
+
+	repo := t.TempDir()
+	for _, p := range []string{
+		"summarize",
+		"team/review/security",
+		"team/review/style",
+		"team/docs",
+		".git/hooks",
+	} {
+		dir := filepath.Join(repo, filepath.FromSlash(p))
+		r.NoError(os.MkdirAll(dir, 0o755))
+		r.NoError(os.WriteFile(filepath.Join(dir, PatternFile), []byte("prompt"), 0o644))
+	}
+
+	// a directory without system.md is not a pattern
+	r.NoError(os.MkdirAll(filepath.Join(repo, "empty"), 0o755))
+
+	got, err := ListPatternsInRepo(repo)
+	r.NoError(err)
+	r.Equal([]string{
+		"summarize",
+		"team/docs",
+		"team/review/security",
+		"team/review/style",
+	}, got)
+
+	_, err = ListPatternsInRepo("")
+	r.ErrorIs(err, ErrEmptyRepo)
+}
@@ -0,0 +1,268 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/spf13/viper"
+)
+
+const (
+	// PersonalSource is the name of the repository set by `pattern.repo`.
+	PersonalSource = "personal"
+	// ProjectSource is the name of the project-local repository.
+	ProjectSource = "project"
+)
+
+// ProjectRepo is the path of the project-local pattern repository,
+// relative to the working directory.
+var ProjectRepo = filepath.Join(".seaq", "patterns")
+
+// PatternRepo is a named source of patterns.
+type PatternRepo struct {
+	// Name identifies the repository, e.g. "personal" or "team".
+	Name string
+	// Path is the directory of the repository, empty for repositories only backed by FS,
+	// such as the built-in patterns.
+	Path string
+	// FS gives access to the content of the repository.
+	FS fs.FS
+}
+
+// NewPatternRepo creates a pattern repository backed by a directory.
+func NewPatternRepo(name string, dir string) PatternRepo {
+	return PatternRepo{
+		Name: name,
+		Path: dir,
+		FS:   os.DirFS(dir),
+	}
+}
+
+// Has reports whether the repository contains a pattern.
+func (r PatternRepo) Has(name string) bool {
+	if ValidatePatternName(name) != nil {
+		return false
+	}
+	info, err := fs.Stat(r.FS, path.Join(name, PatternFile))
+	return err == nil && !info.IsDir()
+}
+
+// ReadPattern reads the prompt and metadata of a pattern in the repository.
+func (r PatternRepo) ReadPattern(name string) (PatternContent, error) {
+	if err := ValidatePatternName(name); err != nil {
+		return PatternContent{}, err
+	}
+
+	p, err := readPattern(r.FS, name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return PatternContent{}, &Unsupported{Type: "pattern", Key: name}
+		}
+		return PatternContent{}, err
+	}
+
+	return p, nil
+}
+
+// ReadPrompt reads the prompt of a pattern in the repository, without its front matter.
+func (r PatternRepo) ReadPrompt(name string) (string, error) {
+	p, err := r.ReadPattern(name)
+	if err != nil {
+		return "", err
+	}
+	return p.Prompt, nil
+}
+
+// PromptPath returns the location of a pattern's prompt file, for display purposes.
+// Repositories without a directory, such as the built-in patterns, show it qualified with their name.
+func (r PatternRepo) PromptPath(name string) string {
+	if r.Path == "" {
+		return r.Name + ":" + path.Join(name, PatternFile)
+	}
+	return filepath.Join(r.Path, filepath.FromSlash(name), PatternFile)
+}
+
+// Builtin reports whether the repository holds the built-in patterns, which are read-only.
+func (r PatternRepo) Builtin() bool {
+	return r.Name == BuiltinSource
+}
+
+// List returns a sorted list of patterns in the repository.
+func (r PatternRepo) List() ([]string, error) {
+	return ListPatternsInFS(r.FS)
+}
+
+// repoConfig is a pattern repository as defined under `pattern.repos`.
+type repoConfig struct {
+	Name string `mapstructure:"name" yaml:"name"`
+	Path string `mapstructure:"path" yaml:"path"`
+}
+
+// PatternRepos returns the pattern repositories in lookup order:
+//  1. the personal repository, set by `pattern.repo`
+//  2. the shared repositories listed under `pattern.repos`
+//  3. the project-local repository, ./.seaq/patterns, if it exists
+//  4. the built-in patterns, see BuiltinRepo
+//
+// A pattern in an earlier repository shadows patterns with the same name in later ones.
+func PatternRepos() ([]PatternRepo, error) {
+	var repos []PatternRepo
+
+	if repo := Repo(); repo != "" {
+		repos = append(repos, NewPatternRepo(PersonalSource, repo))
+	}
+
+	var shared []repoConfig
+	if err := viper.UnmarshalKey("pattern.repos", &shared); err != nil {
+		return nil, fmt.Errorf("reading pattern.repos: %w", err)
+	}
+	for _, c := range shared {
+		if err := validateSourceName(c.Name); err != nil {
+			return nil, err
+		}
+		if c.Path == "" {
+			return nil, fmt.Errorf("pattern repository %q has no path", c.Name)
+		}
+		if slices.ContainsFunc(repos, func(r PatternRepo) bool { return r.Name == c.Name }) {
+			return nil, fmt.Errorf("duplicate pattern repository %q", c.Name)
+		}
+		repos = append(repos, NewPatternRepo(c.Name, c.Path))
+	}
+
+	if info, err := os.Stat(ProjectRepo); err == nil && info.IsDir() {
+		repos = append(repos, NewPatternRepo(ProjectSource, ProjectRepo))
+	}
+
+	return append(repos, BuiltinRepo()), nil
+}
+
+// validateSourceName checks the name of a shared pattern repository.
+func validateSourceName(name string) error {
+	switch {
+	case name == "":
+		return errors.New("pattern repository name is empty")
+	case name == PersonalSource || name == ProjectSource || name == BuiltinSource:
+		return fmt.Errorf("pattern repository name %q is reserved", name)
+	case strings.ContainsAny(name, ":/"):
+		return fmt.Errorf("pattern repository name %q must not contain ':' or '/'", name)
+	default:
+		return nil
+	}
+}
+
+// SplitPatternName splits a pattern name qualified with a source, such as "team:review",
+// into its source and name. The source is empty if the name is not qualified.
+func SplitPatternName(qualified string) (source string, name string) {
+	if source, name, ok := strings.Cut(qualified, ":"); ok {
+		return source, name
+	}
+	return "", qualified
+}
+
+// FindPattern returns the repository providing a pattern.
+//
+// If source is empty, the first repository containing the pattern is returned.
+// Otherwise, the pattern is only looked up in that source.
+// A pattern name qualified with a source (e.g. "team:review") takes precedence over source.
+func FindPattern(qualified string, source string) (PatternRepo, string, error) {
+	if src, name := SplitPatternName(qualified); src != "" {
+		source, qualified = src, name
+	}
+	name := qualified
+
+	if err := ValidatePatternName(name); err != nil {
+		return PatternRepo{}, "", err
+	}
+
+	repos, err := PatternRepos()
+	if err != nil {
+		return PatternRepo{}, "", err
+	}
+
+	if source != "" {
+		i := slices.IndexFunc(repos, func(r PatternRepo) bool { return r.Name == source })
+		if i == -1 {
+			return PatternRepo{}, "", &Unsupported{Type: "pattern source", Key: source}
+		}
+		repos = repos[i : i+1]
+	}
+
+	for _, r := range repos {
+		if r.Has(name) {
+			return r, name, nil
+		}
+	}
+
+	return PatternRepo{}, "", &Unsupported{Type: "pattern", Key: qualified}
+}
+
+// PatternEntry describes a pattern found in a repository.
+type PatternEntry struct {
+	Name   string
+	Source string
+	// ShadowedBy is the source of the pattern with the same name
+	// that takes precedence over this one, if any.
+	ShadowedBy string
+	// Meta is the metadata of the pattern.
+	Meta PatternMeta
+}
+
+// Shadowed reports whether another pattern takes precedence over this one.
+func (e PatternEntry) Shadowed() bool {
+	return e.ShadowedBy != ""
+}
+
+// ListPatternEntries returns all patterns of all repositories, sorted by name
+// and then by lookup order, with shadowed patterns marked.
+// A repository that can't be listed, e.g. a missing directory, is skipped with a warning,
+// and so is the metadata of a pattern that can't be read.
+func ListPatternEntries() ([]PatternEntry, error) {
+	repos, err := PatternRepos()
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []PatternEntry
+	// winners maps pattern names to the source they resolve to
+	winners := make(map[string]string)
+
+	for _, r := range repos {
+		pats, err := r.List()
+		if err != nil {
+			log.Warn("skipping pattern repository", "source", r.Name, "error", err)
+			continue
+		}
+
+		for _, p := range pats {
+			// a pattern with invalid metadata is still listed, it only fails when used
+			var meta PatternMeta
+			if pat, err := r.ReadPattern(p); err != nil {
+				log.Warn("listing pattern without its metadata", "pattern", p, "source", r.Name, "error", err)
+			} else {
+				meta = pat.Meta
+			}
+
+			e := PatternEntry{Name: p, Source: r.Name, Meta: meta}
+			if winner, ok := winners[p]; ok {
+				e.ShadowedBy = winner
+			} else {
+				winners[p] = r.Name
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	// stable sort keeps the lookup order for patterns with the same name
+	slices.SortStableFunc(entries, func(a, b PatternEntry) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return entries, nil
+}
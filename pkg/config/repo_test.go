@@ -0,0 +1,316 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type PatternRepoTestSuite struct {
+	suite.Suite
+	personal string
+	team     string
+	project  string
+}
+
+func TestPatternRepoTestSuite(t *testing.T) {
+	suite.Run(t, new(PatternRepoTestSuite))
+}
+
+func (s *PatternRepoTestSuite) writePattern(repo, name, prompt string) {
+	dir := filepath.Join(repo, filepath.FromSlash(name))
+	s.Require().NoError(os.MkdirAll(dir, 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(dir, PatternFile), []byte(prompt), 0o644))
+}
+
+func (s *PatternRepoTestSuite) SetupTest() {
+	viper.Reset()
+
+	root := s.T().TempDir()
+	s.personal = filepath.Join(root, "personal")
+	s.team = filepath.Join(root, "team")
+	s.project = filepath.Join(root, "project")
+
+	s.writePattern(s.personal, "summarize", "personal summarize")
+	s.writePattern(s.team, "summarize", "team summarize")
+	s.writePattern(s.team, "review/security", "team security")
+	s.writePattern(s.project, "review/security", "project security")
+	s.writePattern(s.project, "release_notes", "project release notes")
+
+	viper.Set("pattern.repo", s.personal)
+	viper.Set("pattern.repos", []map[string]any{
+		{"name": "team", "path": s.team},
+	})
+	ProjectRepo = s.project
+}
+
+func (s *PatternRepoTestSuite) TearDownTest() {
+	viper.Reset()
+	ProjectRepo = filepath.Join(".seaq", "patterns")
+}
+
+func (s *PatternRepoTestSuite) TestPatternRepos() {
+	r := s.Require()
+
+	repos, err := PatternRepos()
+	r.NoError(err)
+
+	names := make([]string, len(repos))
+	for i, repo := range repos {
+		names[i] = repo.Name
+	}
+	r.Equal([]string{PersonalSource, "team", ProjectSource, BuiltinSource}, names)
+}
+
+func (s *PatternRepoTestSuite) TestPatternRepos_Invalid() {
+	testCases := []struct {
+		name  string
+		repos []map[string]any
+	}{
+		{name: "empty name", repos: []map[string]any{{"path": s.team}}},
+		{name: "reserved name", repos: []map[string]any{{"name": PersonalSource, "path": s.team}}},
+		{name: "reserved builtin name", repos: []map[string]any{{"name": BuiltinSource, "path": s.team}}},
+		{name: "invalid name", repos: []map[string]any{{"name": "a:b", "path": s.team}}},
+		{name: "no path", repos: []map[string]any{{"name": "team"}}},
+		{
+			name: "duplicate",
+			repos: []map[string]any{
+				{"name": "team", "path": s.team},
+				{"name": "team", "path": s.project},
+			},
+		},
+	}
+
+	r := s.Require()
+
+	for _, tt := range testCases {
+		s.Run(tt.name, func() {
+			viper.Set("pattern.repos", tt.repos)
+			_, err := PatternRepos()
+			r.Error(err)
+		})
+	}
+}
+
+func (s *PatternRepoTestSuite) TestFindPattern() {
+	testCases := []struct {
+		name       string
+		pattern    string
+		source     string
+		wantSource string
+		wantPrompt string
+		wantErr    bool
+	}{
+		{
+			name:       "personal shadows team",
+			pattern:    "summarize",
+			wantSource: PersonalSource,
+			wantPrompt: "personal summarize",
+		},
+		{
+			name:       "team shadows project",
+			pattern:    "review/security",
+			wantSource: "team",
+			wantPrompt: "team security",
+		},
+		{
+			name:       "project only",
+			pattern:    "release_notes",
+			wantSource: ProjectSource,
+			wantPrompt: "project release notes",
+		},
+		{
+			name:       "forced source",
+			pattern:    "summarize",
+			source:     "team",
+			wantSource: "team",
+			wantPrompt: "team summarize",
+		},
+		{
+			name:       "qualified name",
+			pattern:    "project:review/security",
+			wantSource: ProjectSource,
+			wantPrompt: "project security",
+		},
+		{
+			name:    "not in forced source",
+			pattern: "release_notes",
+			source:  "team",
+			wantErr: true,
+		},
+		{
+			name:    "unknown source",
+			pattern: "summarize",
+			source:  "unknown",
+			wantErr: true,
+		},
+		{
+			name:    "unknown pattern",
+			pattern: "unknown",
+			wantErr: true,
+		},
+	}
+
+	r := s.Require()
+
+	for _, tt := range testCases {
+		s.Run(tt.name, func() {
+			repo, name, err := FindPattern(tt.pattern, tt.source)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.wantSource, repo.Name)
+
+			prompt, err := repo.ReadPrompt(name)
+			r.NoError(err)
+			r.Equal(tt.wantPrompt, prompt)
+		})
+	}
+}
+
+func (s *PatternRepoTestSuite) TestListPatternEntries() {
+	r := s.Require()
+
+	entries, err := ListPatternEntries()
+	r.NoError(err)
+	r.Equal([]PatternEntry{
+		{Name: "improve_prompt", Source: BuiltinSource},
+		{Name: "prime_mind", Source: BuiltinSource},
+		{Name: "release_notes", Source: ProjectSource},
+		{Name: "review/security", Source: "team"},
+		{Name: "review/security", Source: ProjectSource, ShadowedBy: "team"},
+		{Name: "summarize", Source: PersonalSource},
+		{Name: "summarize", Source: "team", ShadowedBy: PersonalSource},
+	}, entries)
+
+	pats, err := ListPatterns()
+	r.NoError(err)
+	r.Equal([]string{"improve_prompt", "prime_mind", "release_notes", "review/security", "summarize"}, pats)
+}
+
+func (s *PatternRepoTestSuite) TestListPatternEntries_MissingRepo() {
+	r := s.Require()
+
+	viper.Set("pattern.repos", []map[string]any{
+		{"name": "share", "path": filepath.Join(s.T().TempDir(), "unmounted")},
+		{"name": "team", "path": s.team},
+	})
+
+	entries, err := ListPatternEntries()
+	r.NoError(err)
+	for _, e := range entries {
+		r.NotEqual("share", e.Source)
+	}
+	r.Contains(entries, PatternEntry{Name: "summarize", Source: "team", ShadowedBy: PersonalSource})
+
+	results, err := SearchPatterns("security")
+	r.NoError(err)
+	r.NotEmpty(results)
+}
+
+func (s *PatternRepoTestSuite) TestListPatternEntries_InvalidMeta() {
+	r := s.Require()
+
+	s.writePattern(s.team, "typo", "---\ntemperatur: 0.3\n---\nprompt")
+	s.writePattern(s.team, "unclosed", "---\ntemperature: 0.3\nprompt")
+
+	entries, err := ListPatternEntries()
+	r.NoError(err)
+	r.Contains(entries, PatternEntry{Name: "typo", Source: "team"})
+	r.Contains(entries, PatternEntry{Name: "unclosed", Source: "team"})
+
+	results, err := SearchPatterns("typo")
+	r.NoError(err)
+	r.NotEmpty(results)
+	r.Equal("typo", results[0].Name)
+
+	// reading the pattern itself is still strict
+	_, err = NewPatternRepo("team", s.team).ReadPattern("typo")
+	r.Error(err)
+}
+
+func (s *PatternRepoTestSuite) TestGetPrompt() {
+	r := s.Require()
+
+	viper.Set("pattern.name", "team:summarize")
+	prompt, err := GetPrompt()
+	r.NoError(err)
+	r.Equal("team summarize", prompt)
+}
+
+func TestSplitPatternName(t *testing.T) {
+	testCases := []struct {
+		name       string
+		qualified  string
+		wantSource string
+		wantName   string
+	}{
+		{name: "unqualified", qualified: "summarize", wantSource: "", wantName: "summarize"},
+		{name: "qualified", qualified: "team:summarize", wantSource: "team", wantName: "summarize"},
+		{name: "qualified nested", qualified: "team:review/security", wantSource: "team", wantName: "review/security"},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			source, name := SplitPatternName(tt.qualified)
+			r.Equal(tt.wantSource, source)
+			r.Equal(tt.wantName, name)
+		})
+	}
+}
+
+func TestBuiltinRepo(t *testing.T) {
+	r := require.New(t)
+
+	repo := BuiltinRepo()
+	r.True(repo.Builtin())
+
+	// other repositories without a directory aren't built-in
+	bundle := PatternRepo{Name: "bundle", FS: fstest.MapFS{}}
+	r.False(bundle.Builtin())
+	r.Equal("bundle:summarize/system.md", bundle.PromptPath("summarize"))
+
+	pats, err := repo.List()
+	r.NoError(err)
+	r.Equal([]string{"improve_prompt", "prime_mind"}, pats)
+
+	p, err := repo.ReadPattern("prime_mind")
+	r.NoError(err)
+	r.NotEmpty(p.Prompt)
+	r.Equal("builtin:prime_mind/system.md", repo.PromptPath("prime_mind"))
+}
+
+func (s *PatternRepoTestSuite) TestFindPattern_Builtin() {
+	r := s.Require()
+
+	// built-in patterns are a fallback, and can be shadowed
+	repo, name, err := FindPattern("prime_mind", "")
+	r.NoError(err)
+	r.Equal(BuiltinSource, repo.Name)
+	r.Equal("prime_mind", name)
+
+	s.writePattern(s.personal, "prime_mind", "personal prime mind")
+	repo, _, err = FindPattern("prime_mind", "")
+	r.NoError(err)
+	r.Equal(PersonalSource, repo.Name)
+
+	repo, _, err = FindPattern("builtin:prime_mind", "")
+	r.NoError(err)
+	r.Equal(BuiltinSource, repo.Name)
+
+	// without any repository
+	viper.Reset()
+	ProjectRepo = filepath.Join(s.T().TempDir(), "missing")
+	repo, _, err = FindPattern("improve_prompt", "")
+	r.NoError(err)
+	r.Equal(BuiltinSource, repo.Name)
+}
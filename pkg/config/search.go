@@ -0,0 +1,184 @@
+package config
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+)
+
+// SearchResult is a pattern matching a search query.
+type SearchResult struct {
+	Name        string
+	Source      string
+	Description string
+	// Score ranks results, higher is better.
+	Score int
+}
+
+// weights of where a query matches, a match on the name matters most
+const (
+	nameWeight    = 3
+	tagScore      = 15
+	descScore     = 10
+	contentScore  = 2
+	maxOccurrence = 5
+)
+
+// SearchPatterns ranks the available patterns against a query,
+// by fuzzy matching their name, and by matching the words of the query
+// against their metadata and prompt. Patterns not matching at all are left out.
+//
+// Shadowed patterns are not searched, see ListPatterns.
+// A repository that can't be listed is skipped with a warning, as in ListPatternEntries.
+func SearchPatterns(query string) ([]SearchResult, error) {
+	repos, err := PatternRepos()
+	if err != nil {
+		return nil, err
+	}
+
+	var results []SearchResult
+	seen := make(map[string]bool)
+
+	for _, r := range repos {
+		pats, err := r.List()
+		if err != nil {
+			log.Warn("skipping pattern repository", "source", r.Name, "error", err)
+			continue
+		}
+
+		for _, name := range pats {
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+
+			p, err := r.ReadPattern(name)
+			if err != nil {
+				// a pattern with invalid metadata is still searched by name
+				log.Warn("searching pattern by name only", "pattern", name, "source", r.Name, "error", err)
+				p = PatternContent{Name: name}
+			}
+
+			if score := ScorePattern(query, p); score > 0 {
+				results = append(results, SearchResult{
+					Name:        name,
+					Source:      r.Name,
+					Description: p.Meta.Description,
+					Score:       score,
+				})
+			}
+		}
+	}
+
+	SortSearchResults(results)
+	return results, nil
+}
+
+// SortSearchResults sorts results by decreasing score, then by name.
+func SortSearchResults(results []SearchResult) {
+	slices.SortStableFunc(results, func(a, b SearchResult) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+}
+
+// ScorePattern scores how well a pattern matches a query, 0 if it doesn't match.
+// Only the fields of the pattern that are set are scored,
+// e.g. a pattern with only a name is scored by its name.
+func ScorePattern(query string, p PatternContent) int {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return 0
+	}
+
+	score := FuzzyScore(query, p.Name) * nameWeight
+
+	content := strings.ToLower(p.Prompt)
+	desc := strings.ToLower(p.Meta.Description)
+
+	for _, term := range strings.Fields(strings.ToLower(query)) {
+		if p.Meta.HasTag(term) {
+			score += tagScore
+		}
+		if strings.Contains(desc, term) {
+			score += descScore
+		}
+		score += min(strings.Count(content, term), maxOccurrence) * contentScore
+	}
+
+	return score
+}
+
+// FuzzyScore scores how well a query fuzzy matches a target, ignoring case.
+// The query matches if its characters appear in the target in order, e.g. "exwis" matches "extract_wisdom".
+// Consecutive characters and characters at the start of words score higher.
+// It returns 0 if the query doesn't match.
+func FuzzyScore(query string, target string) int {
+	query = strings.ToLower(query)
+	target = strings.ToLower(target)
+	if query == "" {
+		return 0
+	}
+
+	// the characters of the query are ignored if they are separators,
+	// so that "extract wisdom" matches "extract_wisdom"
+	qr := []rune(strings.Map(func(r rune) rune {
+		if isSeparator(r) {
+			return -1
+		}
+		return r
+	}, query))
+	if len(qr) == 0 {
+		return 0
+	}
+
+	score := 0
+	qi := 0
+	prevMatched := false
+	prev := rune(-1)
+
+	for _, r := range target {
+		if qi < len(qr) && r == qr[qi] {
+			score++
+			if prevMatched {
+				score += 4
+			}
+			if prev == -1 || isSeparator(prev) {
+				score += 6
+			}
+			qi++
+			prevMatched = true
+		} else {
+			prevMatched = false
+		}
+		prev = r
+	}
+
+	if qi < len(qr) {
+		return 0
+	}
+
+	switch {
+	case target == query:
+		score += 50
+	case strings.HasPrefix(target, query):
+		score += 20
+	case strings.Contains(target, query):
+		score += 10
+	}
+
+	// prefer shorter targets, whose match covers more of the name
+	score -= (utf8.RuneCountInString(target) - len(qr)) / 4
+
+	return max(score, 1)
+}
+
+func isSeparator(r rune) bool {
+	return r == '_' || r == '-' || r == '/' || r == '.' || unicode.IsSpace(r)
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuzzyScore(t *testing.T) {
+	testCases := []struct {
+		name      string
+		query     string
+		target    string
+		wantMatch bool
+	}{
+		{name: "exact", query: "summarize", target: "summarize", wantMatch: true},
+		{name: "case insensitive", query: "SUMMARIZE", target: "summarize", wantMatch: true},
+		{name: "subsequence", query: "exwis", target: "extract_wisdom", wantMatch: true},
+		{name: "separators ignored", query: "extract wisdom", target: "extract_wisdom", wantMatch: true},
+		{name: "out of order", query: "wisex", target: "extract_wisdom", wantMatch: false},
+		{name: "missing character", query: "sumz", target: "summary", wantMatch: false},
+		{name: "empty query", query: "", target: "summarize", wantMatch: false},
+		{name: "only separators", query: "_ -", target: "summarize", wantMatch: false},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			score := FuzzyScore(tt.query, tt.target)
+			if tt.wantMatch {
+				r.Positive(score)
+			} else {
+				r.Zero(score)
+			}
+		})
+	}
+}
+
+func TestFuzzyScore_Ranking(t *testing.T) {
+	r := require.New(t)
+
+	exact := FuzzyScore("summarize", "summarize")
+	prefix := FuzzyScore("summ", "summarize")
+	substring := FuzzyScore("mari", "summarize")
+	wordStarts := FuzzyScore("ec", "explain_code")
+	scattered := FuzzyScore("ec", "exercise")
+
+	r.Greater(exact, prefix)
+	r.Greater(prefix, substring)
+	r.Greater(wordStarts, scattered)
+
+	// shorter names are preferred
+	r.Greater(FuzzyScore("summ", "summarize"), FuzzyScore("summ", "summarize_git_changes"))
+}
+
+func TestScorePattern(t *testing.T) {
+	pattern := PatternContent{
+		Name:   "extract_wisdom",
+		Prompt: "Extract the ideas and quotes from the content. List the ideas first.",
+		Meta: PatternMeta{
+			Description: "Extract insights from talks",
+			Tags:        []string{"learning"},
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "no match", query: "translate", want: 0},
+		{name: "empty query", query: "  ", want: 0},
+		{name: "tag", query: "learning", want: tagScore},
+		{name: "description", query: "talks", want: descScore},
+		{name: "content", query: "quotes", want: contentScore},
+		{name: "content occurrences", query: "ideas", want: 2 * contentScore},
+		{name: "terms add up", query: "learning talks", want: tagScore + descScore},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			r.Equal(tt.want, ScorePattern(tt.query, pattern))
+		})
+	}
+
+	// a match on the name outweighs a match on the content
+	r.Greater(ScorePattern("exwis", pattern), ScorePattern("ideas", pattern))
+}
+
+func (s *PatternRepoTestSuite) TestSearchPatterns() {
+	testCases := []struct {
+		name  string
+		query string
+		want  []SearchResult
+	}{
+		{
+			name:  "shadowed patterns are skipped",
+			query: "sec",
+			want:  []SearchResult{{Name: "review/security", Source: "team"}},
+		},
+		{
+			name:  "content",
+			query: "personal",
+			want:  []SearchResult{{Name: "summarize", Source: PersonalSource}},
+		},
+		{
+			name:  "no match",
+			query: "translate",
+			want:  []SearchResult{},
+		},
+	}
+
+	r := s.Require()
+
+	for _, tt := range testCases {
+		s.Run(tt.name, func() {
+			results, err := SearchPatterns(tt.query)
+			r.NoError(err)
+
+			// the prompts of the built-in patterns may match any query
+			results = slices.DeleteFunc(results, func(res SearchResult) bool {
+				return res.Source == BuiltinSource
+			})
+
+			// scores are covered by the tests of ScorePattern
+			for i := range results {
+				r.Positive(results[i].Score)
+				results[i].Score = 0
+			}
+			r.Equal(tt.want, results)
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// StrategyExts are the extensions of strategy files, in lookup order.
+// JSON files follow the format of fabric's strategies, {"description": "...", "prompt": "..."},
+// and Markdown files hold the prompt, with an optional front matter describing it.
+var StrategyExts = []string{".json", ".md"}
+
+// Strategy is a prompting technique, such as chain-of-thought or self-critique,
+// layered on top of a pattern's prompt.
+type Strategy struct {
+	// Name is the name of the strategy, e.g. "cot".
+	Name string `json:"name"`
+	// Description is a short summary of the technique.
+	Description string `json:"description"`
+	// Prompt is the text composed with the pattern's prompt.
+	Prompt string `json:"prompt"`
+}
+
+// StrategyDir returns the directory holding strategies, set by `strategy.dir`,
+// or the strategies directory next to the config file by default.
+func StrategyDir() (string, error) {
+	if dir := viper.GetString("strategy.dir"); dir != "" {
+		return dir, nil
+	}
+
+	configDir, _, err := AppConfig()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "strategies"), nil
+}
+
+// IsStrategyFile reports whether a file name has the extension of a strategy file.
+func IsStrategyFile(name string) bool {
+	return slices.Contains(StrategyExts, filepath.Ext(name))
+}
+
+// ParseStrategy parses the content of a strategy file, whose extension selects the format.
+func ParseStrategy(file string, data []byte) (Strategy, error) {
+	ext := filepath.Ext(file)
+	s := Strategy{Name: strings.TrimSuffix(filepath.Base(file), ext)}
+
+	switch ext {
+	case ".json":
+		if err := json.Unmarshal(data, &s); err != nil {
+			return Strategy{}, fmt.Errorf("parsing strategy %s: %w", s.Name, err)
+		}
+		// the name comes from the file, not its content
+		s.Name = strings.TrimSuffix(filepath.Base(file), ext)
+	case ".md":
+		meta, prompt, err := splitFrontMatter(string(data))
+		if err != nil {
+			return Strategy{}, fmt.Errorf("parsing strategy %s: %w", s.Name, err)
+		}
+		s.Description = meta.Description
+		s.Prompt = prompt
+	default:
+		return Strategy{}, fmt.Errorf("unsupported strategy file %s, expected one of: %s", file, strings.Join(StrategyExts, ", "))
+	}
+
+	s.Prompt = strings.TrimSpace(s.Prompt)
+	if s.Prompt == "" {
+		return Strategy{}, fmt.Errorf("strategy %s has no prompt", s.Name)
+	}
+	return s, nil
+}
+
+// ListStrategies returns the strategies in the strategy directory, sorted by name.
+// A strategy defined in several formats is listed once, see GetStrategy.
+func ListStrategies() ([]Strategy, error) {
+	dir, err := StrategyDir()
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return []Strategy{}, nil
+		}
+		return nil, err
+	}
+
+	seen := make(map[string]bool)
+	strategies := make([]Strategy, 0, len(entries))
+	for _, e := range entries {
+		name := strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))
+		if e.IsDir() || !IsStrategyFile(e.Name()) || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		s, err := GetStrategy(name)
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, s)
+	}
+
+	slices.SortFunc(strategies, func(a, b Strategy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return strategies, nil
+}
+
+// GetStrategy reads a strategy from the strategy directory,
+// trying each of StrategyExts in order.
+func GetStrategy(name string) (Strategy, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return Strategy{}, fmt.Errorf("invalid strategy name %q", name)
+	}
+
+	dir, err := StrategyDir()
+	if err != nil {
+		return Strategy{}, err
+	}
+
+	for _, ext := range StrategyExts {
+		file := filepath.Join(dir, name+ext)
+		data, err := os.ReadFile(file)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return Strategy{}, err
+		}
+		return ParseStrategy(file, data)
+	}
+
+	return Strategy{}, &Unsupported{Type: "strategy", Key: name}
+}
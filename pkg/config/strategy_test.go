@@ -0,0 +1,115 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStrategy(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		data    string
+		want    Strategy
+		wantErr bool
+	}{
+		{
+			name: "json",
+			file: "strategies/cot.json",
+			data: `{"description": "Chain-of-Thought", "prompt": "Think step by step.\n"}`,
+			want: Strategy{Name: "cot", Description: "Chain-of-Thought", Prompt: "Think step by step."},
+		},
+		{
+			name: "json name is ignored",
+			file: "cot.json",
+			data: `{"name": "other", "prompt": "Think step by step."}`,
+			want: Strategy{Name: "cot", Prompt: "Think step by step."},
+		},
+		{
+			name: "markdown with front matter",
+			file: "self-critique.md",
+			data: "---\ndescription: Self-critique\n---\n\nCritique your answer.\n",
+			want: Strategy{Name: "self-critique", Description: "Self-critique", Prompt: "Critique your answer."},
+		},
+		{
+			name: "markdown without front matter",
+			file: "tot.md",
+			data: "Explore several branches.",
+			want: Strategy{Name: "tot", Prompt: "Explore several branches."},
+		},
+		{
+			name:    "invalid json",
+			file:    "cot.json",
+			data:    `{"prompt": `,
+			wantErr: true,
+		},
+		{
+			name:    "empty prompt",
+			file:    "cot.json",
+			data:    `{"description": "Chain-of-Thought", "prompt": " "}`,
+			wantErr: true,
+		},
+		{
+			name:    "unsupported extension",
+			file:    "cot.txt",
+			data:    "Think step by step.",
+			wantErr: true,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			got, err := ParseStrategy(tt.file, []byte(tt.data))
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestListStrategies(t *testing.T) {
+	r := require.New(t)
+
+	dir := t.TempDir()
+	viper.Reset()
+	viper.Set("strategy.dir", dir)
+	t.Cleanup(viper.Reset)
+
+	writeFile(t, filepath.Join(dir, "tot.md"), "Explore several branches.")
+	writeFile(t, filepath.Join(dir, "cot.json"), `{"description": "Chain-of-Thought", "prompt": "Think step by step."}`)
+	// JSON comes first in lookup order
+	writeFile(t, filepath.Join(dir, "cot.md"), "Shadowed.")
+	writeFile(t, filepath.Join(dir, "README.txt"), "not a strategy")
+
+	got, err := ListStrategies()
+	r.NoError(err)
+	r.Equal([]Strategy{
+		{Name: "cot", Description: "Chain-of-Thought", Prompt: "Think step by step."},
+		{Name: "tot", Prompt: "Explore several branches."},
+	}, got)
+
+	s, err := GetStrategy("cot")
+	r.NoError(err)
+	r.Equal("Think step by step.", s.Prompt)
+
+	_, err = GetStrategy("unknown")
+	var unsupported *Unsupported
+	r.ErrorAs(err, &unsupported)
+
+	_, err = GetStrategy("../cot")
+	r.Error(err)
+
+	// a missing directory has no strategies
+	viper.Set("strategy.dir", filepath.Join(dir, "missing"))
+	got, err = ListStrategies()
+	r.NoError(err)
+	r.Empty(got)
+}
@@ -21,6 +21,9 @@ const (
 	OLLAMA_HOST        = "OLLAMA_HOST"
 	JINA_API_KEY       = "JINA_API_KEY"
 	FIRECRAWL_API_KEY  = "FIRECRAWL_API_KEY"
+	GITHUB_TOKEN       = "GITHUB_TOKEN"
+	VISUAL             = "VISUAL"
+	EDITOR             = "EDITOR"
 
 	// seaq-specific
 
@@ -168,3 +171,20 @@ func JinaAPIKey() (string, error) {
 func FirecrawlAPIKey() (string, error) {
 	return Get(FIRECRAWL_API_KEY)
 }
+
+// GitHubToken returns the value of the GITHUB_TOKEN environment variable
+// or an error if not set.
+func GitHubToken() (string, error) {
+	return Get(GITHUB_TOKEN)
+}
+
+// Editor returns the command of the user's editor,
+// from the VISUAL or EDITOR environment variables, or vi if neither is set.
+func Editor() string {
+	for _, key := range []string{VISUAL, EDITOR} {
+		if val, err := Get(key); err == nil && strings.TrimSpace(val) != "" {
+			return val
+		}
+	}
+	return "vi"
+}
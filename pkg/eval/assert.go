@@ -0,0 +1,139 @@
+package eval
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+)
+
+// AssertionResult is the outcome of an assertion on an output.
+type AssertionResult struct {
+	// Assertion describes the assertion, e.g. `contains "# SUMMARY"`.
+	Assertion string
+	Passed    bool
+	// Skipped is set when the assertion can't be checked, e.g. a judge with the mock model.
+	Skipped bool
+	// Message explains why the assertion failed or was skipped.
+	Message string
+}
+
+// judge grades an output against a rubric with a model.
+type judge struct {
+	completer Completer
+	model     string
+}
+
+const judgePrompt = `# IDENTITY and PURPOSE
+
+You are a strict grader. You check whether the output of an AI assistant satisfies a rubric.
+
+# OUTPUT INSTRUCTIONS
+
+- On the first line, write only PASS if the output fully satisfies the rubric, or FAIL otherwise.
+- On the second line, explain your decision in one sentence.
+`
+
+// grade asks the judge model whether an output satisfies a rubric.
+func (j judge) grade(ctx context.Context, rubric string, output string) (bool, string, error) {
+	content := fmt.Sprintf("RUBRIC:\n%s\n\nOUTPUT:\n%s", rubric, output)
+
+	msgs, err := llm.PrepareMessages(j.model, "", judgePrompt, content, "")
+	if err != nil {
+		return false, "", err
+	}
+
+	verdict, err := j.completer.Complete(ctx, Request{Model: j.model, Messages: msgs})
+	if err != nil {
+		return false, "", err
+	}
+
+	first, reason, _ := strings.Cut(strings.TrimSpace(verdict), "\n")
+	first = strings.ToUpper(strings.Trim(first, " *`.:"))
+	reason = strings.TrimSpace(reason)
+
+	switch first {
+	case "PASS":
+		return true, reason, nil
+	case "FAIL":
+		return false, reason, nil
+	default:
+		return false, "", fmt.Errorf("unexpected verdict from judge: %q", verdict)
+	}
+}
+
+// Describe returns a short description of the assertion.
+func (a Assertion) Describe() string {
+	switch {
+	case a.Contains != "":
+		return fmt.Sprintf("contains %q", a.Contains)
+	case a.NotContains != "":
+		return fmt.Sprintf("not_contains %q", a.NotContains)
+	case a.Regex != "":
+		return fmt.Sprintf("regex /%s/", a.Regex)
+	case a.JSONSchema != nil:
+		return "json_schema"
+	case a.MaxLength != 0:
+		return fmt.Sprintf("max_length %d", a.MaxLength)
+	case a.Judge != "":
+		return fmt.Sprintf("judge %q", a.Judge)
+	default:
+		return "empty assertion"
+	}
+}
+
+// check checks the output against the assertion.
+// Assertions are validated when loading a suite, see LoadSuite.
+func (a Assertion) check(ctx context.Context, output string, j judge) AssertionResult {
+	res := AssertionResult{Assertion: a.Describe()}
+
+	switch {
+	case a.Contains != "":
+		res.Passed = strings.Contains(output, a.Contains)
+		if !res.Passed {
+			res.Message = "output does not contain the string"
+		}
+	case a.NotContains != "":
+		res.Passed = !strings.Contains(output, a.NotContains)
+		if !res.Passed {
+			res.Message = "output contains the string"
+		}
+	case a.Regex != "":
+		res.Passed = regexp.MustCompile(a.Regex).MatchString(output)
+		if !res.Passed {
+			res.Message = "output does not match"
+		}
+	case a.JSONSchema != nil:
+		v, err := parseJSONOutput(output)
+		if err == nil {
+			err = validateSchema(a.JSONSchema, v, "$")
+		}
+		res.Passed = err == nil
+		if err != nil {
+			res.Message = err.Error()
+		}
+	case a.MaxLength != 0:
+		n := utf8.RuneCountInString(output)
+		res.Passed = n <= a.MaxLength
+		if !res.Passed {
+			res.Message = fmt.Sprintf("output has %d characters", n)
+		}
+	case a.Judge != "":
+		if j.model == MockModel {
+			res.Skipped = true
+			res.Message = "the mock model can't judge, set a judge model"
+			return res
+		}
+		passed, reason, err := j.grade(ctx, a.Judge, output)
+		res.Passed = passed
+		res.Message = reason
+		if err != nil {
+			res.Message = err.Error()
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,151 @@
+package eval
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// MockModel is the name of the model returning the mock output of cases,
+// or echoing their input, to run tests offline.
+const MockModel = "mock"
+
+// RecordingFile is the name of the file holding recorded outputs, next to the test cases.
+const RecordingFile = "tests.recorded.json"
+
+// ErrNotRecorded is returned when replaying a completion that was not recorded.
+var ErrNotRecorded = errors.New("no recorded output")
+
+// Request is a completion requested from a model.
+type Request struct {
+	Model       string
+	Messages    []llms.MessageContent
+	Temperature float64
+}
+
+// Completer generates the output of a model.
+type Completer interface {
+	Complete(ctx context.Context, req Request) (string, error)
+}
+
+// CompleterFunc adapts a function to a Completer.
+type CompleterFunc func(ctx context.Context, req Request) (string, error)
+
+func (f CompleterFunc) Complete(ctx context.Context, req Request) (string, error) {
+	return f(ctx, req)
+}
+
+// LLMCompleter runs completions with the configured models.
+var LLMCompleter = CompleterFunc(func(ctx context.Context, req Request) (string, error) {
+	// nolint: contextcheck
+	model, err := llm.New(req.Model)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	err = llm.CreateCompletion(ctx, model, &sb, req.Messages, llms.WithTemperature(req.Temperature))
+	if err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+})
+
+// Recording holds the outputs of completions, keyed by a hash of their request,
+// so that a change to the prompt, input or model is never replayed with a stale output.
+type Recording struct {
+	mu      sync.Mutex
+	Outputs map[string]string `json:"outputs"`
+}
+
+// LoadRecording reads a recording, or returns an empty one if the file doesn't exist.
+func LoadRecording(name string) (*Recording, error) {
+	rec := &Recording{Outputs: make(map[string]string)}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return rec, nil
+		}
+		return nil, err
+	}
+
+	if err := json.Unmarshal(data, rec); err != nil {
+		return nil, fmt.Errorf("parsing recording: %w", err)
+	}
+	if rec.Outputs == nil {
+		rec.Outputs = make(map[string]string)
+	}
+	return rec, nil
+}
+
+// Save writes the recording to a file.
+func (r *Recording) Save(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, append(data, '\n'), 0o644)
+}
+
+// Record returns a Completer recording the outputs of another one.
+func (r *Recording) Record(c Completer) Completer {
+	return CompleterFunc(func(ctx context.Context, req Request) (string, error) {
+		out, err := c.Complete(ctx, req)
+		if err != nil {
+			return "", err
+		}
+
+		key, err := requestKey(req)
+		if err != nil {
+			return "", err
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.Outputs[key] = out
+
+		return out, nil
+	})
+}
+
+// Replay returns a Completer returning the recorded outputs, without calling any model.
+func (r *Recording) Replay() Completer {
+	return CompleterFunc(func(_ context.Context, req Request) (string, error) {
+		key, err := requestKey(req)
+		if err != nil {
+			return "", err
+		}
+
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		out, ok := r.Outputs[key]
+		if !ok {
+			return "", fmt.Errorf("%w for model %s, it was never recorded or its request changed since", ErrNotRecorded, req.Model)
+		}
+		return out, nil
+	})
+}
+
+func requestKey(req Request) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,137 @@
+package eval
+
+import (
+	"cmp"
+	"context"
+	"errors"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/nt54hamnghi/seaq/pkg/util/pool"
+)
+
+// Runner runs the test cases of a pattern.
+type Runner struct {
+	Completer Completer
+	// Model overrides the model of all cases, e.g. MockModel.
+	Model string
+	// DefaultModel runs the cases when neither the case nor the suite sets a model.
+	DefaultModel string
+	// Judge overrides the judge model of the suite.
+	Judge string
+	// Temperature is used when neither the case nor the pattern sets a temperature.
+	Temperature float64
+	// Parallel is the maximum number of cases run at once, unlimited if not positive.
+	Parallel int
+}
+
+// Result is the outcome of a test case.
+type Result struct {
+	Case       string
+	Model      string
+	Output     string
+	Assertions []AssertionResult
+	// Err is set when the output couldn't be generated.
+	Err error
+}
+
+// Passed reports whether the output was generated and satisfies all assertions.
+// Skipped assertions don't fail a case.
+func (r Result) Passed() bool {
+	if r.Err != nil {
+		return false
+	}
+	for _, a := range r.Assertions {
+		if !a.Passed && !a.Skipped {
+			return false
+		}
+	}
+	return true
+}
+
+// Run runs the cases of a suite concurrently and returns their results in order.
+func (rn Runner) Run(ctx context.Context, p config.PatternContent, s Suite) []Result {
+	var sem chan struct{}
+	if rn.Parallel > 0 {
+		sem = make(chan struct{}, rn.Parallel)
+	}
+
+	results := pool.OrderedGoFunc(s.Cases, func(c Case) (Result, error) {
+		if sem != nil {
+			sem <- struct{}{}
+			defer func() { <-sem }()
+		}
+		return rn.runCase(ctx, p, s, c), nil
+	})
+
+	out := make([]Result, len(results))
+	for i, r := range results {
+		out[i] = r.Output
+	}
+	return out
+}
+
+func (rn Runner) runCase(ctx context.Context, p config.PatternContent, s Suite, c Case) Result {
+	model := cmp.Or(rn.Model, c.Model, s.Model, rn.DefaultModel)
+	res := Result{Case: c.Name, Model: model}
+
+	if model == "" {
+		res.Err = errors.New("no model to run the case, set one in the suite or with --model")
+		return res
+	}
+
+	output, err := rn.generate(ctx, p, c, model)
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	res.Output = output
+
+	j := judge{
+		completer: rn.Completer,
+		model:     cmp.Or(rn.Judge, s.Judge, model),
+	}
+	// running with the mock model stays offline, unless a judge model is explicitly given
+	if rn.Model == MockModel && rn.Judge == "" {
+		j.model = MockModel
+	}
+	for _, a := range c.Assert {
+		res.Assertions = append(res.Assertions, a.check(ctx, output, j))
+	}
+
+	return res
+}
+
+// generate runs the pattern on the input of a case.
+func (rn Runner) generate(ctx context.Context, p config.PatternContent, c Case, model string) (string, error) {
+	prompt, err := p.Render(c.Vars)
+	if err != nil {
+		return "", err
+	}
+
+	if model == MockModel {
+		if c.MockOutput != "" {
+			return c.MockOutput, nil
+		}
+		return c.Input, nil
+	}
+
+	temperature := rn.Temperature
+	if p.Meta.Temperature != nil {
+		temperature = *p.Meta.Temperature
+	}
+	if c.Temperature != nil {
+		temperature = *c.Temperature
+	}
+
+	msgs, err := llm.PrepareMessages(model, "", prompt, c.Input, "")
+	if err != nil {
+		return "", err
+	}
+
+	return rn.Completer.Complete(ctx, Request{
+		Model:       model,
+		Messages:    msgs,
+		Temperature: temperature,
+	})
+}
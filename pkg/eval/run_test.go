@@ -0,0 +1,163 @@
+package eval
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// lastText returns the text of the last message of a request.
+func lastText(req Request) string {
+	msg := req.Messages[len(req.Messages)-1]
+	return msg.Parts[len(msg.Parts)-1].(llms.TextContent).Text
+}
+
+func TestRunner_Run(t *testing.T) {
+	pattern := config.PatternContent{
+		Name:   "translate",
+		Prompt: "Translate to {{ .language }}.",
+		Meta:   config.PatternMeta{Variables: []string{"language"}},
+	}
+
+	suite := Suite{
+		Cases: []Case{
+			{
+				Name:       "mock output",
+				Model:      MockModel,
+				MockOutput: "Bonjour",
+				Vars:       map[string]string{"language": "French"},
+				Assert:     []Assertion{{Contains: "Bonjour"}, {MaxLength: 10}},
+			},
+			{
+				Name:   "mock echo",
+				Model:  MockModel,
+				Input:  "Hello",
+				Vars:   map[string]string{"language": "French"},
+				Assert: []Assertion{{Regex: "^Bon"}, {Judge: "The output is in French"}},
+			},
+			{
+				Name:   "missing variable",
+				Model:  MockModel,
+				Assert: []Assertion{{Contains: "Bonjour"}},
+			},
+			{
+				Name:   "model",
+				Input:  "Hello",
+				Vars:   map[string]string{"language": "French"},
+				Assert: []Assertion{{Contains: "Bonjour"}, {Judge: "The output is in French"}},
+			},
+		},
+	}
+
+	var calls atomic.Int32
+	runner := Runner{
+		Completer: CompleterFunc(func(_ context.Context, req Request) (string, error) {
+			calls.Add(1)
+			if strings.Contains(lastText(req), "RUBRIC:") {
+				return "PASS\nIt is French.", nil
+			}
+			return "Bonjour", nil
+		}),
+		DefaultModel: "openai/gpt-4o",
+		Parallel:     2,
+	}
+
+	r := require.New(t)
+
+	results := runner.Run(context.Background(), pattern, suite)
+	r.Len(results, 4)
+
+	r.Equal("mock output", results[0].Case)
+	r.True(results[0].Passed())
+
+	// the mock model can't judge, and the input doesn't match
+	r.False(results[1].Passed())
+	r.False(results[1].Assertions[0].Passed)
+	r.True(results[1].Assertions[1].Skipped)
+
+	r.Error(results[2].Err)
+	r.False(results[2].Passed())
+
+	r.Equal("openai/gpt-4o", results[3].Model)
+	r.True(results[3].Passed())
+	r.Equal("It is French.", results[3].Assertions[1].Message)
+
+	// a completion for the case and one for its judge
+	r.Equal(int32(2), calls.Load())
+}
+
+func TestRunner_Run_MockWithSuiteJudge(t *testing.T) {
+	pattern := config.PatternContent{Name: "summarize", Prompt: "Summarize."}
+	suite := Suite{
+		Model: "openai/gpt-4o",
+		Judge: "anthropic/claude-sonnet-4",
+		Cases: []Case{
+			{
+				Name:       "judged",
+				MockOutput: "A summary.",
+				Assert:     []Assertion{{Contains: "summary"}, {Judge: "The output is a summary"}},
+			},
+		},
+	}
+
+	var calls atomic.Int32
+	completer := CompleterFunc(func(context.Context, Request) (string, error) {
+		calls.Add(1)
+		return "PASS\nIt is a summary.", nil
+	})
+
+	r := require.New(t)
+
+	// --mock skips judge assertions rather than calling the suite's judge
+	results := Runner{Completer: completer, Model: MockModel}.Run(context.Background(), pattern, suite)
+	r.Len(results, 1)
+	r.True(results[0].Passed())
+	r.True(results[0].Assertions[1].Skipped)
+	r.Zero(calls.Load())
+
+	// an explicit judge model is still used
+	results = Runner{Completer: completer, Model: MockModel, Judge: "openai/gpt-4o"}.Run(context.Background(), pattern, suite)
+	r.True(results[0].Passed())
+	r.False(results[0].Assertions[1].Skipped)
+	r.Equal(int32(1), calls.Load())
+}
+
+func TestRecording(t *testing.T) {
+	r := require.New(t)
+
+	name := filepath.Join(t.TempDir(), RecordingFile)
+	req := Request{
+		Model:    "openai/gpt-4o",
+		Messages: []llms.MessageContent{llms.TextParts(llms.ChatMessageTypeHuman, "Hello")},
+	}
+
+	rec, err := LoadRecording(name)
+	r.NoError(err)
+
+	model := CompleterFunc(func(context.Context, Request) (string, error) {
+		return "Bonjour", nil
+	})
+	out, err := rec.Record(model).Complete(context.Background(), req)
+	r.NoError(err)
+	r.Equal("Bonjour", out)
+	r.NoError(rec.Save(name))
+
+	rec, err = LoadRecording(name)
+	r.NoError(err)
+	replay := rec.Replay()
+
+	out, err = replay.Complete(context.Background(), req)
+	r.NoError(err)
+	r.Equal("Bonjour", out)
+
+	// a changed request isn't replayed
+	req.Temperature = 0.2
+	_, err = replay.Complete(context.Background(), req)
+	r.ErrorIs(err, ErrNotRecorded)
+}
@@ -0,0 +1,211 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// parseJSONOutput decodes the output of a model as JSON.
+// Models often wrap JSON in a Markdown code fence, which is stripped.
+func parseJSONOutput(output string) (any, error) {
+	s := strings.TrimSpace(output)
+	if strings.HasPrefix(s, "```") && strings.HasSuffix(s, "```") {
+		s = strings.TrimSuffix(s, "```")
+		// drop the opening fence and its optional language
+		if _, rest, ok := strings.Cut(s, "\n"); ok {
+			s = rest
+		}
+	}
+
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		return nil, fmt.Errorf("output is not valid JSON: %w", err)
+	}
+	return v, nil
+}
+
+// validateSchema validates a value decoded from JSON against a JSON schema.
+//
+// Only a subset of JSON schema is supported, which covers the shape of model outputs:
+// type, enum, const, properties, required, additionalProperties (as a boolean), items,
+// minItems, maxItems, minLength, maxLength, pattern, minimum and maximum.
+func validateSchema(schema map[string]any, v any, at string) error {
+	if t, ok := schema["type"]; ok {
+		if err := checkType(t, v, at); err != nil {
+			return err
+		}
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(e any) bool { return jsonEqual(e, v) }) {
+			return fmt.Errorf("%s: %v is not one of %v", at, v, enum)
+		}
+	}
+	if c, ok := schema["const"]; ok && !jsonEqual(c, v) {
+		return fmt.Errorf("%s: %v is not %v", at, v, c)
+	}
+
+	switch v := v.(type) {
+	case map[string]any:
+		return validateObject(schema, v, at)
+	case []any:
+		return validateArray(schema, v, at)
+	case string:
+		return validateString(schema, v, at)
+	case float64:
+		return validateNumber(schema, v, at)
+	}
+
+	return nil
+}
+
+func validateObject(schema map[string]any, obj map[string]any, at string) error {
+	if required, ok := schema["required"].([]any); ok {
+		for _, r := range required {
+			if name, ok := r.(string); ok {
+				if _, ok := obj[name]; !ok {
+					return fmt.Errorf("%s: missing required property %q", at, name)
+				}
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	for name, val := range obj {
+		sub, ok := props[name].(map[string]any)
+		if !ok {
+			if additional, ok := schema["additionalProperties"].(bool); ok && !additional {
+				return fmt.Errorf("%s: unexpected property %q", at, name)
+			}
+			continue
+		}
+		if err := validateSchema(sub, val, at+"."+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateArray(schema map[string]any, arr []any, at string) error {
+	if n, ok := number(schema["minItems"]); ok && float64(len(arr)) < n {
+		return fmt.Errorf("%s: %d items, expected at least %v", at, len(arr), n)
+	}
+	if n, ok := number(schema["maxItems"]); ok && float64(len(arr)) > n {
+		return fmt.Errorf("%s: %d items, expected at most %v", at, len(arr), n)
+	}
+
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range arr {
+			if err := validateSchema(items, item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func validateString(schema map[string]any, s string, at string) error {
+	length := float64(utf8.RuneCountInString(s))
+	if n, ok := number(schema["minLength"]); ok && length < n {
+		return fmt.Errorf("%s: length %v, expected at least %v", at, length, n)
+	}
+	if n, ok := number(schema["maxLength"]); ok && length > n {
+		return fmt.Errorf("%s: length %v, expected at most %v", at, length, n)
+	}
+
+	if p, ok := schema["pattern"].(string); ok {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return fmt.Errorf("%s: invalid pattern: %w", at, err)
+		}
+		if !re.MatchString(s) {
+			return fmt.Errorf("%s: %q does not match %q", at, s, p)
+		}
+	}
+
+	return nil
+}
+
+func validateNumber(schema map[string]any, f float64, at string) error {
+	if n, ok := number(schema["minimum"]); ok && f < n {
+		return fmt.Errorf("%s: %v is less than %v", at, f, n)
+	}
+	if n, ok := number(schema["maximum"]); ok && f > n {
+		return fmt.Errorf("%s: %v is greater than %v", at, f, n)
+	}
+	return nil
+}
+
+// checkType checks the type of a value, t is a type name or a list of type names.
+func checkType(t any, v any, at string) error {
+	var types []string
+	switch t := t.(type) {
+	case string:
+		types = []string{t}
+	case []any:
+		for _, e := range t {
+			if s, ok := e.(string); ok {
+				types = append(types, s)
+			}
+		}
+	}
+
+	got := jsonType(v)
+	for _, want := range types {
+		if want == got || (want == "number" && got == "integer") {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s: got %s, expected %s", at, got, strings.Join(types, " or "))
+}
+
+func jsonType(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case string:
+		return "string"
+	case float64:
+		if v == math.Trunc(v) {
+			return "integer"
+		}
+		return "number"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
+
+// number converts a number of a schema, decoded from YAML, to a float.
+func number(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case float64:
+		return n, true
+	default:
+		return 0, false
+	}
+}
+
+// jsonEqual compares a value of a schema, decoded from YAML, with a value decoded from JSON.
+func jsonEqual(a any, b any) bool {
+	if fa, ok := number(a); ok {
+		fb, ok := number(b)
+		return ok && fa == fb
+	}
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,64 @@
+package eval
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateSchema(t *testing.T) {
+	// schemas are written in YAML in test suites
+	const schema = `
+type: object
+required: [title, tags]
+additionalProperties: false
+properties:
+  title: {type: string, minLength: 3, pattern: "^[A-Z]"}
+  score: {type: integer, minimum: 0, maximum: 10}
+  level: {enum: [low, high]}
+  tags:
+    type: array
+    minItems: 1
+    items: {type: string}
+`
+
+	testCases := []struct {
+		name    string
+		output  string
+		wantErr bool
+	}{
+		{name: "valid", output: `{"title": "Talk", "score": 7, "level": "low", "tags": ["ai"]}`},
+		{name: "code fence", output: "```json\n{\"title\": \"Talk\", \"tags\": [\"ai\"]}\n```"},
+		{name: "not json", output: "Here is the JSON", wantErr: true},
+		{name: "missing required", output: `{"title": "Talk"}`, wantErr: true},
+		{name: "wrong type", output: `{"title": 1, "tags": ["ai"]}`, wantErr: true},
+		{name: "too short", output: `{"title": "Ta", "tags": ["ai"]}`, wantErr: true},
+		{name: "pattern", output: `{"title": "talk", "tags": ["ai"]}`, wantErr: true},
+		{name: "not an integer", output: `{"title": "Talk", "score": 7.5, "tags": ["ai"]}`, wantErr: true},
+		{name: "maximum", output: `{"title": "Talk", "score": 11, "tags": ["ai"]}`, wantErr: true},
+		{name: "enum", output: `{"title": "Talk", "level": "medium", "tags": ["ai"]}`, wantErr: true},
+		{name: "min items", output: `{"title": "Talk", "tags": []}`, wantErr: true},
+		{name: "items", output: `{"title": "Talk", "tags": [1]}`, wantErr: true},
+		{name: "additional property", output: `{"title": "Talk", "tags": ["ai"], "extra": true}`, wantErr: true},
+	}
+
+	r := require.New(t)
+
+	var s map[string]any
+	r.NoError(yaml.Unmarshal([]byte(schema), &s))
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			v, err := parseJSONOutput(tt.output)
+			if err == nil {
+				err = validateSchema(s, v, "$")
+			}
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+		})
+	}
+}
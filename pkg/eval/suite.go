@@ -0,0 +1,178 @@
+// Package eval runs regression tests of patterns against fixture cases.
+package eval
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SuiteFile is the name of the file holding the test cases of a pattern, next to its system.md.
+const SuiteFile = "tests.yaml"
+
+// ErrNoSuite is returned when a pattern has no test cases.
+var ErrNoSuite = errors.New("pattern has no tests")
+
+// Suite is the set of test cases of a pattern.
+//
+// ```yaml
+//
+//	model: openai/gpt-4o-mini
+//	judge: anthropic/claude-haiku-4-5
+//	cases:
+//	  - name: french summary
+//	    input_file: talk.txt
+//	    vars:
+//	      language: French
+//	    assert:
+//	      - contains: "# SUMMARY"
+//	      - max_length: 2000
+//	      - judge: The summary is written in French
+//
+// ```
+type Suite struct {
+	// Model runs the cases that don't set their own model.
+	Model string `yaml:"model"`
+	// Judge grades judge assertions, defaults to the model of each case.
+	Judge string `yaml:"judge"`
+	// Cases are the test cases, run concurrently.
+	Cases []Case `yaml:"cases"`
+}
+
+// Case is an input of a pattern and the assertions its output must satisfy.
+type Case struct {
+	Name string `yaml:"name"`
+	// Input is the content given to the pattern.
+	Input string `yaml:"input"`
+	// InputFile is a file holding the input, relative to the pattern's directory.
+	InputFile string `yaml:"input_file"`
+	// Vars are the values of the pattern's variables.
+	Vars map[string]string `yaml:"vars"`
+	// Model overrides the model of the suite.
+	Model string `yaml:"model"`
+	// Temperature overrides the temperature of the pattern.
+	Temperature *float64 `yaml:"temperature"`
+	// MockOutput is the output of the mock model, which echoes the input if unset.
+	MockOutput string `yaml:"mock_output"`
+	// Assert lists the assertions on the output.
+	Assert []Assertion `yaml:"assert"`
+}
+
+// Assertion checks an output. Exactly one of its fields is set.
+type Assertion struct {
+	// Contains requires the output to contain a string.
+	Contains string `yaml:"contains"`
+	// NotContains requires the output not to contain a string.
+	NotContains string `yaml:"not_contains"`
+	// Regex requires the output to match a regular expression.
+	Regex string `yaml:"regex"`
+	// JSONSchema requires the output to be JSON valid against a schema, see validateSchema.
+	JSONSchema map[string]any `yaml:"json_schema"`
+	// MaxLength limits the number of characters of the output.
+	MaxLength int `yaml:"max_length"`
+	// Judge is a rubric graded by a model.
+	Judge string `yaml:"judge"`
+}
+
+// LoadSuite reads the test cases of a pattern from a repository.
+// Input files are read relative to the pattern's directory.
+func LoadSuite(fsys fs.FS, pattern string) (Suite, error) {
+	data, err := fs.ReadFile(fsys, path.Join(pattern, SuiteFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return Suite{}, fmt.Errorf("%w: add cases to %s next to its system.md", ErrNoSuite, SuiteFile)
+		}
+		return Suite{}, err
+	}
+
+	var s Suite
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&s); err != nil {
+		return Suite{}, fmt.Errorf("parsing %s: %w", SuiteFile, err)
+	}
+
+	if len(s.Cases) == 0 {
+		return Suite{}, fmt.Errorf("%w: %s has no cases", ErrNoSuite, SuiteFile)
+	}
+
+	seen := make(map[string]bool, len(s.Cases))
+	for i := range s.Cases {
+		c := &s.Cases[i]
+		if err := c.validate(); err != nil {
+			if c.Name == "" {
+				return Suite{}, fmt.Errorf("case %d: %w", i+1, err)
+			}
+			return Suite{}, fmt.Errorf("case %q: %w", c.Name, err)
+		}
+		if seen[c.Name] {
+			return Suite{}, fmt.Errorf("case %q: duplicate name", c.Name)
+		}
+		seen[c.Name] = true
+
+		if c.InputFile != "" {
+			input, err := fs.ReadFile(fsys, path.Join(pattern, c.InputFile))
+			if err != nil {
+				return Suite{}, fmt.Errorf("case %q: reading input file: %w", c.Name, err)
+			}
+			c.Input = string(input)
+		}
+	}
+
+	return s, nil
+}
+
+func (c Case) validate() error {
+	if c.Name == "" {
+		return errors.New("name is required")
+	}
+	if c.Input != "" && c.InputFile != "" {
+		return errors.New("input and input_file are mutually exclusive")
+	}
+	if c.InputFile != "" && !fs.ValidPath(path.Clean(c.InputFile)) {
+		return errors.New("input_file must be relative to the pattern's directory")
+	}
+	if len(c.Assert) == 0 {
+		return errors.New("at least one assertion is required")
+	}
+	for i, a := range c.Assert {
+		if err := a.validate(); err != nil {
+			return fmt.Errorf("assertion %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+func (a Assertion) validate() error {
+	set := 0
+	for _, ok := range []bool{
+		a.Contains != "",
+		a.NotContains != "",
+		a.Regex != "",
+		a.JSONSchema != nil,
+		a.MaxLength != 0,
+		a.Judge != "",
+	} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return errors.New("exactly one of contains, not_contains, regex, json_schema, max_length or judge must be set")
+	}
+
+	if a.Regex != "" {
+		if _, err := regexp.Compile(a.Regex); err != nil {
+			return fmt.Errorf("invalid regex: %w", err)
+		}
+	}
+	if a.MaxLength < 0 {
+		return errors.New("max_length must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package eval
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadSuite(t *testing.T) {
+	testCases := []struct {
+		name    string
+		suite   string
+		want    Suite
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			suite: `
+model: openai/gpt-4o-mini
+cases:
+  - name: inline
+    input: hello
+    vars: {language: French}
+    assert:
+      - contains: bonjour
+      - max_length: 100
+  - name: from file
+    input_file: talk.txt
+    assert:
+      - judge: The summary is short
+`,
+			want: Suite{
+				Model: "openai/gpt-4o-mini",
+				Cases: []Case{
+					{
+						Name:   "inline",
+						Input:  "hello",
+						Vars:   map[string]string{"language": "French"},
+						Assert: []Assertion{{Contains: "bonjour"}, {MaxLength: 100}},
+					},
+					{
+						Name:      "from file",
+						Input:     "a long talk",
+						InputFile: "talk.txt",
+						Assert:    []Assertion{{Judge: "The summary is short"}},
+					},
+				},
+			},
+		},
+		{
+			name:    "no cases",
+			suite:   "model: mock\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			suite:   "cases:\n  - name: a\n    inputs: hello\n    assert: [{contains: a}]\n",
+			wantErr: true,
+		},
+		{
+			name:    "missing name",
+			suite:   "cases:\n  - input: hello\n    assert: [{contains: a}]\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate name",
+			suite:   "cases:\n  - name: a\n    assert: [{contains: a}]\n  - name: a\n    assert: [{contains: a}]\n",
+			wantErr: true,
+		},
+		{
+			name:    "no assertion",
+			suite:   "cases:\n  - name: a\n    input: hello\n",
+			wantErr: true,
+		},
+		{
+			name:    "several kinds in one assertion",
+			suite:   "cases:\n  - name: a\n    assert: [{contains: a, regex: b}]\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid regex",
+			suite:   "cases:\n  - name: a\n    assert: [{regex: '('}]\n",
+			wantErr: true,
+		},
+		{
+			name:    "input file outside of the pattern",
+			suite:   "cases:\n  - name: a\n    input_file: ../secret.txt\n    assert: [{contains: a}]\n",
+			wantErr: true,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			fsys := fstest.MapFS{
+				"summarize/system.md":  {Data: []byte("Summarize.")},
+				"summarize/tests.yaml": {Data: []byte(tt.suite)},
+				"summarize/talk.txt":   {Data: []byte("a long talk")},
+			}
+
+			s, err := LoadSuite(fsys, "summarize")
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.want, s)
+		})
+	}
+}
+
+func TestLoadSuite_NoSuite(t *testing.T) {
+	fsys := fstest.MapFS{"summarize/system.md": {Data: []byte("Summarize.")}}
+
+	_, err := LoadSuite(fsys, "summarize")
+	require.ErrorIs(t, err, ErrNoSuite)
+}
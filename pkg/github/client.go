@@ -0,0 +1,279 @@
+package github
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/nt54hamnghi/seaq/pkg/env"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+)
+
+// apiVersion is the version of the GitHub REST API requests are made against.
+const apiVersion = "2022-11-28"
+
+// apiHost is the host of the GitHub API, the only host GITHUB_TOKEN is sent to.
+var apiHost = "api.github.com"
+
+// maxCacheAge is the age above which cached responses are removed.
+const maxCacheAge = 30 * 24 * time.Hour
+
+// client is the HTTP client used for all GitHub API requests.
+var client = &http.Client{
+	Transport: &transport{base: http.DefaultTransport},
+}
+
+// transport is an http.RoundTripper for the GitHub API. It:
+//   - authenticates requests to the API with GITHUB_TOKEN, if set, but not redirects to other hosts
+//   - revalidates cached responses with their ETag, as 304 responses don't count against the rate limit
+//   - turns rate-limited responses into a RateLimitError
+type transport struct {
+	base http.RoundTripper
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// a RoundTripper must not modify the original request
+	req = req.Clone(req.Context())
+	req.Header.Set("X-GitHub-Api-Version", apiVersion)
+
+	var token string
+	if req.URL.Host == apiHost {
+		token, _ = env.GitHubToken()
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	key := cacheKey(req, token)
+	cached, hit := readCache[cachedResponse](key)
+	if hit && cached.ETag != "" {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkRateLimit(res, token != ""); err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotModified && hit {
+		res.Body.Close()
+		log.Debug("GitHub response not modified", "url", req.URL.String())
+		return cached.response(req), nil
+	}
+
+	etag := res.Header.Get("ETag")
+	if res.StatusCode != http.StatusOK || etag == "" {
+		return res, nil
+	}
+
+	// keep the body for later revalidation
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+
+	writeCache(key, cachedResponse{
+		ETag:        etag,
+		ContentType: res.Header.Get("Content-Type"),
+		Body:        body,
+	})
+
+	return res, nil
+}
+
+// cachedResponse is a GitHub API response stored for revalidation.
+type cachedResponse struct {
+	ETag        string `json:"etag"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// response rebuilds the cached response as a 200 OK response to req.
+func (c cachedResponse) response(req *http.Request) *http.Response {
+	header := make(http.Header)
+	header.Set("Content-Type", c.ContentType)
+	header.Set("ETag", c.ETag)
+
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(c.Body)),
+		ContentLength: int64(len(c.Body)),
+		Request:       req,
+	}
+}
+
+// cacheKey identifies a cached response by its URL and the requested media type,
+// since the same URL returns different representations, e.g. raw or JSON file content.
+// The key also depends on the token the request is made with, so a response to a private
+// repository is never served to requests made without it, or with another token.
+func cacheKey(req *http.Request, token string) string {
+	if req.Method != http.MethodGet {
+		return ""
+	}
+
+	var user string
+	if token != "" {
+		sum := sha256.Sum256([]byte(token))
+		user = hex.EncodeToString(sum[:8])
+	}
+	return "response:" + user + " " + req.Header.Get("Accept") + " " + req.URL.String()
+}
+
+// cacheDir returns the directory where GitHub responses are cached.
+func cacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "seaq", "github"), nil
+}
+
+// readCache reads a cached value. Any failure is treated as a cache miss.
+func readCache[T any](key string) (T, bool) {
+	var v T
+	if key == "" {
+		return v, false
+	}
+
+	dir, err := cacheDir()
+	if err != nil {
+		return v, false
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, cacheFile(key)))
+	if err != nil {
+		return v, false
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return v, false
+	}
+	return v, true
+}
+
+// writeCache stores a cached value. Failures are logged and otherwise ignored,
+// as the cache is an optimization.
+func writeCache(key string, v any) {
+	if key == "" {
+		return
+	}
+
+	err := func() error {
+		dir, err := cacheDir()
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(dir, 0o700); err != nil {
+			return err
+		}
+		pruneCache(dir)
+
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dir, cacheFile(key)), data, 0o600)
+	}()
+	if err != nil {
+		log.Debug("Failed to cache GitHub response", "error", err)
+	}
+}
+
+// pruneCache removes the cached values that weren't written for maxCacheAge,
+// such as responses of repositories that are no longer used.
+func pruneCache(dir string) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || time.Since(info.ModTime()) < maxCacheAge {
+			continue
+		}
+		if err := os.Remove(filepath.Join(dir, e.Name())); err != nil {
+			log.Debug("Failed to remove cached GitHub response", "error", err)
+		}
+	}
+}
+
+func cacheFile(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:]) + ".json"
+}
+
+// RateLimitError is returned when the GitHub API rate limit is exceeded.
+type RateLimitError struct {
+	// Reset is when the rate limit resets, zero if unknown
+	Reset time.Time
+	// Authenticated is whether the request was made with GITHUB_TOKEN
+	Authenticated bool
+}
+
+func (e *RateLimitError) Error() string {
+	msg := "GitHub API rate limit exceeded"
+	if !e.Reset.IsZero() {
+		wait := time.Until(e.Reset).Round(time.Second)
+		msg += fmt.Sprintf(", resets at %s (in %s)", e.Reset.Local().Format(time.TimeOnly), max(wait, 0))
+	}
+	if !e.Authenticated {
+		msg += "; set GITHUB_TOKEN to raise the limit"
+	}
+	return msg
+}
+
+// IsRateLimit reports whether err is caused by the GitHub API rate limit.
+func IsRateLimit(err error) bool {
+	var rateLimitErr *RateLimitError
+	return errors.As(err, &rateLimitErr)
+}
+
+// checkRateLimit returns a RateLimitError if a response was rejected because of the rate limit.
+//
+// See: https://docs.github.com/rest/using-the-rest-api/rate-limits-for-the-rest-api#exceeding-the-rate-limit
+func checkRateLimit(res *http.Response, authenticated bool) error {
+	if res.StatusCode != http.StatusForbidden && res.StatusCode != http.StatusTooManyRequests {
+		return nil
+	}
+
+	e := &RateLimitError{Authenticated: authenticated}
+
+	// secondary rate limits tell how long to wait
+	if after := res.Header.Get("Retry-After"); after != "" {
+		if secs, err := strconv.Atoi(after); err == nil {
+			e.Reset = time.Now().Add(time.Duration(secs) * time.Second)
+			return e
+		}
+	}
+
+	// primary rate limits tell when they reset
+	if res.Header.Get("X-RateLimit-Remaining") != "0" {
+		// a regular 403, e.g. a private repository
+		return nil
+	}
+	if reset, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		e.Reset = time.Unix(reset, 0)
+	}
+	return e
+}
@@ -2,9 +2,14 @@ package github
 
 import (
 	"context"
+	"crypto/sha1" // nolint: gosec
+	"encoding/hex"
 	"fmt"
+	"net/http"
 	"net/url"
+	"path"
 	"strings"
+	"time"
 
 	"github.com/nt54hamnghi/seaq/pkg/util/reqx"
 )
@@ -15,6 +20,7 @@ type Repository struct {
 	Repo       string   // name of the repository
 	OriginURL  *url.URL // original GitHub URL of the repository
 	ContentURL *url.URL // GitHub API URL for accessing repository contents
+	Ref        string   // branch, tag or commit SHA to read from, the default branch if empty
 }
 
 // ParseRepositoryURL takes a GitHub repository URL and returns a Repository struct.
@@ -45,9 +51,54 @@ func ParseRepositoryURL(v string) (Repository, error) {
 	}, nil
 }
 
+// WithRef returns a copy of the repository that reads from a branch, tag or commit SHA.
+func (r Repository) WithRef(ref string) Repository {
+	r.Ref = ref
+	return r
+}
+
+// withRef adds the ref query parameter of the contents API to a URL, if the repository has a ref.
+func (r Repository) withRef(u *url.URL) string {
+	if r.Ref != "" {
+		u.RawQuery = url.Values{"ref": {r.Ref}}.Encode()
+	}
+	return u.String()
+}
+
+// ResolveRef returns the SHA of the commit the repository's ref points to,
+// or of the latest commit of the default branch if the repository has no ref.
+func (r Repository) ResolveRef(ctx context.Context) (string, error) {
+	ref := r.Ref
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// contentURL: https://api.github.com/repos/:owner/:repo/contents
+	// commitURL: https://api.github.com/repos/:owner/:repo/commits/:ref
+	commitURL := r.ContentURL.JoinPath("..", "commits", ref)
+	res, err := get(ctx, commitURL.String(), map[string][]string{
+		// for getting the commit SHA only
+		"Accept": {"application/vnd.github.sha"},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	sha, err := res.String()
+	if err != nil {
+		return "", fmt.Errorf("resolving ref %q: %w", ref, err)
+	}
+	return strings.TrimSpace(sha), nil
+}
+
 func (r Repository) DownloadPattern(ctx context.Context, patternName string) (string, error) {
-	downloadURL := r.ContentURL.JoinPath("patterns", patternName, "system.md")
-	res, err := reqx.Get(ctx, downloadURL.String(), map[string][]string{
+	return r.DownloadFile(ctx, path.Join("patterns", patternName, "system.md"))
+}
+
+// DownloadFile returns the raw content of a file, given its path relative to the repository root.
+func (r Repository) DownloadFile(ctx context.Context, filePath string) (string, error) {
+	downloadURL := r.ContentURL.JoinPath(filePath)
+	res, err := get(ctx, r.withRef(downloadURL), map[string][]string{
 		// for downloading the raw file content
 		"Accept": {"application/vnd.github.raw+json"},
 	})
@@ -58,12 +109,63 @@ func (r Repository) DownloadPattern(ctx context.Context, patternName string) (st
 	return res.String()
 }
 
+// ListFiles returns the names of the files directly in a directory of the repository,
+// given its path relative to the repository root. Subdirectories are left out.
+func (r Repository) ListFiles(ctx context.Context, dir string) ([]string, error) {
+	content, err := getAs[getContentResponse](ctx,
+		r.withRef(r.ContentURL.JoinPath(dir)),
+		map[string][]string{
+			// to get the contents in a consistent object format, with the directory entries
+			"Accept": {"application/vnd.github.object+json"},
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if content.Type != "dir" {
+		return nil, fmt.Errorf("expected directory, got %s", content.Type)
+	}
+
+	names := make([]string, 0, len(content.Entries))
+	for _, e := range content.Entries {
+		if e.Type == "file" {
+			names = append(names, e.Name)
+		}
+	}
+	return names, nil
+}
+
 // GetPatternNames retrieves all system pattern files from the repository's patterns directory.
+// Nested patterns are named by their path relative to the patterns directory (e.g. "team/review/security").
 // Returns a list of patterns found, or an error if the patterns
 // directory doesn't exist or can't be accessed.
 func (r Repository) GetPatternNames(ctx context.Context) ([]string, error) {
-	content, err := reqx.GetAs[getContentResponse](ctx,
-		r.ContentURL.String()+"/patterns",
+	blobs, err := r.GetPatterns(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(blobs))
+	for i, b := range blobs {
+		names[i] = b.Name
+	}
+	return names, nil
+}
+
+// PatternBlob is the system pattern file of a pattern in a remote repository.
+type PatternBlob struct {
+	// Name is the name of the pattern
+	Name string `json:"name"`
+	// SHA is the git blob SHA of the pattern's system.md
+	SHA string `json:"sha"`
+}
+
+// GetPatterns retrieves all system pattern files from the repository's patterns directory,
+// along with their git blob SHA.
+func (r Repository) GetPatterns(ctx context.Context) ([]PatternBlob, error) {
+	content, err := getAs[getContentResponse](ctx,
+		r.withRef(r.ContentURL.JoinPath("patterns")),
 		map[string][]string{
 			// to get the contents in a consistent object format, with a git tree URL
 			"Accept": {"application/vnd.github.object+json"},
@@ -81,27 +183,130 @@ func (r Repository) GetPatternNames(ctx context.Context) ([]string, error) {
 		return nil, fmt.Errorf("unexpected: no git tree URL found")
 	}
 
+	// the git tree URL points to the tree of the requested ref
 	// recursive=1: get the tree recursively
-	tree, err := reqx.GetAs[getGitTree](ctx, *content.GitURL+"?recursive=1", nil)
+	tree, err := getAs[getGitTree](ctx, *content.GitURL+"?recursive=1", nil)
 	if err != nil {
 		return nil, err
 	}
 
-	patterns := make([]string, 0, len(tree.Tree))
+	leaves := tree.Tree
+	if tree.Truncated {
+		// the recursive tree is too large to be returned at once,
+		// so walk it one directory at a time
+		leaves, err = walkTree(ctx, *content.GitURL, "")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	patterns := make([]PatternBlob, 0, len(leaves))
+	for _, l := range leaves {
+		if l.Type == nil || l.Size == nil || l.Path == nil || l.SHA == nil {
+			continue
+		}
+
+		// a system.md directly in the patterns directory has no pattern name
+		if strings.HasSuffix(*l.Path, "/system.md") && *l.Type == "blob" && *l.Size > 0 {
+			patterns = append(patterns, PatternBlob{
+				Name: strings.TrimSuffix(*l.Path, "/system.md"),
+				SHA:  *l.SHA,
+			})
+		}
+	}
+
+	return patterns, nil
+}
+
+// walkTree lists the leaves of a git tree and its subtrees, with paths relative to the root tree.
+// Each subtree is fetched separately, so that it works for trees too large to be fetched recursively.
+func walkTree(ctx context.Context, treeURL string, prefix string) ([]leaf, error) {
+	tree, err := getAs[getGitTree](ctx, treeURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	if tree.Truncated {
+		return nil, fmt.Errorf("tree %s has too many entries", prefix)
+	}
+
+	var leaves []leaf
 	for _, l := range tree.Tree {
-		if l.Type == nil || l.Size == nil || l.Path == nil {
+		if l.Type == nil || l.Path == nil {
 			continue
 		}
 
-		// nested patterns are not supported yet!
-		if strings.HasSuffix(*l.Path, "system.md") && *l.Type == "blob" && *l.Size > 0 {
-			if strings.Count(*l.Path, "/") == 1 {
-				patterns = append(patterns, strings.TrimSuffix(*l.Path, "/system.md"))
+		p := path.Join(prefix, *l.Path)
+		l.Path = &p
+		leaves = append(leaves, l)
+
+		if *l.Type == "tree" && l.URL != nil {
+			sub, err := walkTree(ctx, *l.URL, p)
+			if err != nil {
+				return nil, err
 			}
+			leaves = append(leaves, sub...)
 		}
 	}
 
-	return patterns, nil
+	return leaves, nil
+}
+
+// Listing is the patterns of a repository at a commit.
+type Listing struct {
+	// Revision is the SHA of the commit the patterns were listed at.
+	Revision string `json:"revision"`
+	// Patterns are the patterns of the repository.
+	Patterns []PatternBlob `json:"patterns"`
+	// ListedAt is when the patterns were listed.
+	ListedAt time.Time `json:"listed_at"`
+}
+
+// ListPatterns resolves the repository's ref and lists its patterns at the resolved commit.
+//
+// If maxAge is positive, a listing cached locally less than maxAge ago is returned without
+// any request, which keeps frequent listings, such as shell completions, within the rate limit.
+func (r Repository) ListPatterns(ctx context.Context, maxAge time.Duration) (Listing, error) {
+	key := fmt.Sprintf("listing:%s/%s@%s", r.Owner, r.Repo, r.Ref)
+
+	if maxAge > 0 {
+		if l, ok := readCache[Listing](key); ok && time.Since(l.ListedAt) < maxAge {
+			return l, nil
+		}
+	}
+
+	revision, err := r.ResolveRef(ctx)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	patterns, err := r.WithRef(revision).GetPatterns(ctx)
+	if err != nil {
+		return Listing{}, err
+	}
+
+	l := Listing{Revision: revision, Patterns: patterns, ListedAt: time.Now()}
+	writeCache(key, l)
+
+	return l, nil
+}
+
+// BlobSHA computes the git blob SHA of a file's content,
+// which is how git and GitHub identify file versions.
+func BlobSHA(content []byte) string {
+	h := sha1.New() // nolint: gosec
+	fmt.Fprintf(h, "blob %d\x00", len(content))
+	h.Write(content)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// get makes a GET request to the GitHub API.
+func get(ctx context.Context, u string, headers map[string][]string) (*reqx.Response, error) {
+	return reqx.WithClient(client)(ctx, http.MethodGet, u, headers, nil)
+}
+
+// getAs makes a GET request to the GitHub API and unmarshals the response into a struct of type T.
+func getAs[T any](ctx context.Context, u string, headers map[string][]string) (T, error) {
+	return reqx.WithClientAs[T](client)(ctx, http.MethodGet, u, headers, nil)
 }
 
 // getContentResponse represents the GitHub API response for getting repository contents.
@@ -109,8 +314,15 @@ func (r Repository) GetPatternNames(ctx context.Context) ([]string, error) {
 //
 // See: https://docs.github.com/rest/repos/contents#get-repository-getContentResponse
 type getContentResponse struct {
-	Type   string  `json:"type"`    // type (e.g. "blob", "tree")
-	GitURL *string `json:"git_url"` // Git tree URL
+	Type    string         `json:"type"`    // type (e.g. "blob", "tree")
+	GitURL  *string        `json:"git_url"` // Git tree URL
+	Entries []contentEntry `json:"entries"` // items of a directory
+}
+
+// contentEntry is an item of a directory in a getContentResponse.
+type contentEntry struct {
+	Name string `json:"name"`
+	Type string `json:"type"` // type (e.g. "file", "dir")
 }
 
 // getGitTree represents the GitHub API response for getting a flat tree of a path.
@@ -127,5 +339,6 @@ type leaf struct {
 	Type *string `json:"type"` // object type (e.g. "blob", "tree")
 	Size *int64  `json:"size"`
 	Path *string `json:"path"`
+	SHA  *string `json:"sha"`
 	URL  *string `json:"url"`
 }
@@ -0,0 +1,352 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlobSHA(t *testing.T) {
+	r := require.New(t)
+
+	// git hash-object of "hello\n"
+	r.Equal("ce013625030ba8dba906f756967f9e9ca394464a", BlobSHA([]byte("hello\n")))
+	// git hash-object of an empty file
+	r.Equal("e69de29bb2d1d6434b8b29ae775ad8c2e48c5391", BlobSHA(nil))
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// testRepository returns a repository whose API is served by srv.
+func testRepository(t *testing.T, srv *httptest.Server) Repository {
+	t.Helper()
+	contentURL, err := url.Parse(srv.URL + "/repos/owner/repo/contents")
+	require.NoError(t, err)
+	return Repository{Owner: "owner", Repo: "repo", ContentURL: contentURL}
+}
+
+func TestTransport_ETag(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	r := require.New(t)
+
+	var full, notModified atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("If-None-Match") == `"v1"` {
+			notModified.Add(1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"v1"`)
+		writeJSON(w, map[string]string{"type": "dir"})
+	}))
+	defer srv.Close()
+
+	ctx := context.Background()
+	for range 3 {
+		got, err := getAs[getContentResponse](ctx, srv.URL+"/contents", nil)
+		r.NoError(err)
+		r.Equal("dir", got.Type)
+	}
+
+	r.Equal(int32(1), full.Load())
+	r.Equal(int32(2), notModified.Load())
+}
+
+func TestTransport_Token(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("GITHUB_TOKEN", "secret")
+	r := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	// the token is only sent to the API
+	res, err := get(context.Background(), srv.URL, nil)
+	r.NoError(err)
+	r.Equal(http.StatusUnauthorized, res.StatusCode)
+	res.Body.Close()
+
+	setAPIHost(t, srv)
+
+	res, err = get(context.Background(), srv.URL, nil)
+	r.NoError(err)
+	body, err := res.String()
+	r.NoError(err)
+	r.Equal("ok", body)
+}
+
+func TestTransport_CachePerToken(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	r := require.New(t)
+
+	var full atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("If-None-Match") == `"`+req.Header.Get("Authorization")+`"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		full.Add(1)
+		w.Header().Set("ETag", `"`+req.Header.Get("Authorization")+`"`)
+		writeJSON(w, map[string]string{"type": req.Header.Get("Authorization")})
+	}))
+	defer srv.Close()
+	setAPIHost(t, srv)
+
+	ctx := context.Background()
+	for _, token := range []string{"first", "second", "first", ""} {
+		t.Setenv("GITHUB_TOKEN", token)
+		got, err := getAs[getContentResponse](ctx, srv.URL+"/contents", nil)
+		r.NoError(err)
+		if token != "" {
+			r.Equal("Bearer "+token, got.Type)
+		} else {
+			r.Empty(got.Type)
+		}
+	}
+
+	// the second request of the first token is revalidated
+	r.Equal(int32(3), full.Load())
+}
+
+func TestPruneCache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	r := require.New(t)
+
+	writeCache("old", "value")
+	writeCache("new", "value")
+
+	dir, err := cacheDir()
+	r.NoError(err)
+	old := time.Now().Add(-maxCacheAge - time.Hour)
+	r.NoError(os.Chtimes(filepath.Join(dir, cacheFile("old")), old, old))
+
+	writeCache("other", "value")
+
+	_, ok := readCache[string]("old")
+	r.False(ok)
+	_, ok = readCache[string]("new")
+	r.True(ok)
+}
+
+// setAPIHost makes srv the GitHub API for the duration of the test.
+func setAPIHost(t *testing.T, srv *httptest.Server) {
+	t.Helper()
+	host := apiHost
+	apiHost = srv.Listener.Addr().String()
+	t.Cleanup(func() { apiHost = host })
+}
+
+func TestTransport_RateLimit(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	reset := time.Now().Add(10 * time.Minute).Truncate(time.Second)
+
+	testCases := []struct {
+		name      string
+		status    int
+		headers   map[string]string
+		wantLimit bool
+	}{
+		{
+			name:   "primary",
+			status: http.StatusForbidden,
+			headers: map[string]string{
+				"X-RateLimit-Remaining": "0",
+				"X-RateLimit-Reset":     strconv.FormatInt(reset.Unix(), 10),
+			},
+			wantLimit: true,
+		},
+		{
+			name:      "secondary",
+			status:    http.StatusTooManyRequests,
+			headers:   map[string]string{"Retry-After": "60"},
+			wantLimit: true,
+		},
+		{
+			name:      "forbidden",
+			status:    http.StatusForbidden,
+			headers:   map[string]string{"X-RateLimit-Remaining": "42"},
+			wantLimit: false,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				for k, v := range tt.headers {
+					w.Header().Set(k, v)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			res, err := get(context.Background(), srv.URL, nil)
+			if !tt.wantLimit {
+				r.NoError(err)
+				r.Equal(tt.status, res.StatusCode)
+				return
+			}
+
+			r.True(IsRateLimit(err))
+			r.ErrorContains(err, "resets at")
+			r.ErrorContains(err, "GITHUB_TOKEN")
+		})
+	}
+}
+
+func TestGetPatterns_Truncated(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	r := require.New(t)
+
+	var srv *httptest.Server
+	tree := func(p string) string { return srv.URL + "/repos/owner/repo/git/trees/" + p }
+	entry := func(typ, p, sha string, size int64, u string) map[string]any {
+		return map[string]any{"type": typ, "path": p, "sha": sha, "size": size, "url": u}
+	}
+
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/repos/owner/repo/contents/patterns":
+			r.Equal("v1", req.URL.Query().Get("ref"))
+			writeJSON(w, map[string]any{"type": "dir", "git_url": tree("root")})
+		case "/repos/owner/repo/git/trees/root":
+			if req.URL.Query().Get("recursive") != "" {
+				writeJSON(w, map[string]any{"tree": []any{}, "truncated": true})
+				return
+			}
+			writeJSON(w, map[string]any{"tree": []any{
+				entry("tree", "summarize", "t1", 0, tree("summarize")),
+				entry("tree", "team", "t2", 0, tree("team")),
+			}})
+		case "/repos/owner/repo/git/trees/summarize":
+			writeJSON(w, map[string]any{"tree": []any{
+				entry("blob", "system.md", "s1", 10, ""),
+			}})
+		case "/repos/owner/repo/git/trees/team":
+			writeJSON(w, map[string]any{"tree": []any{
+				entry("tree", "review", "t3", 0, tree("review")),
+			}})
+		case "/repos/owner/repo/git/trees/review":
+			writeJSON(w, map[string]any{"tree": []any{
+				entry("blob", "system.md", "s2", 10, ""),
+				entry("blob", "notes.md", "s3", 10, ""),
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	repo := testRepository(t, srv).WithRef("v1")
+	got, err := repo.GetPatterns(context.Background())
+	r.NoError(err)
+	r.Equal([]PatternBlob{
+		{Name: "summarize", SHA: "s1"},
+		{Name: "team/review", SHA: "s2"},
+	}, got)
+}
+
+func TestListPatterns_Cache(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	r := require.New(t)
+
+	var requests atomic.Int32
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		switch req.URL.Path {
+		case "/repos/owner/repo/commits/HEAD":
+			_, _ = w.Write([]byte("c0ffee"))
+		case "/repos/owner/repo/contents/patterns":
+			r.Equal("c0ffee", req.URL.Query().Get("ref"))
+			writeJSON(w, map[string]any{"type": "dir", "git_url": srv.URL + "/repos/owner/repo/git/trees/root"})
+		case "/repos/owner/repo/git/trees/root":
+			writeJSON(w, map[string]any{"tree": []any{
+				map[string]any{"type": "blob", "path": "summarize/system.md", "sha": "s1", "size": 10},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	repo := testRepository(t, srv)
+	ctx := context.Background()
+
+	want := []PatternBlob{{Name: "summarize", SHA: "s1"}}
+
+	listing, err := repo.ListPatterns(ctx, time.Minute)
+	r.NoError(err)
+	r.Equal("c0ffee", listing.Revision)
+	r.Equal(want, listing.Patterns)
+	r.Equal(int32(3), requests.Load())
+
+	// served from the cache
+	listing, err = repo.ListPatterns(ctx, time.Minute)
+	r.NoError(err)
+	r.Equal(want, listing.Patterns)
+	r.Equal(int32(3), requests.Load())
+
+	// no cache
+	_, err = repo.ListPatterns(ctx, 0)
+	r.NoError(err)
+	r.Equal(int32(6), requests.Load())
+}
+
+func TestListFiles(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	r := require.New(t)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		switch req.URL.Path {
+		case "/repos/owner/repo/contents/strategies":
+			r.Equal("v1", req.URL.Query().Get("ref"))
+			writeJSON(w, map[string]any{"type": "dir", "entries": []any{
+				map[string]any{"name": "cot.json", "type": "file"},
+				map[string]any{"name": "drafts", "type": "dir"},
+				map[string]any{"name": "tot.json", "type": "file"},
+			}})
+		case "/repos/owner/repo/contents/strategies/cot.json":
+			r.Equal("v1", req.URL.Query().Get("ref"))
+			_, _ = w.Write([]byte(`{"prompt": "Think step by step."}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	repo := testRepository(t, srv).WithRef("v1")
+	ctx := context.Background()
+
+	files, err := repo.ListFiles(ctx, "strategies")
+	r.NoError(err)
+	r.Equal([]string{"cot.json", "tot.json"}, files)
+
+	content, err := repo.DownloadFile(ctx, "strategies/cot.json")
+	r.NoError(err)
+	r.JSONEq(`{"prompt": "Think step by step."}`, content)
+
+	_, err = repo.ListFiles(ctx, "missing")
+	r.Error(err)
+}
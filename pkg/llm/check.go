@@ -0,0 +1,140 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/nt54hamnghi/seaq/pkg/env"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// CheckStage identifies the step of a connection check.
+type CheckStage string
+
+const (
+	StageEnv        CheckStage = "env"
+	StageList       CheckStage = "list"
+	StageCompletion CheckStage = "completion"
+)
+
+// CheckResult is the outcome of checking a connection.
+type CheckResult struct {
+	Provider string
+	// Models is the number of models returned by the provider.
+	Models int
+	// Model is the model used for the completion check.
+	Model string
+	// ListLatency is the time it took to list models.
+	ListLatency time.Duration
+	// CompletionLatency is the time it took to complete a one-token request.
+	CompletionLatency time.Duration
+	// Stage is the step that failed, empty if the check succeeded.
+	Stage CheckStage
+	Err   error
+}
+
+// OK reports whether the check succeeded.
+func (r CheckResult) OK() bool {
+	return r.Err == nil
+}
+
+// Check verifies that a connection works by:
+//  1. checking that its environment variable is set
+//  2. listing its models
+//  3. running a one-token completion against a model
+//
+// If model is empty, the first listed model is used.
+// Check stops at the first failing step and records it in the result.
+func (c Connection) Check(ctx context.Context, model string) CheckResult {
+	res := CheckResult{Provider: c.Provider, Model: model}
+
+	if _, err := env.Get(c.EnvKey); err != nil {
+		res.Stage, res.Err = StageEnv, err
+		return res
+	}
+
+	start := time.Now()
+	models, err := c.List(ctx)
+	res.ListLatency = time.Since(start)
+	if err != nil {
+		res.Stage, res.Err = StageList, err
+		return res
+	}
+	res.Models = len(models)
+
+	if res.Model == "" {
+		if len(models) == 0 {
+			res.Stage, res.Err = StageList, ErrModelsListEmpty
+			return res
+		}
+		res.Model = models[0]
+	}
+
+	llm, err := c.NewModel(res.Model)
+	if err != nil {
+		res.Stage, res.Err = StageCompletion, err
+		return res
+	}
+
+	start = time.Now()
+	err = CreateCompletion(ctx, llm, io.Discard, []llms.MessageContent{
+		llms.TextParts(llms.ChatMessageTypeHuman, "ping"),
+	}, llms.WithMaxTokens(1))
+	res.CompletionLatency = time.Since(start)
+	if err != nil {
+		res.Stage, res.Err = StageCompletion, err
+	}
+
+	return res
+}
+
+var statusCodeRegex = regexp.MustCompile(`status code:? (\d{3})`)
+
+// DescribeError explains common connection errors in plain language.
+// Errors it doesn't recognize are returned as is.
+func DescribeError(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "request timed out, the server may be slow or unreachable"
+	}
+
+	// DNSError is usually wrapped in an OpError, so it must be checked first
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return fmt.Sprintf("could not resolve host %q, check the base URL", dnsErr.Name)
+	}
+
+	var netErr *net.OpError
+	if errors.As(err, &netErr) {
+		return fmt.Sprintf("could not reach the server (%s), check the base URL", netErr.Op)
+	}
+
+	if m := statusCodeRegex.FindStringSubmatch(err.Error()); m != nil {
+		code, _ := strconv.Atoi(m[1])
+		switch code {
+		case 401:
+			return "authentication failed (401), check the API key"
+		case 403:
+			return "access denied (403), the API key may lack permissions for this resource"
+		case 404:
+			return "not found (404), check the base URL or model name"
+		case 429:
+			return "rate limited (429), try again later"
+		default:
+			if code >= 500 {
+				return fmt.Sprintf("server error (%d), the provider may be having issues", code)
+			}
+		}
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,68 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeError(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: "",
+		},
+		{
+			name: "timeout",
+			err:  fmt.Errorf("fetch models: %w", context.DeadlineExceeded),
+			want: "request timed out, the server may be slow or unreachable",
+		},
+		{
+			name: "connection refused",
+			err:  fmt.Errorf("send request: %w", &net.OpError{Op: "dial", Err: errors.New("connection refused")}),
+			want: "could not reach the server (dial), check the base URL",
+		},
+		{
+			name: "unknown host",
+			err:  fmt.Errorf("send request: %w", &net.DNSError{Name: "api.example.invalid"}),
+			want: `could not resolve host "api.example.invalid", check the base URL`,
+		},
+		{
+			name: "unauthorized",
+			err:  errors.New("fetch models: unexpected status code: 401 Unauthorized"),
+			want: "authentication failed (401), check the API key",
+		},
+		{
+			name: "not found",
+			err:  errors.New("generate content: API returned unexpected status code: 404"),
+			want: "not found (404), check the base URL or model name",
+		},
+		{
+			name: "server error",
+			err:  errors.New("unexpected status code: 503 Service Unavailable"),
+			want: "server error (503), the provider may be having issues",
+		},
+		{
+			name: "unrecognized",
+			err:  errors.New("GROQ_API_KEY is not set"),
+			want: "GROQ_API_KEY is not set",
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			r.Equal(tt.want, DescribeError(tt.err))
+		})
+	}
+}
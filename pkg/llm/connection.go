@@ -8,9 +8,12 @@ import (
 	"slices"
 	"strings"
 
+	"github.com/gobwas/glob"
 	"github.com/nt54hamnghi/seaq/pkg/env"
 	"github.com/nt54hamnghi/seaq/pkg/util/reqx"
 	"github.com/spf13/viper"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/openai"
 )
 
 // identRegex defines the regex for valid identifiers
@@ -26,13 +29,40 @@ type Connection struct {
 	Provider string `mapstructure:"provider" yaml:"provider"`
 	BaseURL  string `mapstructure:"base_url" yaml:"base_url"`
 	EnvKey   string `mapstructure:"env_key" yaml:"env_key"`
+	// Models is a static list of model IDs.
+	// If set, models are not discovered from the provider's /models endpoint.
+	Models []string `mapstructure:"models" yaml:"models,omitempty"`
+	// Include is a list of glob patterns. If set, only discovered models
+	// matching at least one of them are kept.
+	Include []string `mapstructure:"include" yaml:"include,omitempty"`
+	// Exclude is a list of glob patterns. Discovered models matching any of them are dropped.
+	Exclude []string `mapstructure:"exclude" yaml:"exclude,omitempty"`
+	// Overrides lists per-model metadata overrides.
+	// It's a list rather than a map keyed by model ID, as viper lowercases map keys.
+	Overrides []ModelOverride `mapstructure:"overrides" yaml:"overrides,omitempty"`
+	// SystemRole defines how patterns are delivered to the connection's models.
+	SystemRole SystemRole `mapstructure:"system_role" yaml:"system_role,omitempty"`
+}
+
+// ModelOverride holds per-model metadata overrides for a connection.
+type ModelOverride struct {
+	// Model is the upstream model ID the override applies to.
+	Model string `mapstructure:"model" yaml:"model"`
+	// Alias is the name the model is registered under instead of its upstream ID.
+	Alias string `mapstructure:"alias" yaml:"alias,omitempty"`
+	// SystemRole defines how patterns are delivered to the model.
+	SystemRole SystemRole `mapstructure:"system_role" yaml:"system_role,omitempty"`
 }
 
 func NewConnection(provider string, baseURL string, envKey string) Connection {
 	if envKey == "" {
 		envKey = strings.ToUpper(provider) + "_API_KEY"
 	}
-	return Connection{provider, baseURL, envKey}
+	return Connection{
+		Provider: provider,
+		BaseURL:  baseURL,
+		EnvKey:   envKey,
+	}
 }
 
 // GetProvider implements the ModelLister interface
@@ -43,7 +73,15 @@ func (c Connection) GetProvider() string {
 
 // List implements the ModelLister interface
 // and returns a slice of available model IDs from the provider.
+//
+// If the connection has a static model list, it's returned without making any request.
+// Otherwise, models are fetched from the provider and filtered by the include and exclude patterns.
+// In both cases, models with an alias override are listed under their alias.
 func (c Connection) List(ctx context.Context) ([]string, error) {
+	if len(c.Models) > 0 {
+		return c.applyAliases(c.Models), nil
+	}
+
 	secret, err := env.Get(c.EnvKey)
 	if err != nil {
 		return nil, err
@@ -59,7 +97,113 @@ func (c Connection) List(ctx context.Context) ([]string, error) {
 	for i, model := range res.Data {
 		models[i] = model.ID
 	}
-	return models, nil
+
+	models, err = c.filter(models)
+	if err != nil {
+		return nil, err
+	}
+	return c.applyAliases(models), nil
+}
+
+// filter keeps models matching any include pattern (or all models if there is none)
+// and drops models matching any exclude pattern.
+func (c Connection) filter(models []string) ([]string, error) {
+	include, err := compileGlobs(c.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include pattern: %w", err)
+	}
+	exclude, err := compileGlobs(c.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude pattern: %w", err)
+	}
+
+	filtered := make([]string, 0, len(models))
+	for _, m := range models {
+		if len(include) > 0 && !matchAny(include, m) {
+			continue
+		}
+		if matchAny(exclude, m) {
+			continue
+		}
+		filtered = append(filtered, m)
+	}
+	return filtered, nil
+}
+
+// applyAliases replaces model IDs with their aliases, if any.
+func (c Connection) applyAliases(models []string) []string {
+	aliased := make([]string, len(models))
+	for i, m := range models {
+		aliased[i] = m
+		if o, ok := c.override(m); ok && o.Alias != "" {
+			aliased[i] = o.Alias
+		}
+	}
+	return aliased
+}
+
+// ResolveModel returns the upstream model ID for a name, which is either an alias or an ID.
+func (c Connection) ResolveModel(name string) string {
+	for _, o := range c.Overrides {
+		if o.Alias != "" && o.Alias == name {
+			return o.Model
+		}
+	}
+	return name
+}
+
+// override returns the override of an upstream model ID and whether it exists.
+func (c Connection) override(model string) (ModelOverride, bool) {
+	i := slices.IndexFunc(c.Overrides, func(o ModelOverride) bool { return o.Model == model })
+	if i == -1 {
+		return ModelOverride{}, false
+	}
+	return c.Overrides[i], true
+}
+
+func compileGlobs(patterns []string) ([]glob.Glob, error) {
+	globs := make([]glob.Glob, 0, len(patterns))
+	for _, p := range patterns {
+		g, err := glob.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", p, err)
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+func matchAny(globs []glob.Glob, s string) bool {
+	for _, g := range globs {
+		if g.Match(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// NewModel creates an OpenAI-compatible model client for the connection.
+func (c Connection) NewModel(model string) (llms.Model, error) {
+	apiKey, err := env.Get(c.EnvKey)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []openai.Option{
+		openai.WithModel(c.ResolveModel(model)),
+		openai.WithToken(apiKey),
+		openai.WithBaseURL(c.BaseURL),
+	}
+
+	role, err := LookupSystemRole(c.Provider + "/" + model)
+	if err != nil {
+		return nil, err
+	}
+	if role == SystemRoleDeveloper {
+		opts = append(opts, openai.WithHTTPClient(newDeveloperRoleClient()))
+	}
+
+	return openai.New(opts...)
 }
 
 type listModelsResponse struct {
@@ -120,6 +264,18 @@ func (cs ConnectionSet) Get(provider string) (Connection, bool) {
 	return *c, true
 }
 
+// Update replaces an existing connection with the same provider.
+// It reports whether the provider was found.
+func (cs *ConnectionSet) Update(conn Connection) bool {
+	i := slices.IndexFunc(cs.connections, func(c Connection) bool { return c.Provider == conn.Provider })
+	if i == -1 {
+		return false
+	}
+	cs.connections[i] = conn
+	cs.index[conn.Provider] = &conn
+	return true
+}
+
 // Delete removes a provider from the collection set
 func (cs *ConnectionSet) Delete(provider string) {
 	cs.connections = slices.DeleteFunc(cs.connections, func(c Connection) bool { return c.Provider == provider })
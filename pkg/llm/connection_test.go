@@ -1,8 +1,10 @@
 package llm
 
 import (
+	"context"
 	"maps"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/spf13/viper"
@@ -186,3 +188,223 @@ func (s *ConnectionSetTestSuite) TestConnectionSet_Delete() {
 		})
 	}
 }
+
+func (s *ConnectionSetTestSuite) TestConnectionSet_Update() {
+	r := s.Require()
+
+	cs, err := GetConnectionSet()
+	r.NoError(err)
+
+	testCases := []struct {
+		name   string
+		conn   Connection
+		wantOk bool
+	}{
+		{
+			name: "existing provider",
+			conn: Connection{
+				Provider: "groq",
+				BaseURL:  "https://api.groq.com/openai/v2",
+				EnvKey:   "GROQ_SECRET",
+			},
+			wantOk: true,
+		},
+		{
+			name: "missing provider",
+			conn: Connection{
+				Provider: "anthropic",
+				BaseURL:  "https://api.anthropic.com/v1",
+				EnvKey:   "ANTHROPIC_API_KEY",
+			},
+			wantOk: false,
+		},
+	}
+
+	for _, tt := range testCases {
+		s.Run(tt.name, func() {
+			ok := cs.Update(tt.conn)
+			r.Equal(tt.wantOk, ok)
+
+			got, found := cs.Get(tt.conn.Provider)
+			r.Equal(tt.wantOk, found)
+			if tt.wantOk {
+				r.Equal(tt.conn, got)
+				r.Contains(cs.AsSlice(), tt.conn)
+			}
+		})
+	}
+
+	r.Len(cs.AsSlice(), 2)
+}
+
+func (s *ConnectionSetTestSuite) TestGetConnectionSet_ModelOptions() {
+	r := s.Require()
+
+	viper.Set("connections",
+		[]map[string]any{
+			{
+				"provider": "openrouter",
+				"base_url": "https://openrouter.ai/api/v1",
+				"env_key":  "OPENROUTER_API_KEY",
+				"include":  []string{"anthropic/*"},
+				"exclude":  []string{"*:free"},
+				"overrides": []map[string]any{
+					{"model": "anthropic/claude-sonnet-4", "alias": "sonnet"},
+				},
+			},
+		},
+	)
+
+	cs, err := GetConnectionSet()
+	r.NoError(err)
+
+	conn, ok := cs.Get("openrouter")
+	r.True(ok)
+	r.Equal([]string{"anthropic/*"}, conn.Include)
+	r.Equal([]string{"*:free"}, conn.Exclude)
+	r.Equal([]ModelOverride{
+		{Model: "anthropic/claude-sonnet-4", Alias: "sonnet"},
+	}, conn.Overrides)
+}
+
+func (s *ConnectionSetTestSuite) TestGetConnectionSet_OverridesFromYAML() {
+	r := s.Require()
+
+	// viper lowercases the keys of maps read from a config file, but not of those set directly
+	viper.Reset()
+	viper.SetConfigType("yaml")
+	r.NoError(viper.ReadConfig(strings.NewReader(`
+connections:
+  - provider: together
+    base_url: https://api.together.xyz/v1
+    env_key: TOGETHER_API_KEY
+    models: [Qwen/Qwen3-32B, meta-llama/Llama-3.3-70B-Instruct-Turbo]
+    overrides:
+      - model: Qwen/Qwen3-32B
+        alias: qwen
+`)))
+
+	cs, err := GetConnectionSet()
+	r.NoError(err)
+
+	conn, ok := cs.Get("together")
+	r.True(ok)
+	r.Equal("Qwen/Qwen3-32B", conn.ResolveModel("qwen"))
+
+	models, err := conn.List(context.Background())
+	r.NoError(err)
+	r.Equal([]string{"qwen", "meta-llama/Llama-3.3-70B-Instruct-Turbo"}, models)
+}
+
+func TestConnection_filter(t *testing.T) {
+	models := []string{
+		"anthropic/claude-sonnet-4",
+		"anthropic/claude-haiku-4:free",
+		"openai/gpt-4o",
+		"meta-llama/llama-3.3-70b-instruct",
+	}
+
+	testCases := []struct {
+		name    string
+		conn    Connection
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no filters",
+			conn: Connection{},
+			want: models,
+		},
+		{
+			name: "include only",
+			conn: Connection{Include: []string{"anthropic/*", "*llama*"}},
+			want: []string{
+				"anthropic/claude-sonnet-4",
+				"anthropic/claude-haiku-4:free",
+				"meta-llama/llama-3.3-70b-instruct",
+			},
+		},
+		{
+			name: "exclude only",
+			conn: Connection{Exclude: []string{"*:free"}},
+			want: []string{
+				"anthropic/claude-sonnet-4",
+				"openai/gpt-4o",
+				"meta-llama/llama-3.3-70b-instruct",
+			},
+		},
+		{
+			name: "include and exclude",
+			conn: Connection{Include: []string{"anthropic/*"}, Exclude: []string{"*:free"}},
+			want: []string{"anthropic/claude-sonnet-4"},
+		},
+		{
+			name:    "invalid pattern",
+			conn:    Connection{Include: []string{"[a-"}},
+			wantErr: true,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			got, err := tt.conn.filter(models)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestConnection_List_StaticModels(t *testing.T) {
+	r := require.New(t)
+
+	conn := Connection{
+		Provider: "gateway",
+		BaseURL:  "http://localhost:0",
+		// env key is not needed, as no request is made
+		EnvKey:  "SEAQ_TEST_UNSET_API_KEY",
+		Models:  []string{"gpt-4o", "meta-llama/llama-3.3-70b-instruct"},
+		Exclude: []string{"gpt-*"}, // filters only apply to discovered models
+		Overrides: []ModelOverride{
+			{Model: "meta-llama/llama-3.3-70b-instruct", Alias: "llama3"},
+		},
+	}
+
+	got, err := conn.List(context.Background())
+	r.NoError(err)
+	r.Equal([]string{"gpt-4o", "llama3"}, got)
+}
+
+func TestConnection_ResolveModel(t *testing.T) {
+	conn := Connection{
+		Overrides: []ModelOverride{
+			{Model: "meta-llama/llama-3.3-70b-instruct", Alias: "llama3"},
+			{Model: "openai/gpt-4o"},
+		},
+	}
+
+	testCases := []struct {
+		name  string
+		model string
+		want  string
+	}{
+		{name: "alias", model: "llama3", want: "meta-llama/llama-3.3-70b-instruct"},
+		{name: "upstream ID", model: "meta-llama/llama-3.3-70b-instruct", want: "meta-llama/llama-3.3-70b-instruct"},
+		{name: "no alias", model: "openai/gpt-4o", want: "openai/gpt-4o"},
+		{name: "unknown", model: "unknown", want: "unknown"},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			r.Equal(tt.want, conn.ResolveModel(tt.model))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package llm
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/env"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms/googleai"
+	"github.com/tmc/langchaingo/llms/ollama"
+	"github.com/tmc/langchaingo/llms/openai"
+)
+
+// DefaultEmbeddingModel is the embedding model used unless another one is configured.
+const DefaultEmbeddingModel = "openai/text-embedding-3-small"
+
+// NewEmbedder creates an embedder for an embedding model, given as provider/model,
+// e.g. "openai/text-embedding-3-small" or "ollama/nomic-embed-text".
+//
+// Embedding models are not listed like chat models, so any model of the
+// openai, google and ollama providers is accepted.
+func NewEmbedder(name string) (embeddings.Embedder, error) {
+	provider, model, ok := strings.Cut(name, "/")
+	if !ok || model == "" {
+		return nil, fmt.Errorf("invalid embedding model %q, expected provider/model", name)
+	}
+
+	var (
+		client embeddings.EmbedderClient
+		err    error
+	)
+
+	switch provider {
+	case "openai":
+		apiKey, keyErr := env.OpenAIAPIKey()
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		client, err = openai.New(
+			openai.WithEmbeddingModel(model),
+			openai.WithToken(apiKey),
+		)
+	case "google":
+		apiKey, keyErr := env.GeminiAPIKey()
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		client, err = googleai.New(
+			context.Background(),
+			googleai.WithAPIKey(apiKey),
+			googleai.WithDefaultEmbeddingModel(model),
+		)
+	case ollamaProvider:
+		client, err = ollama.New(
+			ollama.WithModel(model),
+			ollama.WithServerURL(env.OllamaHost()),
+		)
+	default:
+		return nil, fmt.Errorf("embeddings are not supported for provider %s, use openai, google or ollama", provider)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return embeddings.NewEmbedder(client)
+}
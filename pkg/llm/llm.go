@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 
 	"github.com/nt54hamnghi/seaq/pkg/env"
 	"github.com/tmc/langchaingo/llms"
@@ -91,10 +92,21 @@ func New(name string) (llms.Model, error) {
 		if err != nil {
 			return nil, err
 		}
-		return openai.New(
+
+		opts := []openai.Option{
 			openai.WithModel(model),
 			openai.WithToken(apiKey),
-		)
+		}
+
+		role, err := LookupSystemRole(name)
+		if err != nil {
+			return nil, err
+		}
+		if role == SystemRoleDeveloper {
+			opts = append(opts, openai.WithHTTPClient(newDeveloperRoleClient()))
+		}
+
+		return openai.New(opts...)
 	case "anthropic":
 		apiKey, err := env.AnthropicAPIKey()
 		if err != nil {
@@ -114,11 +126,18 @@ func New(name string) (llms.Model, error) {
 			googleai.WithAPIKey(apiKey),
 			googleai.WithDefaultModel(model),
 		)
-	case "ollama":
-		return ollama.New(
-			ollama.WithModel(model),
-			ollama.WithServerURL(env.OllamaHost()),
-		)
+	case ollamaProvider:
+		ollamaOpts, err := GetOllamaOptions()
+		if err != nil {
+			return nil, err
+		}
+		return ollama.New(append(
+			[]ollama.Option{
+				ollama.WithModel(model),
+				ollama.WithServerURL(env.OllamaHost()),
+			},
+			ollamaOpts.llmOptions()...,
+		)...)
 	default:
 		connections, err := GetConnectionSet()
 		if err != nil {
@@ -129,16 +148,7 @@ func New(name string) (llms.Model, error) {
 		if !ok {
 			return nil, fmt.Errorf("unexpected error: provider %s not found", provider)
 		}
-
-		apiKey, err := env.Get(conn.EnvKey)
-		if err != nil {
-			return nil, err
-		}
-		return openai.New(
-			openai.WithModel(model),
-			openai.WithToken(apiKey),
-			openai.WithBaseURL(conn.BaseURL),
-		)
+		return conn.NewModel(model)
 	}
 }
 
@@ -167,7 +177,7 @@ func CreateCompletion(
 		return errors.New("empty response from model")
 	}
 
-	_, err = io.WriteString(writer, resp.Choices[0].Content)
+	_, err = io.WriteString(writer, Content(resp))
 	if err != nil {
 		return err
 	}
@@ -212,7 +222,11 @@ func CreateStreamCompletion(
 	return nil
 }
 
-func PrepareMessages(modelName string, prompt string, content string, hint string) []llms.MessageContent {
+// PrepareMessages builds the messages for a pattern and its input.
+// How the pattern is delivered depends on the model's system role, see LookupSystemRole.
+// If strategy is not empty, it's layered on top of the pattern's prompt, see ComposePrompt.
+func PrepareMessages(modelName string, strategy string, prompt string, content string, hint string) ([]llms.MessageContent, error) {
+	prompt = ComposePrompt(strategy, prompt)
 	altContent := content
 
 	if hint != "" {
@@ -220,39 +234,24 @@ func PrepareMessages(modelName string, prompt string, content string, hint strin
 		altContent, _ = hintTemplate.Format(map[string]any{"content": content, "hint": hint})
 	}
 
-	return []llms.MessageContent{
-		{
-			Role:  lookupSystemRole(modelName),
-			Parts: []llms.ContentPart{llms.TextContent{Text: prompt}},
-		},
-		{
-			Role:  llms.ChatMessageTypeHuman,
-			Parts: []llms.ContentPart{llms.TextContent{Text: altContent}},
-		},
+	role, err := LookupSystemRole(modelName)
+	if err != nil {
+		return nil, err
 	}
-}
 
-// Exceptions where we need a different role for the system message.
-//
-// The role "system" has been deprecated in favor of "developer" for o1-family models provided by OpenAI.
-// https://platform.openai.com/docs/api-reference/chat/create
-//
-// However, langchaingo does not support "developer" role yet.
-// https://github.com/tmc/langchaingo/blob/0672790bb23a2c7e546a4a7aeffc9bef5bbd8c0b/llms/openai/openaillm.go#L60
-var specialRoles = map[string]llms.ChatMessageType{
-	"openai/o1":         llms.ChatMessageTypeGeneric,
-	"openai/o1-mini":    llms.ChatMessageTypeGeneric,
-	"openai/o1-preview": llms.ChatMessageTypeGeneric,
+	return applySystemRole(role, prompt, altContent), nil
 }
 
-// lookupSystemRole returns the appropriate system role based on the model name.
-func lookupSystemRole(modelName string) llms.ChatMessageType {
-	// default role is "system"
-	role := llms.ChatMessageTypeSystem
-
-	if special, ok := specialRoles[modelName]; ok {
-		role = special
+// ComposePrompt layers a strategy, such as chain-of-thought, on top of a pattern's prompt.
+// The strategy comes first, so that it frames how the pattern's instructions are carried out.
+func ComposePrompt(strategy string, prompt string) string {
+	strategy = strings.TrimSpace(strategy)
+	switch {
+	case strategy == "":
+		return prompt
+	case strings.TrimSpace(prompt) == "":
+		return strategy
+	default:
+		return strategy + "\n\n" + prompt
 	}
-
-	return role
 }
@@ -0,0 +1,49 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestComposePrompt(t *testing.T) {
+	testCases := []struct {
+		name     string
+		strategy string
+		prompt   string
+		want     string
+	}{
+		{
+			name:     "no strategy",
+			strategy: "",
+			prompt:   "Summarize the content.",
+			want:     "Summarize the content.",
+		},
+		{
+			name:     "blank strategy",
+			strategy: " \n",
+			prompt:   "Summarize the content.",
+			want:     "Summarize the content.",
+		},
+		{
+			name:     "strategy first",
+			strategy: "Think step by step.\n",
+			prompt:   "Summarize the content.",
+			want:     "Think step by step.\n\nSummarize the content.",
+		},
+		{
+			name:     "no prompt",
+			strategy: "Think step by step.",
+			prompt:   "",
+			want:     "Think step by step.",
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			r.Equal(tt.want, ComposePrompt(tt.strategy, tt.prompt))
+		})
+	}
+}
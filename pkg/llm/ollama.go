@@ -1,26 +1,138 @@
 package llm
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/nt54hamnghi/seaq/pkg/env"
 	"github.com/ollama/ollama/api"
+	"github.com/spf13/viper"
+	"github.com/tmc/langchaingo/llms/ollama"
 )
 
+const ollamaProvider = "ollama"
+
 var ollamaLister = SimpleModelLister{
-	ProviderName: "ollama",
+	ProviderName: ollamaProvider,
 	Lister:       listOllamaModels,
 }
 
-func listOllamaModels(ctx context.Context) ([]string, error) {
-	// create a new client
+// OllamaOptions holds Ollama-specific settings loaded from the "ollama" config key.
+type OllamaOptions struct {
+	// KeepAlive controls how long the model stays loaded after a request (e.g. "5m", "-1").
+	KeepAlive string `mapstructure:"keep_alive" yaml:"keep_alive"`
+	// NumCtx sets the size of the context window.
+	NumCtx int `mapstructure:"num_ctx" yaml:"num_ctx"`
+	// NumGPU sets the number of layers offloaded to the GPU(s).
+	// It's a pointer so that 0 (CPU-only) can be told apart from unset.
+	NumGPU *int `mapstructure:"num_gpu" yaml:"num_gpu"`
+	// AutoPull enables prompting to pull a model that is not available locally.
+	AutoPull bool `mapstructure:"auto_pull" yaml:"auto_pull"`
+}
+
+// GetOllamaOptions loads Ollama options from viper.
+func GetOllamaOptions() (OllamaOptions, error) {
+	var opts OllamaOptions
+	if err := viper.UnmarshalKey("ollama", &opts); err != nil {
+		return OllamaOptions{}, err
+	}
+	return opts, nil
+}
+
+// llmOptions converts the options to langchaingo ollama options.
+func (o OllamaOptions) llmOptions() []ollama.Option {
+	var opts []ollama.Option
+	if o.KeepAlive != "" {
+		opts = append(opts, ollama.WithKeepAlive(o.KeepAlive))
+	}
+	if o.NumCtx > 0 {
+		opts = append(opts, ollama.WithRunnerNumCtx(o.NumCtx))
+	}
+	if o.NumGPU != nil {
+		// langchaingo marks num_gpu as omitempty, so 0 (CPU-only) would be dropped.
+		// Set it on the request body instead.
+		opts = append(opts, ollama.WithHTTPClient(&http.Client{
+			Transport: &ollamaTransport{
+				base:    http.DefaultTransport,
+				options: map[string]any{"num_gpu": *o.NumGPU},
+			},
+		}))
+	}
+	return opts
+}
+
+// ollamaTransport is an http.RoundTripper that merges extra runner options
+// into the "options" object of Ollama chat and generate requests.
+type ollamaTransport struct {
+	base    http.RoundTripper
+	options map[string]any
+}
+
+func (t *ollamaTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || !isOllamaCompletionPath(req.URL.Path) {
+		return t.base.RoundTrip(req)
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	body, err := mergeOllamaOptions(raw, t.options)
+	if err != nil {
+		return nil, err
+	}
+
+	// clone the request as RoundTrip must not modify the original one
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.ContentLength = int64(len(body))
+
+	return t.base.RoundTrip(clone)
+}
+
+func isOllamaCompletionPath(path string) bool {
+	return strings.HasSuffix(path, "/api/chat") || strings.HasSuffix(path, "/api/generate")
+}
+
+// mergeOllamaOptions sets extra key-value pairs in the "options" object of a JSON request body.
+func mergeOllamaOptions(raw []byte, extra map[string]any) ([]byte, error) {
+	var body map[string]any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+
+	options, ok := body["options"].(map[string]any)
+	if !ok {
+		options = make(map[string]any, len(extra))
+	}
+	for k, v := range extra {
+		options[k] = v
+	}
+	body["options"] = options
+
+	return json.Marshal(body)
+}
+
+func newOllamaClient() (*api.Client, error) {
 	hostURL, err := url.ParseRequestURI(env.OllamaHost())
 	if err != nil {
 		return nil, err
 	}
-	client := api.NewClient(hostURL, http.DefaultClient)
+	return api.NewClient(hostURL, http.DefaultClient), nil
+}
+
+func listOllamaModels(ctx context.Context) ([]string, error) {
+	client, err := newOllamaClient()
+	if err != nil {
+		return nil, err
+	}
 
 	res, err := client.List(ctx)
 	if err != nil {
@@ -34,3 +146,34 @@ func listOllamaModels(ctx context.Context) ([]string, error) {
 
 	return models, nil
 }
+
+// IsOllamaModel reports whether a model identifier uses the ollama provider.
+func IsOllamaModel(id string) bool {
+	provider, model, ok := strings.Cut(id, "/")
+	return ok && clean(provider) == ollamaProvider && clean(model) != ""
+}
+
+// PullOllamaModel downloads a model to the local Ollama server
+// and registers it in the default registry once the pull completes.
+// fn is called with every progress update sent by the server.
+func PullOllamaModel(ctx context.Context, model string, fn api.PullProgressFunc) error {
+	client, err := newOllamaClient()
+	if err != nil {
+		return err
+	}
+
+	if err := client.Pull(ctx, &api.PullRequest{Model: model}, fn); err != nil {
+		return err
+	}
+
+	// the server lists tagged names (e.g. "llama3:latest"),
+	// register both so the model can be used with or without the tag
+	names := []string{model}
+	if !strings.Contains(model, ":") {
+		names = append(names, model+":latest")
+	}
+
+	initRegistry()
+	defaultRegistry.Add(ollamaProvider, names...)
+	return nil
+}
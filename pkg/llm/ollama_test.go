@@ -0,0 +1,73 @@
+package llm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsOllamaModel(t *testing.T) {
+	testCases := []struct {
+		name string
+		id   string
+		want bool
+	}{
+		{name: "ollama model", id: "ollama/llama3", want: true},
+		{name: "ollama model with tag", id: "ollama/llama3:8b", want: true},
+		{name: "other provider", id: "openai/gpt-4o", want: false},
+		{name: "missing model", id: "ollama/", want: false},
+		{name: "missing separator", id: "llama3", want: false},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			r.Equal(tt.want, IsOllamaModel(tt.id))
+		})
+	}
+}
+
+func Test_mergeOllamaOptions(t *testing.T) {
+	testCases := []struct {
+		name    string
+		raw     string
+		extra   map[string]any
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "no existing options",
+			raw:   `{"model":"llama3"}`,
+			extra: map[string]any{"num_gpu": 0},
+			want:  `{"model":"llama3","options":{"num_gpu":0}}`,
+		},
+		{
+			name:  "existing options",
+			raw:   `{"model":"llama3","options":{"num_ctx":8192}}`,
+			extra: map[string]any{"num_gpu": 0},
+			want:  `{"model":"llama3","options":{"num_ctx":8192,"num_gpu":0}}`,
+		},
+		{
+			name:    "invalid body",
+			raw:     `not json`,
+			extra:   map[string]any{"num_gpu": 0},
+			wantErr: true,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			got, err := mergeOllamaOptions([]byte(tt.raw), tt.extra)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+
+			r.NoError(err)
+			r.JSONEq(tt.want, string(got))
+		})
+	}
+}
@@ -215,6 +215,26 @@ func (r ModelRegistry) Register(provider string, models []string) error {
 	return nil
 }
 
+// Add adds models to a provider, creating the provider if it doesn't exist.
+// Unlike Register, it doesn't fail if the provider already exists.
+// Model names are trimmed of whitespace and empty names are ignored.
+func (r ModelRegistry) Add(provider string, models ...string) {
+	provider = clean(provider)
+	if provider == "" {
+		return
+	}
+
+	if _, ok := r[provider]; !ok {
+		r[provider] = set.New[string]()
+	}
+
+	for _, model := range models {
+		if m := clean(model); m != "" {
+			r[provider].Add(m)
+		}
+	}
+}
+
 // RegisterWith adds a new provider and its models to the registry using a ModelLister.
 //
 // Returns error if:
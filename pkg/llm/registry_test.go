@@ -402,3 +402,59 @@ func TestIter(t *testing.T) {
 		})
 	}
 }
+
+func TestAdd(t *testing.T) {
+	testCases := []struct {
+		name     string
+		registry ModelRegistry
+		provider string
+		models   []string
+		want     ModelRegistry
+	}{
+		{
+			name:     "new provider",
+			registry: ModelRegistry{},
+			provider: "ollama",
+			models:   []string{"llama3", "llama3:latest"},
+			want: ModelRegistry{
+				"ollama": set.New("llama3", "llama3:latest"),
+			},
+		},
+		{
+			name: "existing provider",
+			registry: ModelRegistry{
+				"ollama": set.New("smollm2:latest"),
+			},
+			provider: "ollama",
+			models:   []string{" llama3 "},
+			want: ModelRegistry{
+				"ollama": set.New("smollm2:latest", "llama3"),
+			},
+		},
+		{
+			name:     "empty provider",
+			registry: ModelRegistry{},
+			provider: " ",
+			models:   []string{"llama3"},
+			want:     ModelRegistry{},
+		},
+		{
+			name:     "empty model names are ignored",
+			registry: ModelRegistry{},
+			provider: "ollama",
+			models:   []string{"", " "},
+			want: ModelRegistry{
+				"ollama": set.New[string](),
+			},
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			tt.registry.Add(tt.provider, tt.models...)
+			r.Equal(tt.want, tt.registry)
+		})
+	}
+}
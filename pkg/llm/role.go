@@ -0,0 +1,203 @@
+package llm
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gobwas/glob"
+	"github.com/spf13/viper"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// SystemRole defines how a pattern is delivered to a model.
+type SystemRole string
+
+const (
+	// SystemRoleSystem sends the pattern as a system message.
+	SystemRoleSystem SystemRole = "system"
+	// SystemRoleDeveloper sends the pattern as a developer message.
+	// It's only supported by OpenAI-compatible providers.
+	SystemRoleDeveloper SystemRole = "developer"
+	// SystemRoleGeneric sends the pattern as a generic message,
+	// which most providers treat as a user message.
+	SystemRoleGeneric SystemRole = "generic"
+	// SystemRoleMerge merges the pattern into the first user message,
+	// for models that don't accept system messages at all.
+	SystemRoleMerge SystemRole = "merge"
+)
+
+// Validate checks if the role is one of the supported roles.
+// An empty role is valid and means the role is not set.
+func (r SystemRole) Validate() error {
+	switch r {
+	case "", SystemRoleSystem, SystemRoleDeveloper, SystemRoleGeneric, SystemRoleMerge:
+		return nil
+	default:
+		return fmt.Errorf("invalid system role %q, must be one of: system, developer, generic, merge", r)
+	}
+}
+
+// SystemRoleRule maps models matching a glob pattern to a system role.
+type SystemRoleRule struct {
+	// Model is a glob pattern matched against model IDs (e.g. "openai/o1*").
+	Model string     `mapstructure:"model" yaml:"model"`
+	Role  SystemRole `mapstructure:"role" yaml:"role"`
+}
+
+// defaultSystemRoleRules are used when no configuration matches a model.
+//
+// The role "system" has been deprecated in favor of "developer" for o1-family models provided by OpenAI.
+// https://platform.openai.com/docs/api-reference/chat/create
+var defaultSystemRoleRules = []SystemRoleRule{
+	{Model: "openai/o1", Role: SystemRoleGeneric},
+	{Model: "openai/o1-mini", Role: SystemRoleGeneric},
+	{Model: "openai/o1-preview", Role: SystemRoleGeneric},
+}
+
+// matchSystemRole returns the role of the first rule whose pattern matches the model.
+func matchSystemRole(rules []SystemRoleRule, modelName string) (SystemRole, error) {
+	for _, r := range rules {
+		g, err := glob.Compile(r.Model)
+		if err != nil {
+			return "", fmt.Errorf("invalid system role pattern %q: %w", r.Model, err)
+		}
+		if g.Match(modelName) {
+			return r.Role, nil
+		}
+	}
+	return "", nil
+}
+
+// LookupSystemRole returns how a pattern is delivered to a model.
+// It checks, in order:
+//  1. the system role override of the model in its connection
+//  2. the rules in the "system_roles" config key, first match wins
+//  3. the system role of the model's connection
+//  4. the built-in rules
+//
+// If none of them applies, SystemRoleSystem is returned.
+func LookupSystemRole(modelName string) (SystemRole, error) {
+	role, err := lookupSystemRole(modelName)
+	if err != nil {
+		return "", err
+	}
+	if err := role.Validate(); err != nil {
+		return "", fmt.Errorf("%s: %w", modelName, err)
+	}
+	return role, nil
+}
+
+func lookupSystemRole(modelName string) (SystemRole, error) {
+	var connRole SystemRole
+
+	if provider, model, ok := strings.Cut(modelName, "/"); ok {
+		connections, err := GetConnectionSet()
+		if err != nil {
+			return "", err
+		}
+		if conn, ok := connections.Get(provider); ok {
+			if o, ok := conn.override(conn.ResolveModel(model)); ok && o.SystemRole != "" {
+				return o.SystemRole, nil
+			}
+			connRole = conn.SystemRole
+		}
+	}
+
+	var rules []SystemRoleRule
+	if err := viper.UnmarshalKey("system_roles", &rules); err != nil {
+		return "", err
+	}
+	if role, err := matchSystemRole(rules, modelName); err != nil || role != "" {
+		return role, err
+	}
+
+	if connRole != "" {
+		return connRole, nil
+	}
+
+	if role, _ := matchSystemRole(defaultSystemRoleRules, modelName); role != "" {
+		return role, nil
+	}
+
+	return SystemRoleSystem, nil
+}
+
+// applySystemRole builds the messages for a pattern and a user message
+// according to the system role.
+func applySystemRole(role SystemRole, prompt string, content string) []llms.MessageContent {
+	if role == SystemRoleMerge {
+		return []llms.MessageContent{
+			llms.TextParts(llms.ChatMessageTypeHuman, prompt+"\n\n"+content),
+		}
+	}
+
+	// developer messages are sent as system messages
+	// and rewritten by developerRoleTransport
+	msgType := llms.ChatMessageTypeSystem
+	if role == SystemRoleGeneric {
+		msgType = llms.ChatMessageTypeGeneric
+	}
+
+	return []llms.MessageContent{
+		llms.TextParts(msgType, prompt),
+		llms.TextParts(llms.ChatMessageTypeHuman, content),
+	}
+}
+
+// developerRoleTransport is an http.RoundTripper that rewrites system messages
+// to developer messages in OpenAI-compatible chat completion requests.
+// langchaingo doesn't support the developer role.
+type developerRoleTransport struct {
+	base http.RoundTripper
+}
+
+func newDeveloperRoleClient() *http.Client {
+	return &http.Client{
+		Transport: &developerRoleTransport{base: http.DefaultTransport},
+	}
+}
+
+func (t *developerRoleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body == nil || !strings.HasSuffix(req.URL.Path, "/chat/completions") {
+		return t.base.RoundTrip(req)
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	body, err := rewriteSystemRole(raw, string(SystemRoleDeveloper))
+	if err != nil {
+		return nil, err
+	}
+
+	// clone the request as RoundTrip must not modify the original one
+	clone := req.Clone(req.Context())
+	clone.Body = io.NopCloser(bytes.NewReader(body))
+	clone.ContentLength = int64(len(body))
+
+	return t.base.RoundTrip(clone)
+}
+
+// rewriteSystemRole replaces the role of system messages in a JSON request body.
+func rewriteSystemRole(raw []byte, role string) ([]byte, error) {
+	var body map[string]any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+
+	msgs, _ := body["messages"].([]any)
+	for _, m := range msgs {
+		if msg, ok := m.(map[string]any); ok && msg["role"] == "system" {
+			msg["role"] = role
+		}
+	}
+
+	return json.Marshal(body)
+}
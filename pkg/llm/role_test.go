@@ -0,0 +1,174 @@
+package llm
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+func TestSystemRole_Validate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		role    SystemRole
+		wantErr bool
+	}{
+		{name: "empty", role: "", wantErr: false},
+		{name: "system", role: SystemRoleSystem, wantErr: false},
+		{name: "developer", role: SystemRoleDeveloper, wantErr: false},
+		{name: "generic", role: SystemRoleGeneric, wantErr: false},
+		{name: "merge", role: SystemRoleMerge, wantErr: false},
+		{name: "invalid", role: "assistant", wantErr: true},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			err := tt.role.Validate()
+			if tt.wantErr {
+				r.Error(err)
+			} else {
+				r.NoError(err)
+			}
+		})
+	}
+}
+
+func TestLookupSystemRole(t *testing.T) {
+	testCases := []struct {
+		name      string
+		modelName string
+		want      SystemRole
+	}{
+		{name: "default", modelName: "anthropic/claude-sonnet-4-20250514", want: SystemRoleSystem},
+		{name: "built-in rule", modelName: "openai/o1-mini", want: SystemRoleGeneric},
+		{name: "config rule", modelName: "openai/o3-mini", want: SystemRoleDeveloper},
+		{name: "config rule over connection", modelName: "local/gemma-3-27b", want: SystemRoleMerge},
+		{name: "connection", modelName: "local/llama-3.3-70b", want: SystemRoleGeneric},
+		{name: "connection model override", modelName: "local/qwen", want: SystemRoleSystem},
+		{name: "override of a mixed-case ID", modelName: "local/Mistral-Small-3.1", want: SystemRoleMerge},
+	}
+
+	viper.Reset()
+	defer viper.Reset()
+
+	// read from YAML, as viper lowercases the keys of maps read from a config file
+	viper.SetConfigType("yaml")
+	err := viper.ReadConfig(strings.NewReader(`
+system_roles:
+  - model: openai/o3*
+    role: developer
+  - model: "*/gemma*"
+    role: merge
+  # shadowed by the override of local/qwen
+  - model: local/qwen
+    role: merge
+connections:
+  - provider: local
+    base_url: http://localhost:8080/v1
+    system_role: generic
+    overrides:
+      - model: Qwen/Qwen3-32B
+        alias: qwen
+        system_role: system
+      - model: Mistral-Small-3.1
+        system_role: merge
+`))
+	require.NoError(t, err)
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			got, err := LookupSystemRole(tt.modelName)
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestLookupSystemRole_Invalid(t *testing.T) {
+	testCases := []struct {
+		name  string
+		rules []map[string]any
+	}{
+		{name: "invalid role", rules: []map[string]any{{"model": "*", "role": "assistant"}}},
+		{name: "invalid pattern", rules: []map[string]any{{"model": "[", "role": "system"}}},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			viper.Reset()
+			defer viper.Reset()
+
+			viper.Set("system_roles", tt.rules)
+			_, err := LookupSystemRole("openai/gpt-4o")
+			r.Error(err)
+		})
+	}
+}
+
+func Test_applySystemRole(t *testing.T) {
+	testCases := []struct {
+		name string
+		role SystemRole
+		want []llms.MessageContent
+	}{
+		{
+			name: "system",
+			role: SystemRoleSystem,
+			want: []llms.MessageContent{
+				llms.TextParts(llms.ChatMessageTypeSystem, "prompt"),
+				llms.TextParts(llms.ChatMessageTypeHuman, "content"),
+			},
+		},
+		{
+			name: "developer",
+			role: SystemRoleDeveloper,
+			want: []llms.MessageContent{
+				llms.TextParts(llms.ChatMessageTypeSystem, "prompt"),
+				llms.TextParts(llms.ChatMessageTypeHuman, "content"),
+			},
+		},
+		{
+			name: "generic",
+			role: SystemRoleGeneric,
+			want: []llms.MessageContent{
+				llms.TextParts(llms.ChatMessageTypeGeneric, "prompt"),
+				llms.TextParts(llms.ChatMessageTypeHuman, "content"),
+			},
+		},
+		{
+			name: "merge",
+			role: SystemRoleMerge,
+			want: []llms.MessageContent{
+				llms.TextParts(llms.ChatMessageTypeHuman, "prompt\n\ncontent"),
+			},
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			r.Equal(tt.want, applySystemRole(tt.role, "prompt", "content"))
+		})
+	}
+}
+
+func Test_rewriteSystemRole(t *testing.T) {
+	r := require.New(t)
+
+	raw := []byte(`{"model":"o3","messages":[{"role":"system","content":"prompt"},{"role":"user","content":"hi"}]}`)
+	got, err := rewriteSystemRole(raw, "developer")
+	r.NoError(err)
+	r.JSONEq(`{"model":"o3","messages":[{"role":"developer","content":"prompt"},{"role":"user","content":"hi"}]}`, string(got))
+
+	_, err = rewriteSystemRole([]byte("not json"), "developer")
+	r.Error(err)
+}
@@ -0,0 +1,176 @@
+package llm
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/tmc/langchaingo/llms"
+	"github.com/tmc/langchaingo/llms/anthropic"
+)
+
+const (
+	// DefaultThinkingBudget is the default number of tokens a model may spend on reasoning.
+	DefaultThinkingBudget = 4096
+
+	// answerTokens is the number of tokens reserved for the answer on top of the thinking budget.
+	// Anthropic counts reasoning towards max tokens, so the limit must be larger than the budget.
+	answerTokens = 8192
+
+	// anthropicThinkingTemperature is the only temperature Anthropic allows with extended thinking.
+	anthropicThinkingTemperature = 1
+)
+
+// ReasoningFunc is called with chunks of reasoning content.
+type ReasoningFunc func(ctx context.Context, chunk []byte) error
+
+// ThinkingOptions returns call options that enable the reasoning mode of models
+// that support it, with the given token budget.
+// If budget is not positive, DefaultThinkingBudget is used.
+func ThinkingOptions(budget int) []llms.CallOption {
+	if budget <= 0 {
+		budget = DefaultThinkingBudget
+	}
+
+	return []llms.CallOption{
+		llms.WithThinkingMode(llms.ThinkingModeMedium),
+		llms.WithThinkingBudget(budget),
+		llms.WithReturnThinking(true),
+		llms.WithStreamThinking(true),
+	}
+}
+
+// anthropicThinkingOptions returns the call options Anthropic's extended thinking requires
+// on top of ThinkingOptions: max tokens larger than the budget, and a temperature of 1.
+// The requested temperature is overridden, see WarnThinkingTemperature.
+func anthropicThinkingOptions(budget int) []llms.CallOption {
+	if budget <= 0 {
+		budget = DefaultThinkingBudget
+	}
+
+	return []llms.CallOption{
+		llms.WithMaxTokens(budget + answerTokens),
+		llms.WithTemperature(anthropicThinkingTemperature),
+	}
+}
+
+// WarnThinkingTemperature logs that a temperature set explicitly, by a flag or a pattern,
+// is ignored because the model's reasoning mode requires another one.
+// Only Anthropic's extended thinking does, with a temperature of 1.
+func WarnThinkingTemperature(modelName string, temperature float64) {
+	provider, _, _ := strings.Cut(modelName, "/")
+	if provider == "anthropic" && temperature != anthropicThinkingTemperature {
+		log.Warn("extended thinking requires a temperature of 1, ignoring the requested temperature",
+			"model", modelName, "temperature", temperature)
+	}
+}
+
+// WithThinking wraps a model so that every call enables its reasoning mode
+// with the given token budget and passes reasoning content to fn.
+//
+// In streaming mode, reasoning is passed to fn as it's generated.
+// Otherwise, or if the provider only returns reasoning with the response,
+// fn is called once with the full reasoning content after the call.
+func WithThinking(model llms.Model, budget int, fn ReasoningFunc) llms.Model {
+	_, isAnthropic := model.(*anthropic.LLM)
+	return &thinkingModel{
+		Model:     model,
+		budget:    budget,
+		fn:        fn,
+		anthropic: isAnthropic,
+	}
+}
+
+type thinkingModel struct {
+	llms.Model
+	budget int
+	fn     ReasoningFunc
+	// anthropic reports whether the model is an Anthropic model,
+	// whose extended thinking constrains max tokens and temperature
+	anthropic bool
+}
+
+// GenerateContent implements the llms.Model interface.
+func (m *thinkingModel) GenerateContent(
+	ctx context.Context,
+	msgs []llms.MessageContent,
+	opts ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	extOpts := append([]llms.CallOption{}, opts...)
+	extOpts = append(extOpts, ThinkingOptions(m.budget)...)
+	if m.anthropic {
+		extOpts = append(extOpts, anthropicThinkingOptions(m.budget)...)
+	}
+
+	streamed := false
+	if isStreaming(opts) {
+		// only set in streaming mode, as some clients switch to streaming
+		// when this is set and fail to parse non-streamed responses
+		reasoningFunc := func(ctx context.Context, reasoningChunk, _ []byte) error {
+			// OpenAI-compatible clients also pass content chunks here, skip them
+			if len(reasoningChunk) == 0 {
+				return nil
+			}
+			streamed = true
+			return m.fn(ctx, reasoningChunk)
+		}
+		extOpts = append(extOpts, llms.WithStreamingReasoningFunc(reasoningFunc))
+	}
+
+	resp, err := m.Model.GenerateContent(ctx, msgs, extOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if !streamed {
+		if thinking := ThinkingContent(resp); thinking != "" {
+			if err := m.fn(ctx, []byte(thinking)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return resp, nil
+}
+
+// Call implements the llms.Model interface.
+func (m *thinkingModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+// SupportsReasoning reports whether the wrapped model supports reasoning.
+func (m *thinkingModel) SupportsReasoning() bool {
+	return llms.SupportsReasoningModel(m.Model)
+}
+
+func isStreaming(opts []llms.CallOption) bool {
+	var o llms.CallOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.StreamingFunc != nil
+}
+
+// ThinkingContent returns the reasoning content of a response, if any.
+func ThinkingContent(resp *llms.ContentResponse) string {
+	var sb strings.Builder
+	for _, c := range resp.Choices {
+		thinking := c.ReasoningContent
+		if thinking == "" {
+			thinking, _ = c.GenerationInfo["ThinkingContent"].(string)
+		}
+		sb.WriteString(thinking)
+	}
+	return sb.String()
+}
+
+// Content returns the text content of a response.
+// Providers like Anthropic return reasoning and text as separate choices,
+// so the content of all choices is joined.
+func Content(resp *llms.ContentResponse) string {
+	var sb strings.Builder
+	for _, c := range resp.Choices {
+		sb.WriteString(c.Content)
+	}
+	return sb.String()
+}
@@ -0,0 +1,195 @@
+package llm
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// fakeReasoningModel returns a fixed response and streams its reasoning
+// through the reasoning function, if any.
+type fakeReasoningModel struct {
+	resp *llms.ContentResponse
+	// opts captures the options of the last call
+	opts llms.CallOptions
+}
+
+func (m *fakeReasoningModel) GenerateContent(
+	ctx context.Context,
+	_ []llms.MessageContent,
+	opts ...llms.CallOption,
+) (*llms.ContentResponse, error) {
+	m.opts = llms.CallOptions{}
+	for _, opt := range opts {
+		opt(&m.opts)
+	}
+
+	if m.opts.StreamingReasoningFunc != nil {
+		for _, c := range m.resp.Choices {
+			if err := m.opts.StreamingReasoningFunc(ctx, []byte(c.ReasoningContent), []byte(c.Content)); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return m.resp, nil
+}
+
+func (m *fakeReasoningModel) Call(ctx context.Context, prompt string, opts ...llms.CallOption) (string, error) {
+	return llms.GenerateFromSinglePrompt(ctx, m, prompt, opts...)
+}
+
+func TestContent(t *testing.T) {
+	r := require.New(t)
+
+	resp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{
+			{Content: "", GenerationInfo: map[string]any{"ThinkingContent": "let me think"}},
+			{Content: "the answer"},
+		},
+	}
+
+	r.Equal("the answer", Content(resp))
+}
+
+func TestThinkingContent(t *testing.T) {
+	testCases := []struct {
+		name   string
+		choice *llms.ContentChoice
+		want   string
+	}{
+		{
+			name:   "reasoning content",
+			choice: &llms.ContentChoice{ReasoningContent: "reasoning"},
+			want:   "reasoning",
+		},
+		{
+			name:   "generation info",
+			choice: &llms.ContentChoice{GenerationInfo: map[string]any{"ThinkingContent": "thinking"}},
+			want:   "thinking",
+		},
+		{
+			name:   "none",
+			choice: &llms.ContentChoice{Content: "answer"},
+			want:   "",
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			resp := &llms.ContentResponse{Choices: []*llms.ContentChoice{tt.choice}}
+			r.Equal(tt.want, ThinkingContent(resp))
+		})
+	}
+}
+
+func TestThinkingOptions(t *testing.T) {
+	testCases := []struct {
+		name       string
+		budget     int
+		wantBudget int
+	}{
+		{name: "custom budget", budget: 2048, wantBudget: 2048},
+		{name: "default budget", budget: 0, wantBudget: DefaultThinkingBudget},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			var opts llms.CallOptions
+			for _, opt := range ThinkingOptions(tt.budget) {
+				opt(&opts)
+			}
+
+			config := llms.GetThinkingConfig(&opts)
+			r.NotNil(config)
+			r.Equal(tt.wantBudget, config.BudgetTokens)
+			// max tokens and temperature are left to the caller, except for Anthropic
+			r.Zero(opts.MaxTokens)
+			r.Zero(opts.Temperature)
+		})
+	}
+}
+
+func TestWithThinking_Temperature(t *testing.T) {
+	testCases := []struct {
+		name          string
+		anthropic     bool
+		wantTemp      float64
+		wantMaxTokens int
+	}{
+		{name: "anthropic", anthropic: true, wantTemp: 1, wantMaxTokens: 2048 + answerTokens},
+		{name: "other providers", anthropic: false, wantTemp: 0.3, wantMaxTokens: 0},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			fake := &fakeReasoningModel{resp: &llms.ContentResponse{Choices: []*llms.ContentChoice{{Content: "answer"}}}}
+			model := &thinkingModel{
+				Model:     fake,
+				budget:    2048,
+				fn:        func(context.Context, []byte) error { return nil },
+				anthropic: tt.anthropic,
+			}
+
+			_, err := model.GenerateContent(context.Background(), nil, llms.WithTemperature(0.3))
+			r.NoError(err)
+			r.Equal(tt.wantTemp, fake.opts.Temperature)
+			r.Equal(tt.wantMaxTokens, fake.opts.MaxTokens)
+		})
+	}
+}
+
+func TestWithThinking(t *testing.T) {
+	resp := &llms.ContentResponse{
+		Choices: []*llms.ContentChoice{
+			{ReasoningContent: "reasoning", Content: "answer"},
+		},
+	}
+
+	testCases := []struct {
+		name string
+		opts []llms.CallOption
+	}{
+		{
+			name: "stream",
+			opts: []llms.CallOption{
+				llms.WithStreamingFunc(func(context.Context, []byte) error { return nil }),
+			},
+		},
+		{
+			name: "no stream",
+			opts: nil,
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			fake := &fakeReasoningModel{resp: resp}
+
+			var thinking string
+			model := WithThinking(fake, 2048, func(_ context.Context, chunk []byte) error {
+				thinking += string(chunk)
+				return nil
+			})
+
+			got, err := model.GenerateContent(context.Background(), nil, tt.opts...)
+			r.NoError(err)
+			r.Equal("answer", Content(got))
+			r.Equal("reasoning", thinking)
+			r.NotNil(llms.GetThinkingConfig(&fake.opts))
+
+			// the reasoning function must only be set in streaming mode
+			r.Equal(tt.opts != nil, fake.opts.StreamingReasoningFunc != nil)
+		})
+	}
+}
@@ -50,6 +50,30 @@ func MarshalAndHash(v any) ([]byte, error) {
 	return h.Sum(nil), nil
 }
 
+// HashFile returns the cache key of a loader of a local file, from the file's absolute path,
+// size and modification time, so that an edited file isn't read from the cache,
+// and from the loader's options, such as its type and the selected pages.
+func HashFile(file string, options map[string]any) ([]byte, error) {
+	path, err := filepath.Abs(file)
+	if err != nil {
+		return nil, err
+	}
+	info, err := fs.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{
+		"path":    path,
+		"size":    info.Size(),
+		"modTime": info.ModTime().UnixNano(),
+	}
+	for k, v := range options {
+		data[k] = v
+	}
+	return MarshalAndHash(data)
+}
+
 // New creates a new CacheStorage.
 func New(l CacheableLoader) (*Storage, error) {
 	dir, _, err := config.AppConfig()
@@ -0,0 +1,279 @@
+// Package epub loads EPUB ebooks as Markdown, one document per chapter.
+package epub
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/util/ranges"
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// mimetype is the content of the mimetype file of EPUB files.
+const mimetype = "application/epub+zip"
+
+// IsEPUB reports whether an archive is an EPUB file.
+func IsEPUB(zr *zip.Reader) bool {
+	data, err := ziputil.ReadFile(zr, "mimetype")
+	return err == nil && strings.TrimSpace(string(data)) == mimetype
+}
+
+type Loader struct {
+	file     string
+	chapters ranges.Ranges
+	title    string
+}
+
+type Option func(*Loader)
+
+func WithFile(file string) Option {
+	return func(o *Loader) {
+		o.file = file
+	}
+}
+
+// WithChapters selects chapters by number, starting at 1.
+func WithChapters(chapters ranges.Ranges) Option {
+	return func(o *Loader) {
+		o.chapters = chapters
+	}
+}
+
+// WithTitle selects the chapters whose title contains a text, ignoring case.
+func WithTitle(title string) Option {
+	return func(o *Loader) {
+		o.title = title
+	}
+}
+
+func NewEPUBLoader(opts ...Option) *Loader {
+	loader := &Loader{}
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
+}
+
+// Load loads from a source and returns documents, one per selected chapter.
+func (l Loader) Load(ctx context.Context) ([]schema.Document, error) {
+	rc, err := zip.OpenReader(l.file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", l.file, err)
+	}
+	defer rc.Close()
+
+	book, err := readBook(ctx, &rc.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", l.file, err)
+	}
+
+	total := len(book.chapters)
+	if total == 0 {
+		return nil, fmt.Errorf("no text found in %s", l.file)
+	}
+	if n, ok := l.chapters.Exceeds(total); ok {
+		return nil, fmt.Errorf("chapter %d is out of range, the book has %d chapters", n, total)
+	}
+
+	docs := make([]schema.Document, 0, total)
+	for i, c := range book.chapters {
+		num := i + 1
+		if !l.chapters.Contains(num) || !strings.Contains(strings.ToLower(c.title), strings.ToLower(l.title)) {
+			continue
+		}
+
+		metadata := map[string]any{
+			"file":          filepath.Base(l.file),
+			"chapter":       num,
+			"totalChapters": total,
+		}
+		if c.title != "" {
+			metadata["title"] = c.title
+		}
+		if book.title != "" {
+			metadata["book"] = book.title
+		}
+		docs = append(docs, schema.Document{PageContent: c.text, Metadata: metadata})
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no chapter of %s matches %q", l.file, l.title)
+	}
+	return docs, nil
+}
+
+// LoadAndSplit loads from a source and splits the documents using a text splitter.
+func (l Loader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (l Loader) Hash() ([]byte, error) {
+	return cache.HashFile(l.file, map[string]any{
+		"type":     "epub",
+		"chapters": l.chapters.String(),
+		"title":    l.title,
+	})
+}
+
+func (l Loader) Type() string {
+	return "epub"
+}
+
+// book is the content of an ebook.
+type book struct {
+	title    string
+	chapters []chapter
+}
+
+type chapter struct {
+	title string
+	text  string
+}
+
+// packageDocument is the package document of an EPUB, which lists its content.
+type packageDocument struct {
+	Title    string         `xml:"metadata>title"`
+	Manifest []manifestItem `xml:"manifest>item"`
+	Spine    struct {
+		Toc   string `xml:"toc,attr"`
+		Items []struct {
+			IDRef  string `xml:"idref,attr"`
+			Linear string `xml:"linear,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+// manifestItem is a file of an EPUB, with its path relative to the package document.
+type manifestItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+// readBook reads the chapters of an ebook, in reading order.
+//
+// Content documents listed in the table of contents start a chapter, and those that aren't,
+// such as the continuation of a chapter split in several files, are part of the previous chapter.
+// Without a table of contents, each content document is a chapter.
+func readBook(ctx context.Context, zr *zip.Reader) (book, error) {
+	opfPath, err := rootFile(zr)
+	if err != nil {
+		return book{}, err
+	}
+
+	var pkg packageDocument
+	if err := ziputil.DecodeXML(zr, opfPath, &pkg, htmlEntities); err != nil {
+		return book{}, err
+	}
+	dir := path.Dir(opfPath)
+
+	titles, err := tocTitles(zr, dir, pkg)
+	if err != nil {
+		return book{}, err
+	}
+
+	b := book{title: strings.TrimSpace(pkg.Title)}
+	for _, ref := range pkg.Spine.Items {
+		if err := ctx.Err(); err != nil {
+			return book{}, err
+		}
+		// non-linear content, such as notes, is out of the reading order
+		if ref.Linear == "no" {
+			continue
+		}
+
+		i := slices.IndexFunc(pkg.Manifest, func(item manifestItem) bool {
+			return item.ID == ref.IDRef
+		})
+		if i == -1 {
+			continue
+		}
+		item := pkg.Manifest[i]
+		if item.MediaType != "application/xhtml+xml" && item.MediaType != "text/html" {
+			continue
+		}
+
+		name := resolve(dir, item.Href)
+		data, err := ziputil.ReadFile(zr, name)
+		if err != nil {
+			return book{}, err
+		}
+		text, heading, err := toMarkdown(data)
+		if err != nil {
+			return book{}, fmt.Errorf("converting %s: %w", name, err)
+		}
+
+		title, listed := titles[name]
+		if !listed && len(titles) > 0 && len(b.chapters) > 0 {
+			last := &b.chapters[len(b.chapters)-1]
+			if text != "" {
+				last.text = strings.TrimSpace(last.text + "\n\n" + text)
+			}
+			continue
+		}
+
+		if text == "" {
+			continue
+		}
+		if title == "" {
+			title = heading
+		}
+		b.chapters = append(b.chapters, chapter{title: title, text: text})
+	}
+
+	return b, nil
+}
+
+// rootFile returns the path of the package document, from META-INF/container.xml.
+func rootFile(zr *zip.Reader) (string, error) {
+	var container struct {
+		RootFiles []struct {
+			FullPath  string `xml:"full-path,attr"`
+			MediaType string `xml:"media-type,attr"`
+		} `xml:"rootfiles>rootfile"`
+	}
+	if err := ziputil.DecodeXML(zr, "META-INF/container.xml", &container, htmlEntities); err != nil {
+		return "", err
+	}
+
+	for _, rf := range container.RootFiles {
+		if rf.MediaType == "" || rf.MediaType == "application/oebps-package+xml" {
+			return rf.FullPath, nil
+		}
+	}
+	return "", errors.New("no package document found")
+}
+
+// resolve returns the path in the archive of a reference relative to a directory,
+// without its fragment.
+func resolve(dir string, href string) string {
+	href, _, _ = strings.Cut(href, "#")
+	if unescaped, err := url.PathUnescape(href); err == nil {
+		href = unescaped
+	}
+	return path.Join(dir, href)
+}
+
+// htmlEntities makes an XML decoder accept HTML entities, such as &nbsp;,
+// which XHTML files often use.
+func htmlEntities(d *xml.Decoder) {
+	d.Strict = false
+	d.Entity = xml.HTMLEntity
+}
@@ -0,0 +1,186 @@
+package epub
+
+import (
+	"archive/zip"
+	"context"
+	"testing"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/ranges"
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil/ziptest"
+	"github.com/stretchr/testify/require"
+)
+
+const container = `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+	<rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`
+
+func xhtml(title, body string) string {
+	return `<?xml version="1.0" encoding="utf-8"?>
+<html xmlns="http://www.w3.org/1999/xhtml"><head><title>` + title + `</title></head><body>` + body + `</body></html>`
+}
+
+// writeEPUB3 writes a book with a cover, two chapters, the second split in two files, and notes.
+func writeEPUB3(t *testing.T) string {
+	t.Helper()
+
+	opf := `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="3.0">
+	<metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>The Book</dc:title></metadata>
+	<manifest>
+		<item id="nav" href="nav.xhtml" media-type="application/xhtml+xml" properties="nav"/>
+		<item id="cover" href="cover.xhtml" media-type="application/xhtml+xml"/>
+		<item id="c1" href="text/chapter%201.xhtml" media-type="application/xhtml+xml"/>
+		<item id="c2" href="text/chapter2.xhtml" media-type="application/xhtml+xml"/>
+		<item id="c2b" href="text/chapter2_split.xhtml" media-type="application/xhtml+xml"/>
+		<item id="notes" href="text/notes.xhtml" media-type="application/xhtml+xml"/>
+		<item id="img" href="images/cover.jpg" media-type="image/jpeg"/>
+	</manifest>
+	<spine>
+		<itemref idref="cover"/>
+		<itemref idref="c1"/>
+		<itemref idref="c2"/>
+		<itemref idref="c2b"/>
+		<itemref idref="notes" linear="no"/>
+	</spine>
+</package>`
+	nav := xhtml("Contents", `<nav epub:type="landmarks"><ol><li><a href="cover.xhtml">Cover</a></li></ol></nav>
+		<nav epub:type="toc"><ol>
+			<li><a href="text/chapter%201.xhtml">Chapter  One</a>
+				<ol><li><a href="text/chapter%201.xhtml#s1">A section</a></li></ol></li>
+			<li><a href="text/chapter2.xhtml#top">Chapter Two</a></li>
+		</ol></nav>`)
+
+	return ziptest.Write(t, "book.epub",
+		[2]string{"mimetype", mimetype},
+		[2]string{"META-INF/container.xml", container},
+		[2]string{"OEBPS/content.opf", opf},
+		[2]string{"OEBPS/nav.xhtml", nav},
+		[2]string{"OEBPS/cover.xhtml", xhtml("Cover", `<h1>The Book</h1><img src="images/cover.jpg" alt="cover"/>`)},
+		[2]string{"OEBPS/text/chapter 1.xhtml", xhtml("One", `<h1>One</h1><p>It was a <em>dark</em> night.</p>`)},
+		[2]string{"OEBPS/text/chapter2.xhtml", xhtml("Two", `<h1>Two</h1><p>The day after.</p>`)},
+		[2]string{"OEBPS/text/chapter2_split.xhtml", xhtml("Two", `<p>And the night after&nbsp;that.</p>`)},
+		[2]string{"OEBPS/text/notes.xhtml", xhtml("Notes", `<p>A note.</p>`)},
+	)
+}
+
+func TestLoad(t *testing.T) {
+	r := require.New(t)
+
+	file := writeEPUB3(t)
+
+	docs, err := NewEPUBLoader(WithFile(file)).Load(context.Background())
+	r.NoError(err)
+	r.Len(docs, 3)
+
+	// the cover is not in the table of contents, it's titled by its heading
+	r.Equal("# The Book", docs[0].PageContent)
+	r.Equal("The Book", docs[0].Metadata["title"])
+
+	r.Equal("# One\n\nIt was a *dark* night.", docs[1].PageContent)
+	r.Equal(map[string]any{
+		"file":          "book.epub",
+		"book":          "The Book",
+		"chapter":       2,
+		"totalChapters": 3,
+		"title":         "Chapter One",
+	}, docs[1].Metadata)
+
+	// the continuation of a chapter is part of it
+	r.Equal("# Two\n\nThe day after.\n\nAnd the night after that.", docs[2].PageContent)
+	r.Equal("Chapter Two", docs[2].Metadata["title"])
+}
+
+func TestLoad_Select(t *testing.T) {
+	file := writeEPUB3(t)
+
+	testCases := []struct {
+		name     string
+		opts     []Option
+		want     []int
+		errorMsg string
+	}{
+		{name: "number", opts: []Option{WithChapters(ranges.Ranges{{Start: 2, End: 2}})}, want: []int{2}},
+		{name: "range", opts: []Option{WithChapters(ranges.Ranges{{Start: 2}})}, want: []int{2, 3}},
+		{name: "title", opts: []Option{WithTitle("chapter")}, want: []int{2, 3}},
+		{name: "title ignoring case", opts: []Option{WithTitle("TWO")}, want: []int{3}},
+		{name: "no match", opts: []Option{WithTitle("epilogue")}, errorMsg: "no chapter"},
+		{name: "out of range", opts: []Option{WithChapters(ranges.Ranges{{Start: 4, End: 4}})}, errorMsg: "chapter 4 is out of range"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			docs, err := NewEPUBLoader(append(tt.opts, WithFile(file))...).Load(context.Background())
+			if tt.errorMsg != "" {
+				r.ErrorContains(err, tt.errorMsg)
+				return
+			}
+			r.NoError(err)
+
+			got := make([]int, len(docs))
+			for i, d := range docs {
+				got[i] = d.Metadata["chapter"].(int)
+			}
+			r.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestLoad_NCX(t *testing.T) {
+	r := require.New(t)
+
+	opf := `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0">
+	<metadata xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Old Book</dc:title></metadata>
+	<manifest>
+		<item id="ncx" href="toc.ncx" media-type="application/x-dtbncx+xml"/>
+		<item id="c1" href="c1.html" media-type="application/xhtml+xml"/>
+		<item id="c2" href="c2.html" media-type="application/xhtml+xml"/>
+	</manifest>
+	<spine toc="ncx"><itemref idref="c1"/><itemref idref="c2"/></spine>
+</package>`
+	ncx := `<?xml version="1.0"?>
+<ncx xmlns="http://www.daisy.org/z3986/2005/ncx/" version="2005-1"><navMap>
+	<navPoint id="p1"><navLabel><text>Part I</text></navLabel><content src="c1.html"/>
+		<navPoint id="p2"><navLabel><text>Beginning</text></navLabel><content src="c1.html#b"/></navPoint>
+		<navPoint id="p3"><navLabel><text>Ending</text></navLabel><content src="c2.html"/></navPoint>
+	</navPoint>
+</navMap></ncx>`
+
+	file := ziptest.Write(t, "book.epub",
+		[2]string{"mimetype", mimetype},
+		[2]string{"META-INF/container.xml", container},
+		[2]string{"OEBPS/content.opf", opf},
+		[2]string{"OEBPS/toc.ncx", ncx},
+		[2]string{"OEBPS/c1.html", xhtml("c1", `<p>First.</p>`)},
+		[2]string{"OEBPS/c2.html", xhtml("c2", `<p>Last.</p>`)},
+	)
+
+	docs, err := NewEPUBLoader(WithFile(file)).Load(context.Background())
+	r.NoError(err)
+	r.Len(docs, 2)
+	r.Equal("Part I", docs[0].Metadata["title"])
+	r.Equal("First.", docs[0].PageContent)
+	r.Equal("Ending", docs[1].Metadata["title"])
+	r.Equal("Old Book", docs[1].Metadata["book"])
+}
+
+func TestIsEPUB(t *testing.T) {
+	r := require.New(t)
+
+	for _, tt := range []struct {
+		files [][2]string
+		want  bool
+	}{
+		{files: [][2]string{{"mimetype", mimetype}}, want: true},
+		{files: [][2]string{{"mimetype", "application/zip"}}, want: false},
+		{files: [][2]string{{"readme.txt", "hello"}}, want: false},
+	} {
+		zr, err := zip.OpenReader(ziptest.Write(t, "book.epub", tt.files...))
+		r.NoError(err)
+		r.Equal(tt.want, IsEPUB(&zr.Reader))
+		r.NoError(zr.Close())
+	}
+}
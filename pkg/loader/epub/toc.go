@@ -0,0 +1,120 @@
+package epub
+
+import (
+	"archive/zip"
+	"bytes"
+	"path"
+	"slices"
+	"strings"
+
+	htmltomarkdown "github.com/JohannesKaufmann/html-to-markdown/v2"
+	"github.com/PuerkitoBio/goquery"
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil"
+)
+
+// tocTitles maps the content documents listed in the table of contents of an ebook to their titles.
+// The navigation document of EPUB 3 is preferred to the NCX file of EPUB 2.
+// A book without table of contents has no titles.
+func tocTitles(zr *zip.Reader, dir string, pkg packageDocument) (map[string]string, error) {
+	titles := make(map[string]string)
+	add := func(tocDir, href, title string) {
+		name := resolve(tocDir, href)
+		title = strings.Join(strings.Fields(title), " ")
+		// the first entry of a document is its title, the others are its sections
+		if _, ok := titles[name]; !ok && title != "" {
+			titles[name] = title
+		}
+	}
+
+	for _, item := range pkg.Manifest {
+		if !slices.Contains(strings.Fields(item.Properties), "nav") {
+			continue
+		}
+
+		name := resolve(dir, item.Href)
+		data, err := ziputil.ReadFile(zr, name)
+		if err != nil {
+			return nil, err
+		}
+		doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+
+		toc := doc.Find("nav").FilterFunction(func(_ int, s *goquery.Selection) bool {
+			return slices.Contains(strings.Fields(s.AttrOr("epub:type", "")), "toc")
+		})
+		if toc.Length() == 0 {
+			toc = doc.Find("nav").First()
+		}
+		toc.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+			add(path.Dir(name), a.AttrOr("href", ""), a.Text())
+		})
+
+		if len(titles) > 0 {
+			return titles, nil
+		}
+	}
+
+	// EPUB 2 refers to the NCX file from the spine
+	i := slices.IndexFunc(pkg.Manifest, func(item manifestItem) bool {
+		return item.ID == pkg.Spine.Toc || item.MediaType == "application/x-dtbncx+xml"
+	})
+	if i == -1 {
+		return titles, nil
+	}
+
+	type navPoint struct {
+		Label   string `xml:"navLabel>text"`
+		Content struct {
+			Src string `xml:"src,attr"`
+		} `xml:"content"`
+		Children []navPoint `xml:"navPoint"`
+	}
+	var ncx struct {
+		Points []navPoint `xml:"navMap>navPoint"`
+	}
+
+	name := resolve(dir, pkg.Manifest[i].Href)
+	if err := ziputil.DecodeXML(zr, name, &ncx, htmlEntities); err != nil {
+		return nil, err
+	}
+
+	var walk func(points []navPoint)
+	walk = func(points []navPoint) {
+		for _, p := range points {
+			add(path.Dir(name), p.Content.Src, p.Label)
+			walk(p.Children)
+		}
+	}
+	walk(ncx.Points)
+
+	return titles, nil
+}
+
+// toMarkdown converts a content document to Markdown, and returns its first heading.
+// Images and other media are left out, as they can't be read from the text.
+func toMarkdown(data []byte) (text string, heading string, err error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return "", "", err
+	}
+
+	body := doc.Find("body")
+	body.Find("img, svg, image, video, audio, object, script, style").Remove()
+
+	heading = strings.Join(strings.Fields(body.Find("h1, h2, h3").First().Text()), " ")
+	if heading == "" {
+		heading = strings.Join(strings.Fields(doc.Find("title").First().Text()), " ")
+	}
+
+	html, err := body.Html()
+	if err != nil {
+		return "", "", err
+	}
+	text, err = htmltomarkdown.ConvertString(html)
+	if err != nil {
+		return "", "", err
+	}
+	return strings.TrimSpace(text), heading, nil
+}
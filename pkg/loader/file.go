@@ -0,0 +1,130 @@
+package loader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/loader/epub"
+	"github.com/nt54hamnghi/seaq/pkg/loader/office"
+	"github.com/nt54hamnghi/seaq/pkg/loader/pdf"
+	"github.com/nt54hamnghi/seaq/pkg/loader/repo"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Format is the format of a local file.
+type Format string
+
+const (
+	// FormatText is any file that is not a document of a supported format, read as is.
+	FormatText Format = "text"
+	FormatPDF  Format = "pdf"
+	FormatEPUB Format = "epub"
+	FormatDOCX Format = office.DOCX
+	FormatPPTX Format = office.PPTX
+	FormatODT  Format = office.ODT
+	// FormatRepo is a directory, read as a codebase.
+	FormatRepo Format = "repo"
+)
+
+// zipMagic is the header of ZIP archives, which EPUB and office documents are.
+var zipMagic = []byte("PK\x03\x04")
+
+// DetectFormat detects the format of a file from its content, rather than its extension.
+func DetectFormat(name string) (Format, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if info, err := f.Stat(); err != nil {
+		return "", err
+	} else if info.IsDir() {
+		return FormatRepo, nil
+	}
+
+	header := make([]byte, 512)
+	n, err := io.ReadFull(f, header)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	header = header[:n]
+
+	switch {
+	case pdf.IsPDF(header):
+		return FormatPDF, nil
+	case bytes.HasPrefix(header, zipMagic):
+		info, err := f.Stat()
+		if err != nil {
+			return "", err
+		}
+		zr, err := zip.NewReader(f, info.Size())
+		if err != nil {
+			// not a valid archive, it's read as is
+			return FormatText, nil
+		}
+		if epub.IsEPUB(zr) {
+			return FormatEPUB, nil
+		}
+		if format := office.Detect(zr); format != "" {
+			return Format(format), nil
+		}
+	}
+
+	return FormatText, nil
+}
+
+// NewDocumentLoader returns a loader of a document with default options,
+// or nil for files of FormatText.
+func NewDocumentLoader(name string, format Format) cache.CacheableLoader {
+	switch format {
+	case FormatPDF:
+		return pdf.NewPDFLoader(pdf.WithFile(name))
+	case FormatEPUB:
+		return epub.NewEPUBLoader(epub.WithFile(name))
+	case FormatDOCX, FormatPPTX, FormatODT:
+		return office.NewOfficeLoader(office.WithFile(name))
+	case FormatRepo:
+		return repo.NewRepoLoader(repo.WithDir(name))
+	default:
+		return nil
+	}
+}
+
+// LoadFile loads an input file as documents.
+// The text of documents, such as PDF or EPUB files, is extracted, other files are read as is.
+// A directory is read as a codebase, one document per source file.
+func LoadFile(ctx context.Context, name string) ([]schema.Document, error) {
+	format, err := DetectFormat(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if l := NewDocumentLoader(name, format); l != nil {
+		return l.Load(ctx)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return []schema.Document{{
+		PageContent: string(data),
+		Metadata:    map[string]any{"file": filepath.Base(name)},
+	}}, nil
+}
+
+// ReadFile reads an input file as text, see LoadFile.
+func ReadFile(ctx context.Context, name string) (string, error) {
+	docs, err := LoadFile(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	return joinDocuments(docs), nil
+}
@@ -0,0 +1,86 @@
+package loader
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func zipOf(t *testing.T, files ...[2]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.Create(f[0])
+		require.NoError(t, err)
+		_, err = w.Write([]byte(f[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestDetectFormat(t *testing.T) {
+	testCases := []struct {
+		name string
+		data []byte
+		want Format
+	}{
+		{name: "text", data: []byte("hello"), want: FormatText},
+		{name: "empty", data: []byte{}, want: FormatText},
+		{name: "pdf", data: []byte("%PDF-1.7\n"), want: FormatPDF},
+		{name: "epub", data: zipOf(t, [2]string{"mimetype", "application/epub+zip"}), want: FormatEPUB},
+		{name: "odt", data: zipOf(t, [2]string{"mimetype", "application/vnd.oasis.opendocument.text"}), want: FormatODT},
+		{name: "docx", data: zipOf(t, [2]string{"word/document.xml", "<w:document/>"}), want: FormatDOCX},
+		{name: "pptx", data: zipOf(t, [2]string{"ppt/presentation.xml", "<p:presentation/>"}), want: FormatPPTX},
+		{name: "other zip", data: zipOf(t, [2]string{"readme.txt", "hello"}), want: FormatText},
+		{name: "broken zip", data: []byte("PK\x03\x04broken"), want: FormatText},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			file := filepath.Join(t.TempDir(), "file")
+			r.NoError(os.WriteFile(file, tt.data, 0o644))
+
+			got, err := DetectFormat(file)
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+
+	got, err := DetectFormat(t.TempDir())
+	require.NoError(t, err)
+	require.Equal(t, FormatRepo, got)
+
+	_, err = DetectFormat(filepath.Join(t.TempDir(), "missing"))
+	require.Error(t, err)
+}
+
+func TestReadFile(t *testing.T) {
+	r := require.New(t)
+
+	// text files are read as is
+	file := filepath.Join(t.TempDir(), "notes.txt")
+	r.NoError(os.WriteFile(file, []byte("  some\nnotes\n"), 0o644))
+
+	text, err := ReadFile(context.Background(), file)
+	r.NoError(err)
+	r.Equal("  some\nnotes\n", text)
+
+	docs, err := LoadFile(context.Background(), file)
+	r.NoError(err)
+	r.Equal("notes.txt", docs[0].Metadata["file"])
+
+	// directories are read as a codebase
+	docs, err = LoadFile(context.Background(), filepath.Dir(file))
+	r.NoError(err)
+	r.Equal("notes.txt", docs[0].Metadata["path"])
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
 	"github.com/tmc/langchaingo/documentloaders"
+	"github.com/tmc/langchaingo/schema"
 )
 
 // LoadAndJoin loads documents using the Loader and joins their content into a single string.
@@ -17,7 +18,11 @@ func LoadAndJoin(ctx context.Context, l documentloaders.Loader) (string, error)
 	if err != nil {
 		return "", err
 	}
+	return joinDocuments(docs), nil
+}
 
+// joinDocuments joins the content of documents, using "\n" as the separator.
+func joinDocuments(docs []schema.Document) string {
 	var buidler strings.Builder
 	for i, doc := range docs {
 		if i > 0 {
@@ -25,7 +30,7 @@ func LoadAndJoin(ctx context.Context, l documentloaders.Loader) (string, error)
 		}
 		buidler.WriteString(doc.PageContent)
 	}
-	return buidler.String(), nil
+	return buidler.String()
 }
 
 // LoadAndMarshal loads documents using the Loader and marshals them into a JSON string.
@@ -0,0 +1,204 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/asticode/go-astisub"
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/loader/subtitle"
+	"github.com/nt54hamnghi/seaq/pkg/util/timestamp"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// ffmpeg is the binary extracting the audio of media files.
+var ffmpeg = "ffmpeg"
+
+type filter struct {
+	start timestamp.Timestamp
+	end   timestamp.Timestamp
+}
+
+type Loader struct {
+	filter
+	file        string
+	transcriber Transcriber
+}
+
+type Option func(*Loader)
+
+func WithFile(file string) Option {
+	return func(o *Loader) {
+		o.file = file
+	}
+}
+
+func WithStart(start timestamp.Timestamp) Option {
+	return func(o *Loader) {
+		o.start = start
+	}
+}
+
+func WithEnd(end timestamp.Timestamp) Option {
+	return func(o *Loader) {
+		o.end = end
+	}
+}
+
+func WithTranscriber(t Transcriber) Option {
+	return func(o *Loader) {
+		o.transcriber = t
+	}
+}
+
+func NewMediaLoader(opts ...Option) *Loader {
+	loader := &Loader{}
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
+}
+
+// Load transcribes the audio of a media file and returns documents, one per caption,
+// with the same metadata as the captions of subtitle files.
+// Only the audio between start and end is transcribed.
+func (l Loader) Load(ctx context.Context) ([]schema.Document, error) {
+	if l.transcriber == nil {
+		return nil, errors.New("no transcription backend")
+	}
+	if _, err := os.Stat(l.file); err != nil {
+		return nil, err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "seaq-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temporary directory to save the audio: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	format := l.transcriber.Format()
+	audio := filepath.Join(tmpDir, "audio."+string(format))
+	if err := extractAudio(ctx, l.file, audio, format, l.filter); err != nil {
+		return nil, err
+	}
+
+	sub, err := l.transcriber.Transcribe(ctx, audio)
+	if err != nil {
+		return nil, err
+	}
+	if len(sub.Items) == 0 {
+		return nil, fmt.Errorf("no speech found in %s", l.file)
+	}
+
+	// captions are timed from the start of the extracted audio
+	shift(sub, l.start.AsDuration())
+
+	return subtitle.Documents(sub, l.start, l.end)
+}
+
+// LoadAndSplit loads from a source and splits the documents using a text splitter.
+func (l Loader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (l Loader) Hash() ([]byte, error) {
+	if l.transcriber == nil {
+		return nil, errors.New("no transcription backend")
+	}
+	return cache.HashFile(l.file, map[string]any{
+		"type":        "media",
+		"transcriber": l.transcriber.String(),
+		"start":       l.start.String(),
+		"end":         l.end.String(),
+	})
+}
+
+func (l Loader) Type() string {
+	return "media"
+}
+
+// ffmpegArgs returns the arguments extracting the mono 16 kHz audio between start and end
+// of a media file, in the given format.
+func ffmpegArgs(file, audio string, format AudioFormat, f filter) ([]string, error) {
+	args := []string{"-nostdin", "-hide_banner", "-loglevel", "error", "-y"}
+
+	start := f.start.AsDuration()
+	if start > 0 {
+		args = append(args, "-ss", seconds(start))
+	}
+	args = append(args, "-i", file)
+	if !f.end.IsZero() {
+		length := f.end.AsDuration() - start
+		if length <= 0 {
+			return nil, errors.New("start time must be before end time")
+		}
+		args = append(args, "-t", seconds(length))
+	}
+
+	args = append(args, "-vn", "-ac", "1", "-ar", "16000")
+	switch format {
+	case MP3:
+		args = append(args, "-c:a", "libmp3lame", "-b:a", "32k")
+	case WAV:
+		args = append(args, "-c:a", "pcm_s16le")
+	default:
+		return nil, fmt.Errorf("unsupported audio format %s", format)
+	}
+
+	return append(args, audio), nil
+}
+
+// extractAudio extracts the audio of a media file with ffmpeg.
+func extractAudio(ctx context.Context, file, audio string, format AudioFormat, f filter) error {
+	args, err := ffmpegArgs(file, audio, format, f)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, ffmpeg, args...)
+
+	// ffmpeg writes the audio to a file, and only errors to stderr
+	if _, err := cmd.Output(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return errors.New("ffmpeg is required to extract the audio, install it from https://ffmpeg.org")
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return fmt.Errorf("failed to extract the audio of %s: %w: %s", file, exitErr, bytes.TrimSpace(exitErr.Stderr))
+		}
+		return fmt.Errorf("failed to extract the audio of %s: %w", file, err)
+	}
+
+	return nil
+}
+
+// shift moves the captions by offset.
+func shift(sub *astisub.Subtitles, offset time.Duration) {
+	if offset == 0 {
+		return
+	}
+	for _, item := range sub.Items {
+		if item != nil {
+			item.StartAt += offset
+			item.EndAt += offset
+		}
+	}
+}
+
+func seconds(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64)
+}
@@ -0,0 +1,239 @@
+package media
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astisub"
+	"github.com/nt54hamnghi/seaq/pkg/util/timestamp"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTranscriber returns fixed captions, timed from the start of the audio.
+type fakeTranscriber struct {
+	format AudioFormat
+	items  []*astisub.Item
+	// audio is the content of the last transcribed file
+	audio string
+}
+
+func (t *fakeTranscriber) Transcribe(_ context.Context, audio string) (*astisub.Subtitles, error) {
+	data, err := os.ReadFile(audio)
+	if err != nil {
+		return nil, err
+	}
+	t.audio = string(data)
+
+	sub := astisub.NewSubtitles()
+	for _, item := range t.items {
+		copied := *item
+		sub.Items = append(sub.Items, &copied)
+	}
+	return sub, nil
+}
+
+func (t *fakeTranscriber) Format() AudioFormat {
+	return t.format
+}
+
+func (t *fakeTranscriber) String() string {
+	return "fake/" + string(t.format)
+}
+
+func caption(start, end time.Duration, text string) *astisub.Item {
+	return &astisub.Item{
+		StartAt: start,
+		EndAt:   end,
+		Lines:   []astisub.Line{{Items: []astisub.LineItem{{Text: text}}}},
+	}
+}
+
+// fakeFFmpeg replaces ffmpeg with a script writing its arguments to the output file.
+func fakeFFmpeg(t *testing.T) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake ffmpeg is a shell script")
+	}
+
+	script := filepath.Join(t.TempDir(), "ffmpeg")
+	content := "#!/bin/sh\nfor out; do :; done\necho \"$@\" > \"$out\"\n"
+	require.NoError(t, os.WriteFile(script, []byte(content), 0o755))
+
+	old := ffmpeg
+	ffmpeg = script
+	t.Cleanup(func() { ffmpeg = old })
+}
+
+func writeMedia(t *testing.T) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "talk.mp4")
+	require.NoError(t, os.WriteFile(file, []byte("video"), 0o644))
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	fakeFFmpeg(t)
+	file := writeMedia(t)
+
+	testCases := []struct {
+		name      string
+		start     timestamp.Timestamp
+		end       timestamp.Timestamp
+		wantStart []time.Duration
+		wantArgs  string
+	}{
+		{
+			name:      "all",
+			wantStart: []time.Duration{0, 10 * time.Second},
+			wantArgs:  "-i " + file + " -vn",
+		},
+		{
+			name:      "interval",
+			start:     timestamp.Timestamp{Minute: 1},
+			end:       timestamp.Timestamp{Minute: 2},
+			wantStart: []time.Duration{time.Minute, time.Minute + 10*time.Second},
+			wantArgs:  "-ss 60 -i " + file + " -t 60 -vn",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			transcriber := &fakeTranscriber{
+				format: WAV,
+				items: []*astisub.Item{
+					caption(0, 4*time.Second, "Hello everyone."),
+					caption(10*time.Second, 12*time.Second, "Let's start."),
+				},
+			}
+
+			docs, err := NewMediaLoader(
+				WithFile(file),
+				WithStart(tt.start),
+				WithEnd(tt.end),
+				WithTranscriber(transcriber),
+			).Load(context.Background())
+			r.NoError(err)
+			r.Contains(transcriber.audio, tt.wantArgs)
+			r.Contains(transcriber.audio, "pcm_s16le")
+
+			got := make([]time.Duration, len(docs))
+			for i, d := range docs {
+				got[i] = d.Metadata["StartAt"].(time.Duration)
+				r.Contains(d.Metadata, "EndAt")
+				r.Contains(d.Metadata, "Comment")
+			}
+			r.Equal(tt.wantStart, got)
+			r.Equal("Hello everyone.", docs[0].PageContent)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	fakeFFmpeg(t)
+	r := require.New(t)
+	ctx := context.Background()
+	file := writeMedia(t)
+
+	_, err := NewMediaLoader(WithFile(file)).Load(ctx)
+	r.ErrorContains(err, "no transcription backend")
+
+	_, err = NewMediaLoader(
+		WithFile(filepath.Join(t.TempDir(), "missing.mp4")),
+		WithTranscriber(&fakeTranscriber{format: MP3}),
+	).Load(ctx)
+	r.Error(err)
+
+	_, err = NewMediaLoader(WithFile(file), WithTranscriber(&fakeTranscriber{format: MP3})).Load(ctx)
+	r.ErrorContains(err, "no speech found")
+}
+
+func Test_ffmpegArgs(t *testing.T) {
+	testCases := []struct {
+		name    string
+		format  AudioFormat
+		filter  filter
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "mp3",
+			format: MP3,
+			want: []string{
+				"-nostdin", "-hide_banner", "-loglevel", "error", "-y",
+				"-i", "in.mkv",
+				"-vn", "-ac", "1", "-ar", "16000", "-c:a", "libmp3lame", "-b:a", "32k",
+				"out.mp3",
+			},
+		},
+		{
+			name:   "wav with start",
+			format: WAV,
+			filter: filter{start: timestamp.Timestamp{Second: 30}},
+			want: []string{
+				"-nostdin", "-hide_banner", "-loglevel", "error", "-y",
+				"-ss", "30", "-i", "in.mkv",
+				"-vn", "-ac", "1", "-ar", "16000", "-c:a", "pcm_s16le",
+				"out.mp3",
+			},
+		},
+		{
+			name:   "end",
+			format: WAV,
+			filter: filter{end: timestamp.Timestamp{Minute: 1, Second: 30}},
+			want: []string{
+				"-nostdin", "-hide_banner", "-loglevel", "error", "-y",
+				"-i", "in.mkv", "-t", "90",
+				"-vn", "-ac", "1", "-ar", "16000", "-c:a", "pcm_s16le",
+				"out.mp3",
+			},
+		},
+		{
+			name:    "empty interval",
+			format:  WAV,
+			filter:  filter{start: timestamp.Timestamp{Minute: 1}, end: timestamp.Timestamp{Minute: 1}},
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  AudioFormat("flac"),
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			got, err := ffmpegArgs("in.mkv", "out.mp3", tt.format, tt.filter)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestHash(t *testing.T) {
+	r := require.New(t)
+	file := writeMedia(t)
+
+	api := NewMediaLoader(WithFile(file), WithTranscriber(&fakeTranscriber{format: MP3}))
+	local := NewMediaLoader(WithFile(file), WithTranscriber(&fakeTranscriber{format: WAV}))
+
+	apiHash, err := api.Hash()
+	r.NoError(err)
+	localHash, err := local.Hash()
+	r.NoError(err)
+	r.NotEqual(apiHash, localHash)
+
+	_, err = NewMediaLoader(WithFile(file)).Hash()
+	r.Error(err)
+}
@@ -0,0 +1,211 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/asticode/go-astisub"
+)
+
+// AudioFormat is the format the audio is extracted to, and the extension of the extracted file.
+type AudioFormat string
+
+const (
+	// MP3 is compact, which keeps uploads under the size limit of transcription endpoints.
+	MP3 AudioFormat = "mp3"
+	// WAV is 16 kHz 16-bit PCM, the only input whisper.cpp accepts.
+	WAV AudioFormat = "wav"
+)
+
+// Transcriber is a speech-to-text backend.
+type Transcriber interface {
+	// Transcribe transcribes an audio file into timestamped captions.
+	Transcribe(ctx context.Context, audio string) (*astisub.Subtitles, error)
+	// Format returns the audio format the backend expects.
+	Format() AudioFormat
+	// String identifies the backend, its model and language. It's part of the cache key.
+	String() string
+}
+
+// APITranscriber transcribes with an OpenAI-compatible /audio/transcriptions endpoint,
+// such as OpenAI's, Groq's or a local whisper server's.
+type APITranscriber struct {
+	baseURL  string
+	apiKey   string
+	model    string
+	language string
+	client   *http.Client
+}
+
+// NewAPITranscriber creates a transcriber for the endpoint under baseURL, e.g. https://api.openai.com/v1.
+// An empty language lets the endpoint detect it.
+func NewAPITranscriber(baseURL, apiKey, model, language string) *APITranscriber {
+	return &APITranscriber{
+		baseURL:  strings.TrimSuffix(baseURL, "/"),
+		apiKey:   apiKey,
+		model:    model,
+		language: language,
+		client:   http.DefaultClient,
+	}
+}
+
+func (t *APITranscriber) Format() AudioFormat {
+	return MP3
+}
+
+func (t *APITranscriber) String() string {
+	return fmt.Sprintf("%s/%s/%s", t.baseURL, t.model, t.language)
+}
+
+// Transcribe uploads the audio and asks for SRT captions,
+// which every OpenAI-compatible endpoint returns with timestamps.
+func (t *APITranscriber) Transcribe(ctx context.Context, audio string) (*astisub.Subtitles, error) {
+	body, contentType, err := t.form(audio)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.baseURL+"/audio/transcriptions", body)
+	if err != nil {
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	res, err := t.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	raw, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	switch code := res.StatusCode; {
+	case code == http.StatusRequestEntityTooLarge:
+		return nil, errors.New("the audio is too large for the endpoint, select a part of it with --start and --end")
+	case code <= 199 || code >= 300:
+		return nil, fmt.Errorf("transcription failed: %s: %s", res.Status, bytes.TrimSpace(raw))
+	}
+
+	sub, err := astisub.ReadFromSRT(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("reading transcription: %w", err)
+	}
+	return sub, nil
+}
+
+// form builds the multipart form of a transcription request.
+func (t *APITranscriber) form(audio string) (io.Reader, string, error) {
+	f, err := os.Open(audio)
+	if err != nil {
+		return nil, "", err
+	}
+	defer f.Close()
+
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	fields := map[string]string{
+		"model":           t.model,
+		"response_format": "srt",
+	}
+	if t.language != "" {
+		fields["language"] = t.language
+	}
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			return nil, "", err
+		}
+	}
+
+	part, err := w.CreateFormFile("file", filepath.Base(audio))
+	if err != nil {
+		return nil, "", err
+	}
+	if _, err := io.Copy(part, f); err != nil {
+		return nil, "", err
+	}
+	if err := w.Close(); err != nil {
+		return nil, "", err
+	}
+
+	return &buf, w.FormDataContentType(), nil
+}
+
+// WhisperCPP transcribes locally with a whisper.cpp binary, e.g. whisper-cli.
+type WhisperCPP struct {
+	binary   string
+	model    string
+	language string
+}
+
+// NewWhisperCPP creates a transcriber running binary with a ggml model file.
+// An empty language lets whisper.cpp detect it.
+func NewWhisperCPP(binary, model, language string) *WhisperCPP {
+	return &WhisperCPP{
+		binary:   binary,
+		model:    model,
+		language: language,
+	}
+}
+
+func (t *WhisperCPP) Format() AudioFormat {
+	return WAV
+}
+
+func (t *WhisperCPP) String() string {
+	return fmt.Sprintf("whisper.cpp/%s/%s", filepath.Base(t.model), t.language)
+}
+
+// Transcribe runs whisper.cpp, which writes the SRT captions next to the audio.
+func (t *WhisperCPP) Transcribe(ctx context.Context, audio string) (*astisub.Subtitles, error) {
+	out := strings.TrimSuffix(audio, filepath.Ext(audio))
+
+	// whisper.cpp assumes English unless told otherwise
+	language := t.language
+	if language == "" {
+		language = "auto"
+	}
+
+	cmd := exec.CommandContext(ctx, t.binary, // nolint: gosec
+		"--model", t.model,
+		"--file", audio,
+		"--language", language,
+		"--output-srt",
+		"--output-file", out,
+		"--no-prints",
+	)
+
+	// the captions are written to a file, not to stdout
+	if _, err := cmd.Output(); err != nil {
+		if errors.Is(err, exec.ErrNotFound) {
+			return nil, fmt.Errorf("whisper.cpp binary %s not found, set its path with transcription.binary", t.binary)
+		}
+
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("running whisper.cpp: %w: %s", exitErr, bytes.TrimSpace(exitErr.Stderr))
+		}
+		return nil, fmt.Errorf("running whisper.cpp: %w", err)
+	}
+
+	sub, err := astisub.OpenFile(out + ".srt")
+	if err != nil {
+		return nil, fmt.Errorf("reading transcription: %w", err)
+	}
+	return sub, nil
+}
@@ -0,0 +1,138 @@
+package media
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const srt = `1
+00:00:00,000 --> 00:00:04,000
+Hello everyone.
+
+2
+00:00:10,000 --> 00:00:12,500
+Let's start.
+`
+
+func writeAudio(t *testing.T, name string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte("audio"), 0o644))
+	return file
+}
+
+func TestAPITranscriber_Transcribe(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/v1/audio/transcriptions" || req.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if req.FormValue("model") != "whisper-1" || req.FormValue("response_format") != "srt" ||
+			req.FormValue("language") != "en" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		f, header, err := req.FormFile("file")
+		if err != nil || header.Filename != "audio.mp3" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer f.Close()
+		if data, _ := io.ReadAll(f); string(data) != "audio" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(srt))
+	}))
+	defer server.Close()
+
+	audio := writeAudio(t, "audio.mp3")
+	sub, err := NewAPITranscriber(server.URL+"/v1/", "secret", "whisper-1", "en").Transcribe(context.Background(), audio)
+	r.NoError(err)
+	r.Len(sub.Items, 2)
+	r.Equal("Hello everyone.", sub.Items[0].String())
+	r.Equal(10*time.Second, sub.Items[1].StartAt)
+	r.Equal(12500*time.Millisecond, sub.Items[1].EndAt)
+}
+
+func TestAPITranscriber_Errors(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  int
+		wantErr string
+	}{
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: "invalid api key"},
+		{name: "too large", status: http.StatusRequestEntityTooLarge, wantErr: "--start and --end"},
+	}
+
+	audio := writeAudio(t, "audio.mp3")
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte("invalid api key"))
+			}))
+			defer server.Close()
+
+			_, err := NewAPITranscriber(server.URL, "", "whisper-1", "").Transcribe(context.Background(), audio)
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestWhisperCPP_Transcribe(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake whisper.cpp is a shell script")
+	}
+	r := require.New(t)
+
+	// the fake binary writes the captions to the --output-file, with the model in the first caption
+	dir := t.TempDir()
+	script := filepath.Join(dir, "whisper-cli")
+	content := `#!/bin/sh
+while [ $# -gt 0 ]; do
+	case "$1" in
+		--model) model="$2"; shift ;;
+		--language) language="$2"; shift ;;
+		--output-file) out="$2"; shift ;;
+	esac
+	shift
+done
+printf '1\n00:00:01,000 --> 00:00:02,000\n%s %s\n' "$model" "$language" > "$out.srt"
+`
+	r.NoError(os.WriteFile(script, []byte(content), 0o755))
+
+	audio := writeAudio(t, "audio.wav")
+	sub, err := NewWhisperCPP(script, "ggml-base.bin", "").Transcribe(context.Background(), audio)
+	r.NoError(err)
+	r.Len(sub.Items, 1)
+	r.Equal("ggml-base.bin auto", sub.Items[0].String())
+	r.Equal(time.Second, sub.Items[0].StartAt)
+
+	_, err = NewWhisperCPP(filepath.Join(dir, "missing"), "ggml-base.bin", "").Transcribe(context.Background(), audio)
+	r.Error(err)
+}
+
+func TestTranscriber_String(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("https://api.openai.com/v1/whisper-1/en", NewAPITranscriber("https://api.openai.com/v1/", "key", "whisper-1", "en").String())
+	r.Equal("whisper.cpp/ggml-base.bin/", NewWhisperCPP("whisper-cli", "/models/ggml-base.bin", "").String())
+	r.Equal(MP3, NewAPITranscriber("", "", "", "").Format())
+	r.Equal(WAV, NewWhisperCPP("", "", "").Format())
+}
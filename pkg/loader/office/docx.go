@@ -0,0 +1,177 @@
+package office
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil"
+)
+
+// docxStyles maps the IDs of a document's paragraph styles to their names, in lowercase,
+// e.g. "Heading1" to "heading 1". IDs are localized, names are not.
+func docxStyles(zr *zip.Reader) (map[string]string, error) {
+	var styles struct {
+		Styles []struct {
+			ID   string `xml:"styleId,attr"`
+			Name struct {
+				Val string `xml:"val,attr"`
+			} `xml:"name"`
+		} `xml:"style"`
+	}
+
+	if err := ziputil.DecodeXML(zr, "word/styles.xml", &styles); err != nil {
+		// styles are optional
+		if errors.Is(err, ziputil.ErrNotFound) {
+			return map[string]string{}, nil
+		}
+		return nil, err
+	}
+
+	names := make(map[string]string, len(styles.Styles))
+	for _, s := range styles.Styles {
+		names[s.ID] = strings.ToLower(s.Name.Val)
+	}
+	return names, nil
+}
+
+// headingLevel returns the level of a heading style, or 0 if the style is not a heading.
+func headingLevel(style string) int {
+	if style == "title" {
+		return 1
+	}
+	if n, ok := strings.CutPrefix(style, "heading "); ok {
+		if level, err := strconv.Atoi(n); err == nil {
+			return level
+		}
+	}
+	return 0
+}
+
+// isListStyle reports whether a paragraph style is a list, e.g. "list bullet".
+func isListStyle(style string) bool {
+	return strings.HasPrefix(style, "list ")
+}
+
+// docxMarkdown converts the body of a Word document, word/document.xml, to Markdown.
+func docxMarkdown(r io.Reader, styles map[string]string) (string, error) {
+	type paragraph struct {
+		style string
+		list  bool
+		level int
+	}
+
+	var (
+		md     markdown
+		paras  paragraphs
+		props  []paragraph
+		inText bool
+		tables int
+		tbl    *table
+	)
+
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "Fallback":
+				// alternate content is duplicated for older readers
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			case "p":
+				paras.push()
+				props = append(props, paragraph{})
+			case "pStyle":
+				if len(props) > 0 {
+					props[len(props)-1].style = styles[attr(t, "val")]
+				}
+			case "numPr":
+				if len(props) > 0 {
+					props[len(props)-1].list = true
+				}
+			case "ilvl":
+				if n, err := strconv.Atoi(attr(t, "val")); err == nil && len(props) > 0 {
+					props[len(props)-1].level = n
+				}
+			case "t":
+				inText = true
+			case "tab":
+				paras.write("\t")
+			case "br", "cr":
+				paras.write("\n")
+			case "tbl":
+				tables++
+				if tables == 1 {
+					tbl = &table{}
+				}
+			case "tr":
+				if tables == 1 {
+					tbl.startRow()
+				}
+			case "tc":
+				if tables == 1 {
+					tbl.startCell()
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				text := paras.pop()
+				if len(props) == 0 {
+					continue
+				}
+				p := props[len(props)-1]
+				props = props[:len(props)-1]
+
+				if tbl.write(text) {
+					continue
+				}
+				switch {
+				case headingLevel(p.style) > 0:
+					md.heading(headingLevel(p.style), text)
+				case p.list || isListStyle(p.style):
+					md.item(p.level, text)
+				default:
+					md.paragraph(text)
+				}
+			case "tc":
+				if tables == 1 {
+					tbl.endCell()
+				}
+			case "tr":
+				if tables == 1 {
+					tbl.endRow()
+				}
+			case "tbl":
+				tables--
+				if tables == 0 {
+					md.table(tbl.rows)
+					tbl = nil
+				}
+			}
+
+		case xml.CharData:
+			if inText {
+				paras.write(string(t))
+			}
+		}
+	}
+
+	return md.String(), nil
+}
@@ -0,0 +1,177 @@
+package office
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// markdown builds a Markdown document block by block.
+type markdown struct {
+	blocks []block
+}
+
+type block struct {
+	text string
+	// items of a list are not separated by blank lines
+	item bool
+}
+
+func (m *markdown) add(text string, item bool) {
+	m.blocks = append(m.blocks, block{text: text, item: item})
+}
+
+func (m *markdown) heading(level int, text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	level = min(max(level, 1), 6)
+	m.add(strings.Repeat("#", level)+" "+text, false)
+}
+
+func (m *markdown) paragraph(text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	m.add(text, false)
+}
+
+// item adds a list item, nested at level, starting at 0.
+func (m *markdown) item(level int, text string) {
+	if text = strings.TrimSpace(text); text == "" {
+		return
+	}
+	m.add(strings.Repeat("  ", max(level, 0))+"- "+text, true)
+}
+
+// table adds a table whose first row is the header.
+func (m *markdown) table(rows [][]string) {
+	cols := 0
+	for _, row := range rows {
+		cols = max(cols, len(row))
+	}
+	if cols == 0 {
+		return
+	}
+
+	var sb strings.Builder
+	writeRow := func(row []string) {
+		sb.WriteString("|")
+		for i := range cols {
+			cell := ""
+			if i < len(row) {
+				cell = strings.Join(strings.Fields(row[i]), " ")
+				cell = strings.ReplaceAll(cell, "|", `\|`)
+			}
+			sb.WriteString(" " + cell + " |")
+		}
+		sb.WriteString("\n")
+	}
+
+	writeRow(rows[0])
+	sb.WriteString("|" + strings.Repeat(" --- |", cols) + "\n")
+	for _, row := range rows[1:] {
+		writeRow(row)
+	}
+
+	m.add(strings.TrimSuffix(sb.String(), "\n"), false)
+}
+
+// append adds the blocks of another document.
+func (m *markdown) append(other markdown) {
+	m.blocks = append(m.blocks, other.blocks...)
+}
+
+func (m *markdown) empty() bool {
+	return len(m.blocks) == 0
+}
+
+func (m *markdown) String() string {
+	var sb strings.Builder
+	for i, b := range m.blocks {
+		if i > 0 {
+			if b.item && m.blocks[i-1].item {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString("\n\n")
+			}
+		}
+		sb.WriteString(b.text)
+	}
+	return sb.String()
+}
+
+// attr returns the value of an attribute by its local name, ignoring its namespace.
+func attr(se xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+// paragraphs is a stack of paragraphs being read.
+// Paragraphs can nest, e.g. in text boxes anchored in a paragraph.
+type paragraphs []*strings.Builder
+
+func (p *paragraphs) push() {
+	*p = append(*p, &strings.Builder{})
+}
+
+func (p *paragraphs) pop() string {
+	s := *p
+	if len(s) == 0 {
+		return ""
+	}
+	top := s[len(s)-1]
+	*p = s[:len(s)-1]
+	return top.String()
+}
+
+// write writes text to the innermost paragraph, if any.
+func (p paragraphs) write(text string) {
+	if len(p) > 0 {
+		p[len(p)-1].WriteString(text)
+	}
+}
+
+// table collects the rows of a table being read.
+type table struct {
+	rows [][]string
+	row  []string
+	cell *strings.Builder
+}
+
+func (t *table) startRow() {
+	t.row = nil
+}
+
+func (t *table) endRow() {
+	t.rows = append(t.rows, t.row)
+	t.row = nil
+}
+
+func (t *table) startCell() {
+	t.cell = &strings.Builder{}
+}
+
+func (t *table) endCell() {
+	if t.cell != nil {
+		t.row = append(t.row, t.cell.String())
+		t.cell = nil
+	}
+}
+
+// write adds a paragraph to the current cell, and reports whether there is one.
+func (t *table) write(text string) bool {
+	if t == nil || t.cell == nil {
+		return false
+	}
+	if text = strings.TrimSpace(text); text != "" {
+		if t.cell.Len() > 0 {
+			t.cell.WriteString(" ")
+		}
+		t.cell.WriteString(text)
+	}
+	return true
+}
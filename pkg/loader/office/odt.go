@@ -0,0 +1,146 @@
+package office
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// odtMarkdown converts the body of an OpenDocument text, content.xml, to Markdown.
+func odtMarkdown(r io.Reader) (string, error) {
+	type paragraph struct {
+		heading int
+		list    bool
+	}
+
+	var (
+		md     markdown
+		paras  paragraphs
+		props  []paragraph
+		lists  int
+		tables int
+		tbl    *table
+	)
+
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "note", "annotation", "tracked-changes":
+				// footnotes, comments and deleted text are not part of the text
+				if err := d.Skip(); err != nil {
+					return "", err
+				}
+			case "h":
+				level, err := strconv.Atoi(attr(t, "outline-level"))
+				if err != nil {
+					level = 1
+				}
+				paras.push()
+				props = append(props, paragraph{heading: level})
+			case "p":
+				paras.push()
+				props = append(props, paragraph{list: lists > 0})
+			case "list":
+				lists++
+			case "s":
+				n, err := strconv.Atoi(attr(t, "c"))
+				if err != nil {
+					n = 1
+				}
+				paras.write(strings.Repeat(" ", n))
+			case "tab":
+				paras.write("\t")
+			case "line-break":
+				paras.write("\n")
+			case "table":
+				tables++
+				if tables == 1 {
+					tbl = &table{}
+				}
+			case "table-row":
+				if tables == 1 {
+					tbl.startRow()
+				}
+			case "table-cell", "covered-table-cell":
+				if tables == 1 {
+					tbl.startCell()
+				}
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "h", "p":
+				text := paras.pop()
+				if len(props) == 0 {
+					continue
+				}
+				p := props[len(props)-1]
+				props = props[:len(props)-1]
+
+				if tbl.write(text) {
+					continue
+				}
+				switch {
+				case p.heading > 0:
+					md.heading(p.heading, text)
+				case p.list:
+					md.item(lists-1, text)
+				default:
+					md.paragraph(text)
+				}
+			case "list":
+				lists--
+			case "table-cell", "covered-table-cell":
+				if tables == 1 {
+					tbl.endCell()
+				}
+			case "table-row":
+				if tables == 1 {
+					tbl.endRow()
+				}
+			case "table":
+				tables--
+				if tables == 0 {
+					md.table(tbl.rows)
+					tbl = nil
+				}
+			}
+
+		case xml.CharData:
+			// whitespace is collapsed, spaces to keep are written as <text:s/>
+			paras.write(collapseSpaces(string(t)))
+		}
+	}
+
+	return md.String(), nil
+}
+
+// collapseSpaces replaces each sequence of whitespace by a single space.
+func collapseSpaces(s string) string {
+	var sb strings.Builder
+	space := false
+	for _, r := range s {
+		if r == ' ' || r == '\t' || r == '\n' || r == '\r' {
+			if !space {
+				sb.WriteByte(' ')
+			}
+			space = true
+			continue
+		}
+		space = false
+		sb.WriteRune(r)
+	}
+	return sb.String()
+}
@@ -0,0 +1,234 @@
+// Package office loads Word (DOCX), PowerPoint (PPTX) and OpenDocument text (ODT) files as Markdown.
+package office
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/util/ranges"
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// Formats of office documents.
+const (
+	DOCX = "docx"
+	PPTX = "pptx"
+	ODT  = "odt"
+)
+
+// odtMimetype is the content of the mimetype file of OpenDocument texts.
+const odtMimetype = "application/vnd.oasis.opendocument.text"
+
+// Detect returns the format of an office document from its content, or an empty string
+// if it's not an office document of a supported format.
+func Detect(zr *zip.Reader) string {
+	if data, err := ziputil.ReadFile(zr, "mimetype"); err == nil && strings.TrimSpace(string(data)) == odtMimetype {
+		return ODT
+	}
+	for _, f := range zr.File {
+		switch f.Name {
+		case "word/document.xml":
+			return DOCX
+		case "ppt/presentation.xml":
+			return PPTX
+		}
+	}
+	return ""
+}
+
+type Loader struct {
+	file   string
+	slides ranges.Ranges
+}
+
+type Option func(*Loader)
+
+func WithFile(file string) Option {
+	return func(o *Loader) {
+		o.file = file
+	}
+}
+
+// WithSlides selects the slides of a presentation to load.
+func WithSlides(slides ranges.Ranges) Option {
+	return func(o *Loader) {
+		o.slides = slides
+	}
+}
+
+func NewOfficeLoader(opts ...Option) *Loader {
+	loader := &Loader{}
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
+}
+
+// Load loads from a source and returns documents:
+// one per selected slide for presentations, and a single one for other documents.
+func (l Loader) Load(ctx context.Context) ([]schema.Document, error) {
+	rc, err := zip.OpenReader(l.file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", l.file, err)
+	}
+	defer rc.Close()
+	zr := &rc.Reader
+
+	var docs []schema.Document
+	switch format := Detect(zr); format {
+	case DOCX:
+		docs, err = l.loadDOCX(zr)
+	case ODT:
+		docs, err = l.loadODT(zr)
+	case PPTX:
+		docs, err = l.loadPPTX(ctx, zr)
+	default:
+		return nil, fmt.Errorf("unsupported document %s, expected DOCX, PPTX or ODT", l.file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", l.file, err)
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no text found in %s", l.file)
+	}
+	return docs, nil
+}
+
+func (l Loader) loadDOCX(zr *zip.Reader) ([]schema.Document, error) {
+	styles, err := docxStyles(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := ziputil.Open(zr, "word/document.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	text, err := docxMarkdown(f, styles)
+	if err != nil {
+		return nil, err
+	}
+
+	// core properties are optional
+	var core struct {
+		Title string `xml:"title"`
+	}
+	if err := ziputil.DecodeXML(zr, "docProps/core.xml", &core); err != nil && !errors.Is(err, ziputil.ErrNotFound) {
+		return nil, err
+	}
+
+	return l.single(text, core.Title), nil
+}
+
+func (l Loader) loadODT(zr *zip.Reader) ([]schema.Document, error) {
+	f, err := ziputil.Open(zr, "content.xml")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	text, err := odtMarkdown(f)
+	if err != nil {
+		return nil, err
+	}
+
+	// metadata is optional
+	var meta struct {
+		Title string `xml:"meta>title"`
+	}
+	if err := ziputil.DecodeXML(zr, "meta.xml", &meta); err != nil && !errors.Is(err, ziputil.ErrNotFound) {
+		return nil, err
+	}
+
+	return l.single(text, meta.Title), nil
+}
+
+// single returns the document of a file loaded as a whole.
+func (l Loader) single(text string, title string) []schema.Document {
+	if text == "" {
+		return nil
+	}
+
+	metadata := map[string]any{
+		"file": filepath.Base(l.file),
+	}
+	if title = strings.TrimSpace(title); title != "" {
+		metadata["title"] = title
+	}
+	return []schema.Document{{PageContent: text, Metadata: metadata}}
+}
+
+func (l Loader) loadPPTX(ctx context.Context, zr *zip.Reader) ([]schema.Document, error) {
+	parts, err := pptxSlides(zr)
+	if err != nil {
+		return nil, err
+	}
+
+	total := len(parts)
+	if n, ok := l.slides.Exceeds(total); ok {
+		return nil, fmt.Errorf("slide %d is out of range, the presentation has %d slides", n, total)
+	}
+
+	docs := make([]schema.Document, 0, total)
+	for i, part := range parts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		num := i + 1
+		if !l.slides.Contains(num) {
+			continue
+		}
+
+		s, err := readSlide(zr, part)
+		if err != nil {
+			return nil, err
+		}
+		text := s.markdown()
+		if text == "" {
+			continue
+		}
+
+		metadata := map[string]any{
+			"file":        filepath.Base(l.file),
+			"slide":       num,
+			"totalSlides": total,
+		}
+		if s.title != "" {
+			metadata["title"] = s.title
+		}
+		docs = append(docs, schema.Document{PageContent: text, Metadata: metadata})
+	}
+
+	return docs, nil
+}
+
+// LoadAndSplit loads from a source and splits the documents using a text splitter.
+func (l Loader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (l Loader) Hash() ([]byte, error) {
+	return cache.HashFile(l.file, map[string]any{
+		"type":   "office",
+		"slides": l.slides.String(),
+	})
+}
+
+func (l Loader) Type() string {
+	return "office"
+}
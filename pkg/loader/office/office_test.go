@@ -0,0 +1,203 @@
+package office
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/ranges"
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil/ziptest"
+	"github.com/stretchr/testify/require"
+)
+
+const wordNS = `xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"`
+
+func TestLoad_DOCX(t *testing.T) {
+	r := require.New(t)
+
+	styles := `<w:styles ` + wordNS + `>
+		<w:style w:styleId="Titre1"><w:name w:val="heading 1"/></w:style>
+		<w:style w:styleId="Heading2"><w:name w:val="heading 2"/></w:style>
+		<w:style w:styleId="ListBullet"><w:name w:val="List Bullet"/></w:style>
+	</w:styles>`
+	document := `<w:document ` + wordNS + `><w:body>
+		<w:p><w:pPr><w:pStyle w:val="Titre1"/></w:pPr><w:r><w:t>Report</w:t></w:r></w:p>
+		<w:p><w:r><w:t xml:space="preserve">Some </w:t></w:r><w:r><w:t>text.</w:t><w:br/><w:t>Next line.</w:t></w:r></w:p>
+		<w:p><w:pPr><w:pStyle w:val="Heading2"/></w:pPr><w:r><w:t>Findings</w:t></w:r></w:p>
+		<w:p><w:pPr><w:pStyle w:val="ListBullet"/></w:pPr><w:r><w:t>first</w:t></w:r></w:p>
+		<w:p><w:pPr><w:numPr><w:ilvl w:val="1"/><w:numId w:val="1"/></w:numPr></w:pPr><w:r><w:t>nested</w:t></w:r></w:p>
+		<w:tbl>
+			<w:tr><w:tc><w:p><w:r><w:t>Name</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>Value</w:t></w:r></w:p></w:tc></w:tr>
+			<w:tr><w:tc><w:p><w:r><w:t>a|b</w:t></w:r></w:p></w:tc><w:tc><w:p><w:r><w:t>1</w:t></w:r></w:p><w:p><w:r><w:t>2</w:t></w:r></w:p></w:tc></w:tr>
+		</w:tbl>
+		<w:p><w:r><w:delText>deleted</w:delText><w:t>Done</w:t></w:r></w:p>
+	</w:body></w:document>`
+	core := `<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" xmlns:dc="http://purl.org/dc/elements/1.1/"><dc:title>Q3 report</dc:title></cp:coreProperties>`
+
+	file := ziptest.Write(t, "report.docx",
+		[2]string{"[Content_Types].xml", "<Types/>"},
+		[2]string{"word/document.xml", document},
+		[2]string{"word/styles.xml", styles},
+		[2]string{"docProps/core.xml", core},
+	)
+
+	docs, err := NewOfficeLoader(WithFile(file)).Load(context.Background())
+	r.NoError(err)
+	r.Len(docs, 1)
+	r.Equal(strings.Join([]string{
+		"# Report",
+		"Some text.\nNext line.",
+		"## Findings",
+		"- first\n  - nested",
+		"| Name | Value |\n| --- | --- |\n| a\\|b | 1 2 |",
+		"Done",
+	}, "\n\n"), docs[0].PageContent)
+	r.Equal(map[string]any{"file": "report.docx", "title": "Q3 report"}, docs[0].Metadata)
+}
+
+func TestLoad_ODT(t *testing.T) {
+	r := require.New(t)
+
+	content := `<office:document-content
+		xmlns:office="urn:oasis:names:tc:opendocument:xmlns:office:1.0"
+		xmlns:text="urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+		xmlns:table="urn:oasis:names:tc:opendocument:xmlns:table:1.0"><office:body><office:text>
+		<text:h text:outline-level="1">Notes</text:h>
+		<text:p>Some   text<text:s text:c="2"/>with<text:tab/>tabs<text:note><text:note-body><text:p>a footnote</text:p></text:note-body></text:note>.</text:p>
+		<text:list><text:list-item><text:p>one</text:p>
+			<text:list><text:list-item><text:p>two</text:p></text:list-item></text:list>
+		</text:list-item></text:list>
+		<table:table><table:table-row><table:table-cell><text:p>A</text:p></table:table-cell><table:table-cell><text:p>B</text:p></table:table-cell></table:table-row></table:table>
+	</office:text></office:body></office:document-content>`
+
+	file := ziptest.Write(t, "notes.odt",
+		[2]string{"mimetype", odtMimetype},
+		[2]string{"content.xml", content},
+	)
+
+	docs, err := NewOfficeLoader(WithFile(file)).Load(context.Background())
+	r.NoError(err)
+	r.Len(docs, 1)
+	r.Equal(strings.Join([]string{
+		"# Notes",
+		"Some text  with\ttabs.",
+		"- one\n  - two",
+		"| A | B |\n| --- | --- |",
+	}, "\n\n"), docs[0].PageContent)
+	r.Equal(map[string]any{"file": "notes.odt"}, docs[0].Metadata)
+}
+
+const (
+	presentationNS = `xmlns:p="http://schemas.openxmlformats.org/presentationml/2006/main" ` +
+		`xmlns:a="http://schemas.openxmlformats.org/drawingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+	relsNS = `xmlns="http://schemas.openxmlformats.org/package/2006/relationships"`
+)
+
+// pptxShape is a shape of a test slide, filling a placeholder of a type, with paragraphs at levels.
+func pptxShape(placeholder string, paragraphs ...string) string {
+	var sb strings.Builder
+	sb.WriteString(`<p:sp><p:nvSpPr><p:nvPr>`)
+	if placeholder != "" {
+		sb.WriteString(placeholder)
+	}
+	sb.WriteString(`</p:nvPr></p:nvSpPr><p:txBody>`)
+	for _, p := range paragraphs {
+		level, text, ok := strings.Cut(p, ":")
+		if !ok {
+			level, text = "0", p
+		}
+		sb.WriteString(`<a:p><a:pPr lvl="` + level + `"/><a:r><a:t>` + text + `</a:t></a:r></a:p>`)
+	}
+	sb.WriteString(`</p:txBody></p:sp>`)
+	return sb.String()
+}
+
+func pptxSlide(shapes ...string) string {
+	return `<p:sld ` + presentationNS + `><p:cSld><p:spTree>` + strings.Join(shapes, "") + `</p:spTree></p:cSld></p:sld>`
+}
+
+func writePPTX(t *testing.T) string {
+	t.Helper()
+
+	presentation := `<p:presentation ` + presentationNS + `><p:sldIdLst>
+		<p:sldId id="256" r:id="rId3"/>
+		<p:sldId id="257" r:id="rId2"/>
+		<p:sldId id="258" r:id="rId4"/>
+	</p:sldIdLst></p:presentation>`
+	presentationRels := `<Relationships ` + relsNS + `>
+		<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide2.xml"/>
+		<Relationship Id="rId3" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="slides/slide1.xml"/>
+		<Relationship Id="rId4" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slide" Target="/ppt/slides/slide3.xml"/>
+	</Relationships>`
+
+	slide1 := pptxSlide(
+		pptxShape(`<p:ph type="ctrTitle"/>`, "Welcome"),
+		pptxShape(`<p:ph type="subTitle" idx="1"/>`, "An introduction"),
+		pptxShape(`<p:ph type="sldNum" idx="12"/>`, "1"),
+	)
+	slide1Rels := `<Relationships ` + relsNS + `>
+		<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/slideLayout" Target="../slideLayouts/slideLayout1.xml"/>
+		<Relationship Id="rId2" Type="` + notesSlideType + `" Target="../notesSlides/notesSlide1.xml"/>
+	</Relationships>`
+	notes1 := pptxSlide(
+		pptxShape(`<p:ph type="sldImg"/>`),
+		pptxShape(`<p:ph type="body" idx="1"/>`, "Say hello", "Introduce yourself"),
+		pptxShape(`<p:ph type="sldNum" idx="5"/>`, "1"),
+	)
+
+	slide2 := pptxSlide(
+		pptxShape(`<p:ph type="title"/>`, "Agenda"),
+		pptxShape(`<p:ph idx="1"/>`, "Context", "1:Market", "Plan"),
+		pptxShape("", "A text box"),
+	)
+
+	return ziptest.Write(t, "deck.pptx",
+		[2]string{"ppt/presentation.xml", presentation},
+		[2]string{"ppt/_rels/presentation.xml.rels", presentationRels},
+		[2]string{"ppt/slides/slide1.xml", slide1},
+		[2]string{"ppt/slides/_rels/slide1.xml.rels", slide1Rels},
+		[2]string{"ppt/notesSlides/notesSlide1.xml", notes1},
+		[2]string{"ppt/slides/slide2.xml", slide2},
+		[2]string{"ppt/slides/slide3.xml", pptxSlide()},
+	)
+}
+
+func TestLoad_PPTX(t *testing.T) {
+	r := require.New(t)
+
+	file := writePPTX(t)
+
+	docs, err := NewOfficeLoader(WithFile(file)).Load(context.Background())
+	r.NoError(err)
+
+	// the empty third slide is left out
+	r.Len(docs, 2)
+	r.Equal("# Welcome\n\nAn introduction\n\n## Notes\n\n- Say hello\n- Introduce yourself", docs[0].PageContent)
+	r.Equal(map[string]any{"file": "deck.pptx", "slide": 1, "totalSlides": 3, "title": "Welcome"}, docs[0].Metadata)
+	r.Equal("# Agenda\n\n- Context\n  - Market\n- Plan\n\nA text box", docs[1].PageContent)
+	r.Equal(2, docs[1].Metadata["slide"])
+
+	docs, err = NewOfficeLoader(WithFile(file), WithSlides(ranges.Ranges{{Start: 2}})).Load(context.Background())
+	r.NoError(err)
+	r.Len(docs, 1)
+	r.Equal("Agenda", docs[0].Metadata["title"])
+
+	_, err = NewOfficeLoader(WithFile(file), WithSlides(ranges.Ranges{{Start: 4, End: 4}})).Load(context.Background())
+	r.ErrorContains(err, "slide 4 is out of range")
+}
+
+func TestLoad_Unsupported(t *testing.T) {
+	r := require.New(t)
+
+	file := ziptest.Write(t, "archive.zip", [2]string{"readme.txt", "hello"})
+	_, err := NewOfficeLoader(WithFile(file)).Load(context.Background())
+	r.ErrorContains(err, "unsupported document")
+
+	notZip := filepath.Join(t.TempDir(), "doc.docx")
+	r.NoError(os.WriteFile(notZip, []byte("hello"), 0o644))
+	_, err = NewOfficeLoader(WithFile(notZip)).Load(context.Background())
+	r.Error(err)
+}
@@ -0,0 +1,292 @@
+package office
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil"
+)
+
+const (
+	drawingNS      = "http://schemas.openxmlformats.org/drawingml/2006/main"
+	notesSlideType = "http://schemas.openxmlformats.org/officeDocument/2006/relationships/notesSlide"
+)
+
+// slide is the content of a slide of a presentation.
+type slide struct {
+	title string
+	body  markdown
+	notes markdown
+}
+
+// markdown formats a slide, with its title as heading and its speaker notes in their own section.
+func (s slide) markdown() string {
+	var md markdown
+	md.heading(1, s.title)
+	md.append(s.body)
+	if !s.notes.empty() {
+		md.heading(2, "Notes")
+		md.append(s.notes)
+	}
+	return md.String()
+}
+
+// relationships maps the IDs of the relationships of a part to their targets,
+// resolved from the root of the package.
+type relationships map[string]struct {
+	Type   string
+	Target string
+}
+
+// readRelationships reads the relationships of a part, e.g. ppt/presentation.xml.
+// A part without relationships has none.
+func readRelationships(zr *zip.Reader, part string) (relationships, error) {
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Type   string `xml:"Type,attr"`
+			Target string `xml:"Target,attr"`
+			Mode   string `xml:"TargetMode,attr"`
+		} `xml:"Relationship"`
+	}
+
+	dir, file := path.Split(part)
+	err := ziputil.DecodeXML(zr, path.Join(dir, "_rels", file+".rels"), &rels)
+	if errors.Is(err, ziputil.ErrNotFound) {
+		return relationships{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	res := make(relationships, len(rels.Relationships))
+	for _, r := range rels.Relationships {
+		if r.Mode == "External" {
+			continue
+		}
+		target := path.Join(dir, r.Target)
+		if strings.HasPrefix(r.Target, "/") {
+			target = strings.TrimPrefix(r.Target, "/")
+		}
+		res[r.ID] = struct {
+			Type   string
+			Target string
+		}{r.Type, target}
+	}
+	return res, nil
+}
+
+// pptxSlides returns the parts of the slides of a presentation, in order.
+func pptxSlides(zr *zip.Reader) ([]string, error) {
+	const presentation = "ppt/presentation.xml"
+
+	var pres struct {
+		Slides []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sldIdLst>sldId"`
+	}
+	if err := ziputil.DecodeXML(zr, presentation, &pres); err != nil {
+		return nil, err
+	}
+
+	rels, err := readRelationships(zr, presentation)
+	if err != nil {
+		return nil, err
+	}
+
+	slides := make([]string, 0, len(pres.Slides))
+	for _, s := range pres.Slides {
+		rel, ok := rels[s.RID]
+		if !ok {
+			return nil, fmt.Errorf("slide %s not found", s.RID)
+		}
+		slides = append(slides, rel.Target)
+	}
+	return slides, nil
+}
+
+// readSlide reads a slide and its speaker notes.
+func readSlide(zr *zip.Reader, part string) (slide, error) {
+	var s slide
+
+	f, err := ziputil.Open(zr, part)
+	if err != nil {
+		return slide{}, err
+	}
+	defer f.Close()
+
+	shapes, err := readShapes(f)
+	if err != nil {
+		return slide{}, fmt.Errorf("reading %s: %w", part, err)
+	}
+
+	for _, sh := range shapes {
+		switch sh.placeholder {
+		case "title", "ctrTitle":
+			if s.title == "" {
+				s.title = strings.Join(sh.texts(), " ")
+				continue
+			}
+			s.body.append(sh.content)
+		case "sldNum", "dt", "ftr", "hdr":
+			// slide numbers, dates, footers and headers repeat on every slide
+		default:
+			s.body.append(sh.content)
+		}
+	}
+
+	rels, err := readRelationships(zr, part)
+	if err != nil {
+		return slide{}, err
+	}
+	for _, rel := range rels {
+		if rel.Type != notesSlideType {
+			continue
+		}
+
+		f, err := ziputil.Open(zr, rel.Target)
+		if err != nil {
+			return slide{}, err
+		}
+		defer f.Close()
+
+		notes, err := readShapes(f)
+		if err != nil {
+			return slide{}, fmt.Errorf("reading %s: %w", rel.Target, err)
+		}
+		// the body of a notes page holds the notes, its other shapes the slide's image and number
+		for _, sh := range notes {
+			if sh.placeholder == "body" {
+				s.notes.append(sh.content)
+			}
+		}
+	}
+
+	return s, nil
+}
+
+// shape is a shape of a slide holding text or a table.
+type shape struct {
+	// placeholder is the type of the placeholder the shape fills, empty if it's not a placeholder,
+	// or "body" for placeholders without type, which hold content.
+	placeholder string
+	content     markdown
+}
+
+// texts returns the text of each block of the shape.
+func (sh shape) texts() []string {
+	texts := make([]string, 0, len(sh.content.blocks))
+	for _, b := range sh.content.blocks {
+		texts = append(texts, b.text)
+	}
+	return texts
+}
+
+// readShapes reads the shapes of a slide, in order.
+// Text in placeholders for content is formatted as a list, as it's usually bulleted.
+func readShapes(r io.Reader) ([]shape, error) {
+	var (
+		shapes []shape
+		cur    *shape
+		paras  paragraphs
+		level  int
+		tables int
+		tbl    *table
+		inText bool
+	)
+
+	d := xml.NewDecoder(r)
+	for {
+		tok, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch {
+			case t.Name.Local == "Fallback":
+				if err := d.Skip(); err != nil {
+					return nil, err
+				}
+			case t.Name.Local == "sp" || t.Name.Local == "graphicFrame":
+				shapes = append(shapes, shape{})
+				cur = &shapes[len(shapes)-1]
+			case t.Name.Local == "ph" && cur != nil:
+				cur.placeholder = attr(t, "type")
+				if cur.placeholder == "" {
+					cur.placeholder = "body"
+				}
+			case t.Name.Space != drawingNS:
+			case t.Name.Local == "p":
+				paras.push()
+				level = 0
+			case t.Name.Local == "pPr":
+				if n, err := strconv.Atoi(attr(t, "lvl")); err == nil {
+					level = n
+				}
+			case t.Name.Local == "t":
+				inText = true
+			case t.Name.Local == "br":
+				paras.write("\n")
+			case t.Name.Local == "tbl":
+				tables++
+				if tables == 1 {
+					tbl = &table{}
+				}
+			case t.Name.Local == "tr" && tables == 1:
+				tbl.startRow()
+			case t.Name.Local == "tc" && tables == 1:
+				tbl.startCell()
+			}
+
+		case xml.EndElement:
+			switch {
+			case t.Name.Local == "sp" || t.Name.Local == "graphicFrame":
+				cur = nil
+			case t.Name.Space != drawingNS:
+			case t.Name.Local == "t":
+				inText = false
+			case t.Name.Local == "p":
+				text := paras.pop()
+				if tbl.write(text) || cur == nil {
+					continue
+				}
+				switch cur.placeholder {
+				case "body", "obj":
+					cur.content.item(level, text)
+				default:
+					cur.content.paragraph(text)
+				}
+			case t.Name.Local == "tc" && tables == 1:
+				tbl.endCell()
+			case t.Name.Local == "tr" && tables == 1:
+				tbl.endRow()
+			case t.Name.Local == "tbl":
+				tables--
+				if tables == 0 {
+					if cur != nil {
+						cur.content.table(tbl.rows)
+					}
+					tbl = nil
+				}
+			}
+
+		case xml.CharData:
+			if inText {
+				paras.write(string(t))
+			}
+		}
+	}
+
+	return shapes, nil
+}
@@ -0,0 +1,187 @@
+package pdf
+
+import (
+	"cmp"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// run is a piece of text laid out on a single line, without wide gaps.
+type run struct {
+	text   string
+	x0, x1 float64
+	y      float64
+	size   float64
+}
+
+// sameLine reports whether a position is on the line of the run,
+// allowing for subscripts, superscripts and slightly skewed scans.
+func (r run) sameLine(y float64) bool {
+	return math.Abs(y-r.y) <= r.size/2
+}
+
+// lines groups glyphs, in the order of the content stream, into runs.
+// A run ends on a new line, or on a gap wider than a couple of characters,
+// which often separates columns or table cells.
+func lines(glyphs []pdf.Text) []run {
+	var (
+		runs []run
+		cur  *run
+	)
+	flush := func() {
+		if cur != nil {
+			if cur.text = strings.TrimSpace(cur.text); cur.text != "" {
+				runs = append(runs, *cur)
+			}
+			cur = nil
+		}
+	}
+
+	for _, g := range glyphs {
+		if g.S == "" || g.S == "\n" {
+			flush()
+			continue
+		}
+		size := max(g.FontSize, 1)
+
+		if cur != nil {
+			gap := g.X - cur.x1
+			if !cur.sameLine(g.Y) || gap < -cur.size || gap > 2*cur.size {
+				flush()
+			} else if gap > 0.15*cur.size && !strings.HasSuffix(cur.text, " ") && g.S != " " {
+				// the space between words is often a gap rather than a glyph
+				cur.text += " "
+			}
+		}
+		if cur == nil {
+			cur = &run{x0: g.X, x1: g.X, y: g.Y, size: size}
+		}
+
+		if g.S == " " && strings.HasSuffix(cur.text, " ") {
+			continue
+		}
+		cur.text += g.S
+		cur.x1 = max(cur.x1, g.X+g.W)
+	}
+	flush()
+
+	return runs
+}
+
+// layout joins runs into text in reading order, top to bottom.
+// On pages with two columns, the left column is read before the right one,
+// and runs spanning both columns, such as titles, are read in place.
+func layout(runs []run, width float64) string {
+	if width <= 0 {
+		for _, r := range runs {
+			width = max(width, r.x1)
+		}
+	}
+
+	gutter, ok := findGutter(runs, width)
+	if !ok {
+		return joinLines(runs)
+	}
+
+	slices.SortStableFunc(runs, func(a, b run) int {
+		return cmp.Compare(b.y, a.y)
+	})
+
+	var (
+		blocks      []string
+		left, right []run
+	)
+	flush := func() {
+		for _, col := range [][]run{left, right} {
+			if text := joinLines(col); text != "" {
+				blocks = append(blocks, text)
+			}
+		}
+		left, right = nil, nil
+	}
+
+	for _, r := range runs {
+		switch {
+		case r.x1 <= gutter:
+			left = append(left, r)
+		case r.x0 >= gutter:
+			right = append(right, r)
+		default:
+			flush()
+			blocks = append(blocks, r.text)
+		}
+	}
+	flush()
+
+	return strings.Join(blocks, "\n")
+}
+
+// findGutter looks for the gap between two columns in the middle of a page,
+// a position crossed by few runs, with enough runs on either side.
+func findGutter(runs []run, width float64) (float64, bool) {
+	const minRuns = 6
+	if len(runs) < minRuns || width <= 0 {
+		return 0, false
+	}
+
+	best, bestCrossing := 0.0, len(runs)+1
+	step := width / 200
+	for x := 0.3 * width; x <= 0.7*width; x += step {
+		var crossing, left, right int
+		for _, r := range runs {
+			switch {
+			case r.x1 <= x:
+				left++
+			case r.x0 >= x:
+				right++
+			default:
+				crossing++
+			}
+		}
+
+		// at most 20% of runs span the columns, and each column has at least 20% of runs
+		if crossing*5 > len(runs) || left*5 < len(runs) || right*5 < len(runs) {
+			continue
+		}
+		// prefer the position closest to the middle among the least crossed
+		if crossing < bestCrossing ||
+			(crossing == bestCrossing && math.Abs(x-width/2) < math.Abs(best-width/2)) {
+			best, bestCrossing = x, crossing
+		}
+	}
+
+	return best, bestCrossing <= len(runs)
+}
+
+// joinLines joins runs into lines, top to bottom and left to right.
+func joinLines(runs []run) string {
+	runs = slices.Clone(runs)
+	slices.SortStableFunc(runs, func(a, b run) int {
+		return cmp.Compare(b.y, a.y)
+	})
+
+	var out []string
+	for i := 0; i < len(runs); {
+		j := i + 1
+		for j < len(runs) && runs[i].sameLine(runs[j].y) {
+			j++
+		}
+
+		line := runs[i:j]
+		slices.SortStableFunc(line, func(a, b run) int {
+			return cmp.Compare(a.x0, b.x0)
+		})
+		texts := make([]string, len(line))
+		for k, r := range line {
+			texts[k] = r.text
+		}
+		out = append(out, strings.Join(texts, " "))
+
+		i = j
+	}
+
+	return strings.Join(out, "\n")
+}
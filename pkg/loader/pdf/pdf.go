@@ -0,0 +1,200 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/util/ranges"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+// magic is the header every PDF file starts with.
+var magic = []byte("%PDF-")
+
+// pdf20 is the header of PDF 2.0 files, which the pdf package rejects
+// although it reads their syntax, a superset of that of PDF 1.7.
+var pdf20 = []byte("%PDF-2.0")
+
+// IsPDF reports whether data is the beginning of a PDF file.
+func IsPDF(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+type Loader struct {
+	file  string
+	pages ranges.Ranges
+}
+
+type Option func(*Loader)
+
+func WithFile(file string) Option {
+	return func(o *Loader) {
+		o.file = file
+	}
+}
+
+func WithPages(pages ranges.Ranges) Option {
+	return func(o *Loader) {
+		o.pages = pages
+	}
+}
+
+func NewPDFLoader(opts ...Option) *Loader {
+	loader := &Loader{}
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
+}
+
+// Load loads from a source and returns documents, one per selected page.
+// Pages without text, e.g. scanned images, are left out.
+func (l Loader) Load(ctx context.Context) (docs []schema.Document, err error) {
+	// the pdf package panics on malformed files, when resolving objects
+	defer func() {
+		if r := recover(); r != nil {
+			docs, err = nil, fmt.Errorf("reading %s: %v", l.file, r)
+		}
+	}()
+
+	f, err := os.Open(l.file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// encrypted files with an empty user password, e.g. protected against edits only, are read as is
+	r, err := pdf.NewReader(v17Reader{f}, info.Size())
+	if err != nil {
+		if errors.Is(err, pdf.ErrInvalidPassword) {
+			return nil, fmt.Errorf("%s is protected by a password, remove it first", l.file)
+		}
+		return nil, fmt.Errorf("reading %s: %w", l.file, err)
+	}
+
+	total := r.NumPage()
+	if page, ok := l.pages.Exceeds(total); ok {
+		return nil, fmt.Errorf("page %d is out of range, the document has %d pages", page, total)
+	}
+
+	docs = make([]schema.Document, 0, total)
+	for i := 1; i <= total; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if !l.pages.Contains(i) {
+			continue
+		}
+
+		text, err := pageText(r.Page(i))
+		if err != nil {
+			return nil, fmt.Errorf("reading page %d of %s: %w", i, l.file, err)
+		}
+		if text == "" {
+			continue
+		}
+
+		docs = append(docs, schema.Document{
+			PageContent: text,
+			Metadata: map[string]any{
+				"file":       filepath.Base(l.file),
+				"page":       i,
+				"totalPages": total,
+			},
+		})
+	}
+
+	if len(docs) == 0 {
+		return nil, fmt.Errorf("no text found in %s, it may be a scanned document", l.file)
+	}
+	return docs, nil
+}
+
+// LoadAndSplit loads from a source and splits the documents using a text splitter.
+func (l Loader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (l Loader) Hash() ([]byte, error) {
+	return cache.HashFile(l.file, map[string]any{
+		"type":  "pdf",
+		"pages": l.pages.String(),
+	})
+}
+
+func (l Loader) Type() string {
+	return "pdf"
+}
+
+// v17Reader reads a PDF 2.0 file as a PDF 1.7 file, by rewriting its header.
+type v17Reader struct {
+	r io.ReaderAt
+}
+
+func (v v17Reader) ReadAt(p []byte, off int64) (int, error) {
+	n, err := v.r.ReadAt(p, off)
+	if off < int64(len(pdf20)) {
+		head := p[:min(n, len(pdf20)-int(off))]
+		if bytes.Equal(head, pdf20[off:int(off)+len(head)]) {
+			copy(head, "%PDF-1.7"[off:])
+		}
+	}
+	return n, err
+}
+
+// pageText extracts the text of a page in reading order.
+// It falls back to the order of the content stream if the page's layout can't be read.
+func pageText(p pdf.Page) (string, error) {
+	if p.V.IsNull() {
+		return "", errors.New("page not found")
+	}
+
+	glyphs, ok := pageGlyphs(p)
+	if !ok {
+		text, err := p.GetPlainText(nil)
+		return strings.TrimSpace(text), err
+	}
+
+	return layout(lines(glyphs), pageWidth(p)), nil
+}
+
+// pageWidth returns the width of a page's media box, which pages inherit from their parents,
+// or 0 if it's missing.
+func pageWidth(p pdf.Page) float64 {
+	for v := p.V; !v.IsNull(); v = v.Key("Parent") {
+		if box := v.Key("MediaBox"); box.Len() == 4 {
+			return box.Index(2).Float64() - box.Index(0).Float64()
+		}
+	}
+	return 0
+}
+
+// pageGlyphs returns the positioned glyphs of a page.
+// The pdf package panics on content it can't interpret, in which case ok is false.
+func pageGlyphs(p pdf.Page) (glyphs []pdf.Text, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			glyphs, ok = nil, false
+		}
+	}()
+	return p.Content().Text, true
+}
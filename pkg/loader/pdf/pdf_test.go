@@ -0,0 +1,221 @@
+package pdf
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/ranges"
+	"github.com/stretchr/testify/require"
+)
+
+// text is a line of a test page, drawn at x, y in points with a 10pt font.
+type text struct {
+	x, y float64
+	s    string
+}
+
+// buildPDF builds a PDF of letter-sized pages, with a monospaced font 6pt wide at 10pt.
+func buildPDF(pages ...[]text) []byte {
+	var objects []string
+	add := func(obj string) int {
+		objects = append(objects, obj)
+		return len(objects)
+	}
+
+	widths := strings.TrimSpace(strings.Repeat("600 ", 95))
+	font := add("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /FirstChar 32 /LastChar 126 /Widths [" + widths + "] >>")
+	pagesID := len(objects) + 2*len(pages) + 1
+
+	var kids []string
+	for _, page := range pages {
+		var stream strings.Builder
+		for _, t := range page {
+			fmt.Fprintf(&stream, "BT /F1 10 Tf %g %g Td (%s) Tj ET\n", t.x, t.y, t.s)
+		}
+		content := add(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", stream.Len(), stream.String()))
+		id := add(fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Contents %d 0 R /Resources << /Font << /F1 %d 0 R >> >> >>", pagesID, content, font))
+		kids = append(kids, fmt.Sprintf("%d 0 R", id))
+	}
+	add(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 612 792] >>", strings.Join(kids, " "), len(pages)))
+	catalog := add(fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, catalog, xref)
+
+	return buf.Bytes()
+}
+
+func writePDF(t *testing.T, pages ...[]text) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), "doc.pdf")
+	require.NoError(t, os.WriteFile(file, buildPDF(pages...), 0o644))
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	file := writePDF(t,
+		[]text{{72, 720, "First page"}, {72, 700, "second line"}},
+		[]text{{72, 720, "Second page"}},
+		[]text{},
+		[]text{{72, 720, "Fourth page"}},
+	)
+
+	testCases := []struct {
+		name  string
+		pages string
+		want  []int
+	}{
+		{name: "all", pages: "", want: []int{1, 2, 4}},
+		{name: "range", pages: "2-3", want: []int{2}},
+		{name: "open range", pages: "2-", want: []int{2, 4}},
+		{name: "list", pages: "1,4", want: []int{1, 4}},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			pages, err := ranges.Parse(tt.pages)
+			r.NoError(err)
+
+			docs, err := NewPDFLoader(WithFile(file), WithPages(pages)).Load(context.Background())
+			r.NoError(err)
+
+			got := make([]int, len(docs))
+			for i, d := range docs {
+				got[i] = d.Metadata["page"].(int)
+				r.Equal("doc.pdf", d.Metadata["file"])
+				r.Equal(4, d.Metadata["totalPages"])
+			}
+			r.Equal(tt.want, got)
+		})
+	}
+
+	docs, err := NewPDFLoader(WithFile(file)).Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "First page\nsecond line", docs[0].PageContent)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	file := writePDF(t, []text{{72, 720, "Only page"}})
+	_, err := NewPDFLoader(WithFile(file), WithPages(ranges.Ranges{{Start: 3, End: 5}})).Load(ctx)
+	r.ErrorContains(err, "page 3 is out of range")
+
+	empty := writePDF(t, []text{})
+	_, err = NewPDFLoader(WithFile(empty)).Load(ctx)
+	r.ErrorContains(err, "no text found")
+
+	notPDF := filepath.Join(t.TempDir(), "doc.pdf")
+	r.NoError(os.WriteFile(notPDF, []byte("hello"), 0o644))
+	_, err = NewPDFLoader(WithFile(notPDF)).Load(ctx)
+	r.Error(err)
+
+	// objects that can't be resolved make the pdf package panic
+	broken := filepath.Join(t.TempDir(), "doc.pdf")
+	data := bytes.ReplaceAll(buildPDF([]text{{72, 720, "Only page"}}), []byte(" 0 obj"), []byte(" 0 xyz"))
+	r.NoError(os.WriteFile(broken, data, 0o644))
+	_, err = NewPDFLoader(WithFile(broken)).Load(ctx)
+	r.ErrorContains(err, "reading "+broken)
+}
+
+func TestLoad_PDF20(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "doc.pdf")
+	data := bytes.Replace(buildPDF([]text{{72, 720, "Only page"}}), []byte("%PDF-1.4"), []byte("%PDF-2.0"), 1)
+	require.NoError(t, os.WriteFile(file, data, 0o644))
+
+	docs, err := NewPDFLoader(WithFile(file)).Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "Only page", docs[0].PageContent)
+}
+
+func TestLoad_Columns(t *testing.T) {
+	// a title across the page, two columns, and a footer across the page
+	file := writePDF(t, []text{
+		{72, 740, "A title spanning both of the columns of the page"},
+		{72, 700, "left one"},
+		{330, 700, "right one"},
+		{72, 688, "left two"},
+		{330, 688, "right two"},
+		{72, 676, "left three"},
+		{330, 676, "right three"},
+		{72, 664, "left four"},
+		{330, 664, "right four"},
+		{72, 60, "A footer spanning both of the columns of the page"},
+	})
+
+	docs, err := NewPDFLoader(WithFile(file)).Load(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, strings.Join([]string{
+		"A title spanning both of the columns of the page",
+		"left one",
+		"left two",
+		"left three",
+		"left four",
+		"right one",
+		"right two",
+		"right three",
+		"right four",
+		"A footer spanning both of the columns of the page",
+	}, "\n"), docs[0].PageContent)
+}
+
+func TestLoad_SingleColumn(t *testing.T) {
+	// lines of a single column are not split, even if some are short
+	file := writePDF(t, []text{
+		{72, 700, "A line of text that runs across most of the page width"},
+		{72, 688, "Short"},
+		{72, 676, "Another line of text running across most of the page"},
+		{72, 664, "And one more line of text across most of the page wide"},
+		{72, 652, "Yet another line of text across the most of the page"},
+		{72, 640, "The last line of text that runs across most of the page"},
+	})
+
+	docs, err := NewPDFLoader(WithFile(file)).Load(context.Background())
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(docs[0].PageContent, "A line of text that runs across most of the page width\nShort\n"))
+}
+
+func TestHash(t *testing.T) {
+	r := require.New(t)
+
+	file := writePDF(t, []text{{72, 720, "Only page"}})
+	h1, err := NewPDFLoader(WithFile(file)).Hash()
+	r.NoError(err)
+
+	h2, err := NewPDFLoader(WithFile(file), WithPages(ranges.Ranges{{Start: 1, End: 1}})).Hash()
+	r.NoError(err)
+	r.NotEqual(h1, h2)
+
+	// an edited file is not read from the cache
+	later := time.Now().Add(time.Hour)
+	r.NoError(os.Chtimes(file, later, later))
+	h3, err := NewPDFLoader(WithFile(file)).Hash()
+	r.NoError(err)
+	r.NotEqual(h1, h3)
+}
+
+func TestIsPDF(t *testing.T) {
+	require.True(t, IsPDF([]byte("%PDF-1.7\n")))
+	require.True(t, IsPDF([]byte("%PDF-2.0\n")))
+	require.False(t, IsPDF([]byte("hello")))
+}
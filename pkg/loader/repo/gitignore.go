@@ -0,0 +1,183 @@
+package repo
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/gobwas/glob"
+)
+
+// pathGlob matches slash-separated paths relative to a directory, the way .gitignore patterns do:
+//   - a pattern without a slash matches the name of a file or directory at any depth
+//   - a pattern with a slash, e.g. a leading one, matches the path from the directory
+//   - ** matches any number of directories, including none
+type pathGlob struct {
+	globs    []glob.Glob
+	baseName bool
+}
+
+func compilePathGlob(pattern string) (pathGlob, error) {
+	anchored := strings.HasPrefix(pattern, "/")
+	pattern = strings.TrimPrefix(pattern, "/")
+
+	// gobwas/glob's ** matches any characters, but not the absence of a directory,
+	// so each ** is also tried as no directory at all
+	variants := []string{pattern}
+	for i := 0; i < len(variants); i++ {
+		v := variants[i]
+		if rest, ok := strings.CutPrefix(v, "**/"); ok {
+			variants = append(variants, rest)
+		}
+		if strings.Contains(v, "/**/") {
+			variants = append(variants, strings.Replace(v, "/**/", "/", 1))
+		}
+	}
+
+	g := pathGlob{baseName: !anchored && !strings.Contains(pattern, "/")}
+	for _, v := range variants {
+		compiled, err := glob.Compile(v, '/')
+		if err != nil {
+			return pathGlob{}, fmt.Errorf("invalid glob %q: %w", pattern, err)
+		}
+		g.globs = append(g.globs, compiled)
+	}
+	return g, nil
+}
+
+func (g pathGlob) match(rel string) bool {
+	if g.baseName {
+		rel = path.Base(rel)
+	}
+	for _, gl := range g.globs {
+		if gl.Match(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// compilePathGlobs compiles patterns, such as the --include and --exclude globs.
+func compilePathGlobs(patterns []string) ([]pathGlob, error) {
+	globs := make([]pathGlob, 0, len(patterns))
+	for _, p := range patterns {
+		g, err := compilePathGlob(p)
+		if err != nil {
+			return nil, err
+		}
+		globs = append(globs, g)
+	}
+	return globs, nil
+}
+
+func matchAny(globs []pathGlob, rel string) bool {
+	for _, g := range globs {
+		if g.match(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// rule is a pattern of a .gitignore file.
+type rule struct {
+	// base is the directory of the .gitignore file, relative to the root, empty for the root
+	base    string
+	glob    pathGlob
+	negate  bool
+	dirOnly bool
+}
+
+// ignorer tells which paths the .gitignore files of a directory tree ignore.
+type ignorer struct {
+	rules []rule
+}
+
+// load reads the rules of an ignore file, whose patterns are relative to base.
+// A missing file has no rules.
+func (ig *ignorer) load(file, base string) error {
+	f, err := os.Open(file)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		r, ok, err := parseRule(scanner.Text(), base)
+		if err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		if ok {
+			ig.rules = append(ig.rules, r)
+		}
+	}
+	return scanner.Err()
+}
+
+// loadDir reads the .gitignore file of dir, a directory of the tree under root.
+func (ig *ignorer) loadDir(root, dir string) error {
+	return ig.load(filepath.Join(root, filepath.FromSlash(dir), ".gitignore"), dir)
+}
+
+// parseRule parses a line of a .gitignore file, and reports whether it's a pattern.
+func parseRule(line, base string) (rule, bool, error) {
+	line = strings.TrimRight(line, " \t\r")
+	if line == "" || strings.HasPrefix(line, "#") {
+		return rule{}, false, nil
+	}
+
+	r := rule{base: base}
+	if negated, ok := strings.CutPrefix(line, "!"); ok {
+		r.negate, line = true, negated
+	}
+	// a leading backslash escapes a literal # or !
+	line = strings.TrimPrefix(line, `\`)
+	if trimmed, ok := strings.CutSuffix(line, "/"); ok {
+		r.dirOnly, line = true, trimmed
+	}
+	if line == "" {
+		return rule{}, false, nil
+	}
+
+	g, err := compilePathGlob(line)
+	if err != nil {
+		return rule{}, false, err
+	}
+	r.glob = g
+	return r, true, nil
+}
+
+// ignored reports whether a path relative to the root is ignored.
+// As with git, the last matching rule wins, so a negated rule re-includes a path.
+func (ig *ignorer) ignored(rel string, isDir bool) bool {
+	ignored := false
+	for _, r := range ig.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		sub, ok := within(rel, r.base)
+		if !ok {
+			continue
+		}
+		if r.glob.match(sub) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// within returns the path relative to dir, and reports whether the path is under dir.
+func within(rel, dir string) (string, bool) {
+	if dir == "" {
+		return rel, true
+	}
+	return strings.CutPrefix(rel, dir+"/")
+}
@@ -0,0 +1,111 @@
+package repo
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_pathGlob(t *testing.T) {
+	testCases := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{pattern: "*.go", path: "main.go", want: true},
+		{pattern: "*.go", path: "cmd/root.go", want: true},
+		{pattern: "*.go", path: "cmd/root.py", want: false},
+		{pattern: "/*.go", path: "main.go", want: true},
+		{pattern: "/*.go", path: "cmd/root.go", want: false},
+		{pattern: "cmd/*.go", path: "cmd/root.go", want: true},
+		{pattern: "cmd/*.go", path: "cmd/fetch/page.go", want: false},
+		{pattern: "cmd/**/*.go", path: "cmd/root.go", want: true},
+		{pattern: "cmd/**/*.go", path: "cmd/fetch/flag/page.go", want: true},
+		{pattern: "**/testdata", path: "testdata", want: true},
+		{pattern: "**/testdata", path: "pkg/loader/testdata", want: true},
+		{pattern: "docs/**", path: "docs/guide/intro.md", want: true},
+		{pattern: "docs/**", path: "pkg/docs/intro.md", want: false},
+		{pattern: "build", path: "web/build", want: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			g, err := compilePathGlob(tt.pattern)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, g.match(tt.path))
+		})
+	}
+
+	_, err := compilePathGlob("[a-")
+	require.Error(t, err)
+}
+
+func Test_parseRule(t *testing.T) {
+	testCases := []struct {
+		line    string
+		wantOK  bool
+		negate  bool
+		dirOnly bool
+	}{
+		{line: "", wantOK: false},
+		{line: "# comment", wantOK: false},
+		{line: "*.log   ", wantOK: true},
+		{line: "!keep.log", wantOK: true, negate: true},
+		{line: "build/", wantOK: true, dirOnly: true},
+		{line: `\#file`, wantOK: true},
+		{line: "/", wantOK: false},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.line, func(t *testing.T) {
+			r := require.New(t)
+
+			got, ok, err := parseRule(tt.line, "")
+			r.NoError(err)
+			r.Equal(tt.wantOK, ok)
+			if ok {
+				r.Equal(tt.negate, got.negate)
+				r.Equal(tt.dirOnly, got.dirOnly)
+			}
+		})
+	}
+}
+
+func Test_ignorer(t *testing.T) {
+	r := require.New(t)
+
+	root := t.TempDir()
+	r.NoError(os.MkdirAll(filepath.Join(root, "web"), 0o755))
+	r.NoError(os.WriteFile(filepath.Join(root, ".gitignore"), []byte("*.log\n!keep.log\nbuild/\n/secret.txt\n"), 0o644))
+	r.NoError(os.WriteFile(filepath.Join(root, "web", ".gitignore"), []byte("*.map\n#file\n\\#file\n"), 0o644))
+
+	var ig ignorer
+	r.NoError(ig.loadDir(root, ""))
+	r.NoError(ig.loadDir(root, "web"))
+	r.NoError(ig.loadDir(root, "missing"))
+
+	testCases := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{path: "debug.log", want: true},
+		{path: "web/debug.log", want: true},
+		{path: "keep.log", want: false},
+		{path: "build", isDir: true, want: true},
+		{path: "build", isDir: false, want: false},
+		{path: "web/build", isDir: true, want: true},
+		{path: "secret.txt", want: true},
+		{path: "web/secret.txt", want: false},
+		{path: "web/app.js.map", want: true},
+		{path: "app.js.map", want: false},
+		{path: "web/#file", want: true},
+		{path: "main.go", want: false},
+	}
+
+	for _, tt := range testCases {
+		r.Equal(tt.want, ig.ignored(tt.path, tt.isDir), tt.path)
+	}
+}
@@ -0,0 +1,163 @@
+package repo
+
+import (
+	"path"
+	"strings"
+)
+
+// language is the language of a source file.
+type language struct {
+	// name is the display name, e.g. "Go"
+	name string
+	// fence is the info string of Markdown code blocks, e.g. "go"
+	fence string
+	// prose tells documentation and data, e.g. Markdown or JSON, from code
+	prose bool
+}
+
+var plainText = language{name: "Text", prose: true}
+
+var extensions = map[string]language{
+	".go":     {name: "Go", fence: "go"},
+	".py":     {name: "Python", fence: "python"},
+	".pyi":    {name: "Python", fence: "python"},
+	".js":     {name: "JavaScript", fence: "javascript"},
+	".mjs":    {name: "JavaScript", fence: "javascript"},
+	".cjs":    {name: "JavaScript", fence: "javascript"},
+	".jsx":    {name: "JavaScript", fence: "jsx"},
+	".ts":     {name: "TypeScript", fence: "typescript"},
+	".mts":    {name: "TypeScript", fence: "typescript"},
+	".tsx":    {name: "TypeScript", fence: "tsx"},
+	".rs":     {name: "Rust", fence: "rust"},
+	".java":   {name: "Java", fence: "java"},
+	".kt":     {name: "Kotlin", fence: "kotlin"},
+	".kts":    {name: "Kotlin", fence: "kotlin"},
+	".scala":  {name: "Scala", fence: "scala"},
+	".c":      {name: "C", fence: "c"},
+	".h":      {name: "C", fence: "c"},
+	".cc":     {name: "C++", fence: "cpp"},
+	".cpp":    {name: "C++", fence: "cpp"},
+	".cxx":    {name: "C++", fence: "cpp"},
+	".hpp":    {name: "C++", fence: "cpp"},
+	".cs":     {name: "C#", fence: "csharp"},
+	".swift":  {name: "Swift", fence: "swift"},
+	".m":      {name: "Objective-C", fence: "objectivec"},
+	".rb":     {name: "Ruby", fence: "ruby"},
+	".php":    {name: "PHP", fence: "php"},
+	".lua":    {name: "Lua", fence: "lua"},
+	".pl":     {name: "Perl", fence: "perl"},
+	".r":      {name: "R", fence: "r"},
+	".dart":   {name: "Dart", fence: "dart"},
+	".ex":     {name: "Elixir", fence: "elixir"},
+	".exs":    {name: "Elixir", fence: "elixir"},
+	".erl":    {name: "Erlang", fence: "erlang"},
+	".hs":     {name: "Haskell", fence: "haskell"},
+	".ml":     {name: "OCaml", fence: "ocaml"},
+	".clj":    {name: "Clojure", fence: "clojure"},
+	".zig":    {name: "Zig", fence: "zig"},
+	".sh":     {name: "Shell", fence: "sh"},
+	".bash":   {name: "Shell", fence: "bash"},
+	".zsh":    {name: "Shell", fence: "zsh"},
+	".fish":   {name: "Shell", fence: "fish"},
+	".ps1":    {name: "PowerShell", fence: "powershell"},
+	".sql":    {name: "SQL", fence: "sql"},
+	".html":   {name: "HTML", fence: "html"},
+	".css":    {name: "CSS", fence: "css"},
+	".scss":   {name: "SCSS", fence: "scss"},
+	".vue":    {name: "Vue", fence: "vue"},
+	".svelte": {name: "Svelte", fence: "svelte"},
+	".proto":  {name: "Protocol Buffers", fence: "protobuf"},
+	".tf":     {name: "HCL", fence: "hcl"},
+	".md":     {name: "Markdown", fence: "markdown", prose: true},
+	".mdx":    {name: "Markdown", fence: "markdown", prose: true},
+	".rst":    {name: "reStructuredText", fence: "rst", prose: true},
+	".txt":    plainText,
+	".json":   {name: "JSON", fence: "json", prose: true},
+	".yaml":   {name: "YAML", fence: "yaml", prose: true},
+	".yml":    {name: "YAML", fence: "yaml", prose: true},
+	".toml":   {name: "TOML", fence: "toml", prose: true},
+	".xml":    {name: "XML", fence: "xml", prose: true},
+	".ini":    {name: "INI", fence: "ini", prose: true},
+	".csv":    {name: "CSV", fence: "csv", prose: true},
+}
+
+var filenames = map[string]language{
+	"makefile":   {name: "Makefile", fence: "makefile"},
+	"dockerfile": {name: "Dockerfile", fence: "dockerfile"},
+	"justfile":   {name: "Just", fence: "just"},
+	"gemfile":    {name: "Ruby", fence: "ruby"},
+	"rakefile":   {name: "Ruby", fence: "ruby"},
+	"go.mod":     {name: "Go Module", prose: true},
+}
+
+// detectLanguage detects the language of a file from its name.
+func detectLanguage(rel string) language {
+	name := strings.ToLower(path.Base(rel))
+	if lang, ok := filenames[name]; ok {
+		return lang
+	}
+	if lang, ok := extensions[path.Ext(name)]; ok {
+		return lang
+	}
+	return plainText
+}
+
+// manifests describe a project, so they are read first within a token budget.
+var manifests = map[string]bool{
+	"go.mod":           true,
+	"package.json":     true,
+	"cargo.toml":       true,
+	"pyproject.toml":   true,
+	"setup.py":         true,
+	"pom.xml":          true,
+	"build.gradle":     true,
+	"build.gradle.kts": true,
+	"gemfile":          true,
+	"composer.json":    true,
+	"makefile":         true,
+	"dockerfile":       true,
+}
+
+// generated files are rarely worth reading, so they are read last within a token budget.
+var lockfiles = map[string]bool{
+	"go.sum":            true,
+	"package-lock.json": true,
+	"yarn.lock":         true,
+	"pnpm-lock.yaml":    true,
+	"cargo.lock":        true,
+	"poetry.lock":       true,
+	"gemfile.lock":      true,
+	"composer.lock":     true,
+	"uv.lock":           true,
+}
+
+func isGenerated(rel string) bool {
+	name := strings.ToLower(path.Base(rel))
+	return lockfiles[name] ||
+		strings.HasSuffix(name, ".min.js") ||
+		strings.HasSuffix(name, ".min.css") ||
+		strings.HasSuffix(name, ".pb.go") ||
+		strings.Contains(name, "_generated.") ||
+		strings.Contains(name, ".generated.")
+}
+
+func isTest(rel string) bool {
+	name := strings.ToLower(path.Base(rel))
+	stem := strings.TrimSuffix(name, path.Ext(name))
+	if strings.HasSuffix(stem, "_test") || strings.HasPrefix(stem, "test_") ||
+		strings.HasSuffix(stem, ".test") || strings.HasSuffix(stem, ".spec") {
+		return true
+	}
+
+	for _, dir := range strings.Split(path.Dir(strings.ToLower(rel)), "/") {
+		switch dir {
+		case "test", "tests", "__tests__", "testdata", "spec":
+			return true
+		}
+	}
+	return false
+}
+
+func isReadme(rel string) bool {
+	return strings.HasPrefix(strings.ToLower(path.Base(rel)), "readme")
+}
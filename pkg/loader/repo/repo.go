@@ -0,0 +1,381 @@
+package repo
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+const (
+	// maxFileSize is the size above which files are skipped, as they are mostly data or generated
+	maxFileSize = 1 << 20
+	// sniffSize is the length of the beginning of a file looked at for NUL bytes, as git does
+	sniffSize = 8000
+	// charsPerToken approximates the number of characters per token
+	charsPerToken = 4
+)
+
+// skipDirs are version control and vendored dependency directories, which are never read.
+var skipDirs = map[string]bool{
+	".git":             true,
+	".hg":              true,
+	".svn":             true,
+	"node_modules":     true,
+	"vendor":           true,
+	"bower_components": true,
+	".venv":            true,
+	"venv":             true,
+	"__pycache__":      true,
+}
+
+type Loader struct {
+	dir       string
+	include   []string
+	exclude   []string
+	prefer    []string
+	maxTokens int
+}
+
+type Option func(*Loader)
+
+func WithDir(dir string) Option {
+	return func(o *Loader) {
+		o.dir = dir
+	}
+}
+
+// WithInclude keeps only the files matching any of the globs.
+func WithInclude(globs []string) Option {
+	return func(o *Loader) {
+		o.include = globs
+	}
+}
+
+// WithExclude skips the files and directories matching any of the globs.
+func WithExclude(globs []string) Option {
+	return func(o *Loader) {
+		o.exclude = globs
+	}
+}
+
+// WithPrefer reads the files matching any of the globs first within the token budget.
+func WithPrefer(globs []string) Option {
+	return func(o *Loader) {
+		o.prefer = globs
+	}
+}
+
+// WithMaxTokens sets the token budget, 0 means no budget.
+func WithMaxTokens(maxTokens int) Option {
+	return func(o *Loader) {
+		o.maxTokens = maxTokens
+	}
+}
+
+func NewRepoLoader(opts ...Option) *Loader {
+	loader := &Loader{}
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
+}
+
+// Load loads from a source and returns documents, one per text file of the directory tree,
+// in the order of their paths.
+//
+// Files ignored by .gitignore files, binary files and vendored directories are skipped.
+// With a token budget, the files with the highest priority that fit in it are kept:
+// preferred files, then the README and manifests, source code, documentation and data, tests,
+// and generated files last.
+func (l Loader) Load(ctx context.Context) ([]schema.Document, error) {
+	files, err := l.walk()
+	if err != nil {
+		return nil, err
+	}
+	prefer, err := compilePathGlobs(l.prefer)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make([]entry, 0, len(files))
+	for _, f := range files {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		data, err := os.ReadFile(filepath.Join(l.dir, filepath.FromSlash(f.path)))
+		if err != nil {
+			return nil, err
+		}
+		if len(bytes.TrimSpace(data)) == 0 || isBinary(data) {
+			continue
+		}
+		entries = append(entries, newEntry(f.path, string(data)))
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("no text files found in %s", l.dir)
+	}
+
+	if l.maxTokens > 0 {
+		var left int
+		entries, left = fit(entries, l.maxTokens, prefer)
+		if left > 0 {
+			log.Warn("files left out to fit the token budget", "files", left, "budget", l.maxTokens)
+		}
+		if len(entries) == 0 {
+			return nil, fmt.Errorf("no file fits in the budget of %d tokens", l.maxTokens)
+		}
+	}
+
+	docs := make([]schema.Document, len(entries))
+	for i, e := range entries {
+		docs[i] = e.document()
+	}
+	return docs, nil
+}
+
+// LoadAndSplit loads from a source and splits the documents using a text splitter.
+func (l Loader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+// Hash returns the cache key of the loader, from its options and the path, size and
+// modification time of every file, so that editing, adding or removing a file isn't read from the cache.
+func (l Loader) Hash() ([]byte, error) {
+	root, err := filepath.Abs(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	files, err := l.walk()
+	if err != nil {
+		return nil, err
+	}
+
+	stamps := make([]string, len(files))
+	for i, f := range files {
+		stamps[i] = fmt.Sprintf("%s:%d:%d", f.path, f.size, f.modTime.UnixNano())
+	}
+
+	data := map[string]any{
+		"type":      "repo",
+		"dir":       root,
+		"include":   l.include,
+		"exclude":   l.exclude,
+		"prefer":    l.prefer,
+		"maxTokens": l.maxTokens,
+		"files":     stamps,
+	}
+	return cache.MarshalAndHash(data)
+}
+
+func (l Loader) Type() string {
+	return "repo"
+}
+
+// file is a file of the directory tree.
+type file struct {
+	// path is relative to the root, slash-separated
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// walk lists the files of the directory tree that aren't ignored, excluded or too large,
+// in lexical order.
+func (l Loader) walk() ([]file, error) {
+	info, err := os.Stat(l.dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", l.dir)
+	}
+
+	include, err := compilePathGlobs(l.include)
+	if err != nil {
+		return nil, err
+	}
+	exclude, err := compilePathGlobs(l.exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	var ig ignorer
+	if err := ig.load(filepath.Join(l.dir, ".git", "info", "exclude"), ""); err != nil {
+		return nil, err
+	}
+
+	var files []file
+	err = filepath.WalkDir(l.dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(l.dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() {
+			if rel == "." {
+				return ig.loadDir(l.dir, "")
+			}
+			if skipDirs[d.Name()] || ig.ignored(rel, true) || matchAny(exclude, rel) {
+				return filepath.SkipDir
+			}
+			return ig.loadDir(l.dir, rel)
+		}
+
+		// symbolic links and special files are skipped
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if ig.ignored(rel, false) || matchAny(exclude, rel) {
+			return nil
+		}
+		if len(include) > 0 && !matchAny(include, rel) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if info.Size() > maxFileSize {
+			log.Debug("skipping large file", "file", rel, "size", info.Size())
+			return nil
+		}
+
+		files = append(files, file{path: rel, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// isBinary reports whether data isn't text: it has a NUL byte in its beginning, or isn't valid UTF-8.
+func isBinary(data []byte) bool {
+	sniff := data[:min(len(data), sniffSize)]
+	return bytes.IndexByte(sniff, 0) >= 0 || !utf8.Valid(data)
+}
+
+// estimateTokens approximates the number of tokens of a text,
+// without a tokenizer, as its number of characters divided by four.
+func estimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + charsPerToken - 1) / charsPerToken
+}
+
+// entry is a text file and its content, formatted as a Markdown code block under its path.
+type entry struct {
+	path     string
+	language language
+	content  string
+	tokens   int
+}
+
+func newEntry(rel, text string) entry {
+	lang := detectLanguage(rel)
+
+	// the fence is longer than any run of backticks in the text
+	fence := "```"
+	for strings.Contains(text, fence) {
+		fence += "`"
+	}
+
+	content := fmt.Sprintf("## %s\n\n%s%s\n%s\n%s", rel, fence, lang.fence, strings.TrimRight(text, "\n"), fence)
+	return entry{
+		path:     rel,
+		language: lang,
+		content:  content,
+		tokens:   estimateTokens(content),
+	}
+}
+
+func (e entry) document() schema.Document {
+	return schema.Document{
+		PageContent: e.content,
+		Metadata: map[string]any{
+			"path":     e.path,
+			"language": e.language.name,
+			"tokens":   e.tokens,
+		},
+	}
+}
+
+// priority ranks a file within the token budget, the lowest first.
+func priority(e entry, prefer []pathGlob) int {
+	name := strings.ToLower(path.Base(e.path))
+	switch {
+	case matchAny(prefer, e.path):
+		return 0
+	case manifests[name], isReadme(e.path) && !strings.Contains(e.path, "/"):
+		return 1
+	case isGenerated(e.path):
+		return 5
+	case isTest(e.path):
+		return 4
+	case e.language.prose:
+		return 3
+	default:
+		return 2
+	}
+}
+
+// fit keeps the entries with the highest priority that fit in the token budget, in their order,
+// and returns the number of entries left out.
+// Within a priority, files closer to the root come first, then the smaller ones.
+func fit(entries []entry, maxTokens int, prefer []pathGlob) ([]entry, int) {
+	ranks := make([]int, len(entries))
+	order := make([]int, len(entries))
+	for i, e := range entries {
+		ranks[i] = priority(e, prefer)
+		order[i] = i
+	}
+
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Or(
+			cmp.Compare(ranks[a], ranks[b]),
+			cmp.Compare(strings.Count(entries[a].path, "/"), strings.Count(entries[b].path, "/")),
+			cmp.Compare(entries[a].tokens, entries[b].tokens),
+		)
+	})
+
+	keep := make([]bool, len(entries))
+	total := 0
+	for _, i := range order {
+		if total+entries[i].tokens <= maxTokens {
+			keep[i] = true
+			total += entries[i].tokens
+		}
+	}
+
+	kept := make([]entry, 0, len(entries))
+	for i, e := range entries {
+		if keep[i] {
+			kept = append(kept, e)
+		}
+	}
+	return kept, len(entries) - len(kept)
+}
@@ -0,0 +1,245 @@
+package repo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+// writeTree writes files under a temporary directory, and returns the directory.
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for name, content := range files {
+		file := filepath.Join(root, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(file), 0o755))
+		require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	}
+	return root
+}
+
+func paths(docs []schema.Document) []string {
+	got := make([]string, len(docs))
+	for i, d := range docs {
+		got[i] = d.Metadata["path"].(string)
+	}
+	return got
+}
+
+var tree = map[string]string{
+	".gitignore":                 "dist/\n*.log\n",
+	"README.md":                  "# Project\n",
+	"go.mod":                     "module example.com/project\n",
+	"main.go":                    "package main\n\nfunc main() {}\n",
+	"main_test.go":               "package main\n",
+	"cmd/root.go":                "package cmd\n",
+	"cmd/.gitignore":             "generated.go\n",
+	"cmd/generated.go":           "package cmd\n",
+	"dist/app.js":                "console.log(1)\n",
+	"debug.log":                  "error\n",
+	"vendor/lib/lib.go":          "package lib\n",
+	"node_modules/pkg/index.js":  "module.exports = {}\n",
+	".git/config":                "[core]\n",
+	"logo.png":                   "\x89PNG\r\n\x1a\n\x00\x00",
+	"empty.txt":                  "\n",
+	"docs/guide.md":              "# Guide\n",
+	"scripts/build.sh":           "#!/bin/sh\necho build\n",
+	"web/src/app.min.js":         "var a=1;\n",
+	"web/src/components/view.ts": "export const view = 1\n",
+}
+
+func TestLoad(t *testing.T) {
+	root := writeTree(t, tree)
+
+	testCases := []struct {
+		name    string
+		include []string
+		exclude []string
+		want    []string
+	}{
+		{
+			name: "all",
+			want: []string{
+				".gitignore", "README.md", "cmd/.gitignore", "cmd/root.go", "docs/guide.md", "go.mod",
+				"main.go", "main_test.go", "scripts/build.sh", "web/src/app.min.js", "web/src/components/view.ts",
+			},
+		},
+		{
+			name:    "include",
+			include: []string{"*.go"},
+			want:    []string{"cmd/root.go", "main.go", "main_test.go"},
+		},
+		{
+			name:    "include path",
+			include: []string{"web/**"},
+			want:    []string{"web/src/app.min.js", "web/src/components/view.ts"},
+		},
+		{
+			name:    "exclude",
+			include: []string{"*.go", "*.md"},
+			exclude: []string{"*_test.go", "docs"},
+			want:    []string{"README.md", "cmd/root.go", "main.go"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			docs, err := NewRepoLoader(
+				WithDir(root),
+				WithInclude(tt.include),
+				WithExclude(tt.exclude),
+			).Load(context.Background())
+			r.NoError(err)
+			r.Equal(tt.want, paths(docs))
+		})
+	}
+}
+
+func TestLoad_Document(t *testing.T) {
+	r := require.New(t)
+
+	root := writeTree(t, map[string]string{
+		"main.go":   "package main\n",
+		"README.md": "# Title\n\n```sh\ngo run .\n```\n",
+	})
+	docs, err := NewRepoLoader(WithDir(root)).Load(context.Background())
+	r.NoError(err)
+	r.Len(docs, 2)
+
+	r.Equal("## README.md\n\n````markdown\n# Title\n\n```sh\ngo run .\n```\n````", docs[0].PageContent)
+	r.Equal("Markdown", docs[0].Metadata["language"])
+
+	r.Equal("## main.go\n\n```go\npackage main\n```", docs[1].PageContent)
+	r.Equal("Go", docs[1].Metadata["language"])
+	r.Equal(estimateTokens(docs[1].PageContent), docs[1].Metadata["tokens"])
+}
+
+func TestLoad_Budget(t *testing.T) {
+	files := map[string]string{
+		"README.md":            strings.Repeat("readme ", 10),
+		"go.mod":               "module example.com/project\n",
+		"main.go":              strings.Repeat("code ", 20),
+		"pkg/deep/util.go":     strings.Repeat("util ", 20),
+		"docs/guide.md":        strings.Repeat("guide ", 20),
+		"main_test.go":         strings.Repeat("test ", 20),
+		"go.sum":               strings.Repeat("sum ", 20),
+		"internal/important.c": strings.Repeat("c ", 20),
+	}
+	root := writeTree(t, files)
+
+	all, err := NewRepoLoader(WithDir(root)).Load(context.Background())
+	require.NoError(t, err)
+	tokens := map[string]int{}
+	for _, d := range all {
+		tokens[d.Metadata["path"].(string)] = d.Metadata["tokens"].(int)
+	}
+
+	testCases := []struct {
+		name   string
+		budget int
+		prefer []string
+		want   []string
+	}{
+		{
+			name:   "readme and manifests first",
+			budget: tokens["README.md"] + tokens["go.mod"],
+			want:   []string{"README.md", "go.mod"},
+		},
+		{
+			name:   "code closer to the root next",
+			budget: tokens["README.md"] + tokens["go.mod"] + tokens["main.go"],
+			want:   []string{"README.md", "go.mod", "main.go"},
+		},
+		{
+			name:   "preferred first",
+			budget: tokens["internal/important.c"] + tokens["go.mod"],
+			prefer: []string{"internal/**"},
+			want:   []string{"go.mod", "internal/important.c"},
+		},
+		{
+			name:   "smaller files fill the rest",
+			budget: tokens["go.mod"] + 1,
+			want:   []string{"go.mod"},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			docs, err := NewRepoLoader(
+				WithDir(root),
+				WithMaxTokens(tt.budget),
+				WithPrefer(tt.prefer),
+			).Load(context.Background())
+			r.NoError(err)
+			r.Equal(tt.want, paths(docs))
+		})
+	}
+
+	_, err = NewRepoLoader(WithDir(root), WithMaxTokens(1)).Load(context.Background())
+	require.ErrorContains(t, err, "no file fits")
+}
+
+func TestLoad_Errors(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	root := writeTree(t, map[string]string{"logo.png": "\x00\x01"})
+	_, err := NewRepoLoader(WithDir(root)).Load(ctx)
+	r.ErrorContains(err, "no text files found")
+
+	_, err = NewRepoLoader(WithDir(filepath.Join(root, "logo.png"))).Load(ctx)
+	r.ErrorContains(err, "not a directory")
+
+	_, err = NewRepoLoader(WithDir(root), WithInclude([]string{"[a-"})).Load(ctx)
+	r.ErrorContains(err, "invalid glob")
+}
+
+func TestHash(t *testing.T) {
+	r := require.New(t)
+
+	root := writeTree(t, map[string]string{"main.go": "package main\n"})
+	l := NewRepoLoader(WithDir(root))
+
+	before, err := l.Hash()
+	r.NoError(err)
+	again, err := l.Hash()
+	r.NoError(err)
+	r.Equal(before, again)
+
+	// adding a file changes the key, and so do the options
+	r.NoError(os.WriteFile(filepath.Join(root, "util.go"), []byte("package main\n"), 0o644))
+	added, err := l.Hash()
+	r.NoError(err)
+	r.NotEqual(before, added)
+
+	budget, err := NewRepoLoader(WithDir(root), WithMaxTokens(100)).Hash()
+	r.NoError(err)
+	r.NotEqual(added, budget)
+}
+
+func Test_classify(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("Go", detectLanguage("cmd/root.go").name)
+	r.Equal("Makefile", detectLanguage("Makefile").name)
+	r.Equal("Text", detectLanguage("LICENSE").name)
+	r.True(detectLanguage("docs/intro.md").prose)
+
+	r.True(isTest("pkg/loader/file_test.go"))
+	r.True(isTest("src/app.spec.ts"))
+	r.True(isTest("tests/conftest.py"))
+	r.False(isTest("pkg/latest/latest.go"))
+
+	r.True(isGenerated("go.sum"))
+	r.True(isGenerated("web/vendor.min.js"))
+	r.False(isGenerated("main.go"))
+}
@@ -0,0 +1,135 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/asticode/go-astisub"
+	"github.com/nt54hamnghi/seaq/pkg/loader/cache"
+	"github.com/nt54hamnghi/seaq/pkg/util/pool"
+	"github.com/nt54hamnghi/seaq/pkg/util/timestamp"
+	"github.com/tmc/langchaingo/schema"
+	"github.com/tmc/langchaingo/textsplitter"
+)
+
+type filter struct {
+	start timestamp.Timestamp
+	end   timestamp.Timestamp
+}
+
+type Loader struct {
+	filter
+	file string
+}
+
+type Option func(*Loader)
+
+func WithFile(file string) Option {
+	return func(o *Loader) {
+		o.file = file
+	}
+}
+
+func WithStart(start timestamp.Timestamp) Option {
+	return func(o *Loader) {
+		o.start = start
+	}
+}
+
+func WithEnd(end timestamp.Timestamp) Option {
+	return func(o *Loader) {
+		o.end = end
+	}
+}
+
+func NewSubtitleLoader(opts ...Option) *Loader {
+	loader := &Loader{}
+	for _, opt := range opts {
+		opt(loader)
+	}
+
+	return loader
+}
+
+// Load loads from a source and returns documents, one per subtitle event.
+// The format is picked from the file's extension, e.g. .srt, .vtt or .ass.
+func (l Loader) Load(_ context.Context) ([]schema.Document, error) {
+	sub, err := astisub.OpenFile(l.file)
+	if err != nil {
+		if errors.Is(err, astisub.ErrInvalidExtension) {
+			return nil, fmt.Errorf("unsupported subtitle file %s, supported formats: SRT, WebVTT, SSA/ASS, STL, TTML", l.file)
+		}
+		return nil, err
+	}
+
+	return Documents(sub, l.start, l.end)
+}
+
+// LoadAndSplit loads from a source and splits the documents using a text splitter.
+func (l Loader) LoadAndSplit(ctx context.Context, splitter textsplitter.TextSplitter) ([]schema.Document, error) {
+	docs, err := l.Load(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return textsplitter.SplitDocuments(splitter, docs)
+}
+
+func (l Loader) Hash() ([]byte, error) {
+	return cache.HashFile(l.file, map[string]any{
+		"type":  "subtitle",
+		"start": l.start.String(),
+		"end":   l.end.String(),
+	})
+}
+
+func (l Loader) Type() string {
+	return "subtitle"
+}
+
+// Documents converts subtitles into documents, one per event between start and end.
+// A zero start or end leaves that side of the interval open.
+// It's shared by the loaders of captions, e.g. those of Udemy lectures.
+func Documents(sub *astisub.Subtitles, start, end timestamp.Timestamp) ([]schema.Document, error) {
+	events := make([]event, 0, len(sub.Items))
+	for _, item := range sub.Items {
+		if item != nil {
+			events = append(events, event{Item: item})
+		}
+	}
+
+	if !start.IsZero() {
+		events = timestamp.After(start, events)
+	}
+	if !end.IsZero() {
+		events = timestamp.Before(end, events)
+	}
+
+	return pool.OrderedRun(events, event.toDocument)
+}
+
+type event struct {
+	*astisub.Item
+}
+
+func (e event) AsDuration() time.Duration {
+	return e.StartAt
+}
+
+// toDocument converts an event into a document.
+func (e event) toDocument() (schema.Document, error) {
+	if e.Item == nil {
+		return schema.Document{}, errors.New("nil event")
+	}
+
+	return schema.Document{
+		PageContent: e.String(),
+		Metadata: map[string]any{
+			"Comment": e.Comments,
+			"StartAt": e.StartAt,
+			"EndAt":   e.EndAt,
+		},
+		Score: 0,
+	}, nil
+}
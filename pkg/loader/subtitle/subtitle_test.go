@@ -0,0 +1,224 @@
+package subtitle
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/asticode/go-astisub"
+	"github.com/nt54hamnghi/seaq/pkg/util/timestamp"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/schema"
+)
+
+const srt = `1
+00:00:01,000 --> 00:00:04,000
+Welcome to the course.
+
+2
+00:01:00,000 --> 00:01:05,500
+Let's talk about
+the first topic.
+
+3
+00:02:30,000 --> 00:02:35,000
+That's all for today.
+`
+
+const vtt = `WEBVTT
+
+00:00:01.000 --> 00:00:04.000
+Welcome to the course.
+
+00:01:00.000 --> 00:01:05.500
+Let's talk about the first topic.
+
+00:02:30.000 --> 00:02:35.000
+That's all for today.
+`
+
+const ass = `[Script Info]
+ScriptType: v4.00+
+
+[V4+ Styles]
+Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding
+Style: Default,Arial,20,&H00FFFFFF,&H000000FF,&H00000000,&H00000000,0,0,0,0,100,100,0,0,1,2,2,2,10,10,10,1
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:04.00,Default,,0,0,0,,Welcome to the course.
+Dialogue: 0,0:01:00.00,0:01:05.50,Default,,0,0,0,,Let's talk about the first topic.
+Dialogue: 0,0:02:30.00,0:02:35.00,Default,,0,0,0,,That's all for today.
+`
+
+func writeSubtitle(t *testing.T, name, content string) string {
+	t.Helper()
+	file := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o644))
+	return file
+}
+
+func TestLoad(t *testing.T) {
+	testCases := []struct {
+		name    string
+		file    string
+		content string
+	}{
+		{name: "srt", file: "lecture.srt", content: srt},
+		{name: "vtt", file: "lecture.vtt", content: vtt},
+		{name: "ass", file: "lecture.ass", content: ass},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			file := writeSubtitle(t, tt.file, tt.content)
+			docs, err := NewSubtitleLoader(WithFile(file)).Load(context.Background())
+			r.NoError(err)
+			r.Len(docs, 3)
+
+			r.Equal("Welcome to the course.", docs[0].PageContent)
+			r.Equal(time.Second, docs[0].Metadata["StartAt"])
+			r.Equal(4*time.Second, docs[0].Metadata["EndAt"])
+			r.Contains(docs[0].Metadata, "Comment")
+
+			r.Equal(time.Minute, docs[1].Metadata["StartAt"])
+			r.Equal(time.Minute+5500*time.Millisecond, docs[1].Metadata["EndAt"])
+			r.Equal("That's all for today.", docs[2].PageContent)
+		})
+	}
+}
+
+func TestLoad_Interval(t *testing.T) {
+	file := writeSubtitle(t, "lecture.srt", srt)
+
+	testCases := []struct {
+		name  string
+		start timestamp.Timestamp
+		end   timestamp.Timestamp
+		want  []time.Duration
+	}{
+		{
+			name: "all",
+			want: []time.Duration{time.Second, time.Minute, 150 * time.Second},
+		},
+		{
+			name:  "start",
+			start: timestamp.Timestamp{Minute: 1},
+			want:  []time.Duration{time.Minute, 150 * time.Second},
+		},
+		{
+			name: "end",
+			end:  timestamp.Timestamp{Minute: 2},
+			want: []time.Duration{time.Second, time.Minute},
+		},
+		{
+			name:  "start and end",
+			start: timestamp.Timestamp{Second: 30},
+			end:   timestamp.Timestamp{Minute: 2},
+			want:  []time.Duration{time.Minute},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			docs, err := NewSubtitleLoader(
+				WithFile(file),
+				WithStart(tt.start),
+				WithEnd(tt.end),
+			).Load(context.Background())
+			r.NoError(err)
+
+			got := make([]time.Duration, len(docs))
+			for i, d := range docs {
+				got[i] = d.Metadata["StartAt"].(time.Duration)
+			}
+			r.Equal(tt.want, got)
+		})
+	}
+}
+
+func Test_event_toDocument(t *testing.T) {
+	testCases := []struct {
+		name    string
+		item    *astisub.Item
+		want    schema.Document
+		wantErr error
+	}{
+		{
+			name:    "nil",
+			item:    nil,
+			wantErr: errors.New("nil event"),
+		},
+		{
+			name: "valid",
+			item: &astisub.Item{
+				StartAt: 1000 * time.Millisecond,
+				EndAt:   5000 * time.Millisecond,
+				Lines: []astisub.Line{
+					{Items: []astisub.LineItem{{Text: "Hello, world!"}}},
+				},
+				Comments: []string{"Test comment"},
+			},
+			want: schema.Document{
+				PageContent: "Hello, world!",
+				Metadata: map[string]any{
+					"Comment": []string{"Test comment"},
+					"StartAt": 1000 * time.Millisecond,
+					"EndAt":   5000 * time.Millisecond,
+				},
+				Score: 0,
+			},
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			event := event{Item: tt.item}
+			got, err := event.toDocument()
+			if tt.wantErr != nil {
+				r.Equal(tt.wantErr, err)
+				return
+			}
+
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	unsupported := writeSubtitle(t, "lecture.txt", srt)
+	_, err := NewSubtitleLoader(WithFile(unsupported)).Load(ctx)
+	r.ErrorContains(err, "unsupported subtitle file")
+
+	_, err = NewSubtitleLoader(WithFile(filepath.Join(t.TempDir(), "missing.srt"))).Load(ctx)
+	r.Error(err)
+}
+
+func TestHash(t *testing.T) {
+	r := require.New(t)
+
+	file := writeSubtitle(t, "lecture.srt", srt)
+	all, err := NewSubtitleLoader(WithFile(file)).Hash()
+	r.NoError(err)
+
+	again, err := NewSubtitleLoader(WithFile(file)).Hash()
+	r.NoError(err)
+	r.Equal(all, again)
+
+	clipped, err := NewSubtitleLoader(WithFile(file), WithStart(timestamp.Timestamp{Minute: 1})).Hash()
+	r.NoError(err)
+	r.NotEqual(all, clipped)
+}
@@ -12,9 +12,8 @@ import (
 	"time"
 
 	"github.com/asticode/go-astisub"
-	"github.com/nt54hamnghi/seaq/pkg/util/pool"
+	"github.com/nt54hamnghi/seaq/pkg/loader/subtitle"
 	"github.com/nt54hamnghi/seaq/pkg/util/reqx"
-	"github.com/nt54hamnghi/seaq/pkg/util/timestamp"
 	"github.com/tmc/langchaingo/schema"
 )
 
@@ -190,24 +189,7 @@ type caption struct {
 	LocaleID   string `json:"locale_id"`
 	VideoLabel string `json:"video_label"`
 	AssetID    int    `json:"asset_id"`
-	events     []event
-}
-
-type event struct {
-	*astisub.Item
-}
-
-func (c *caption) filter(opt *filter) {
-	if opt == nil {
-		return
-	}
-
-	if !opt.start.IsZero() {
-		c.events = timestamp.After(opt.start, c.events)
-	}
-	if !opt.end.IsZero() {
-		c.events = timestamp.Before(opt.end, c.events)
-	}
+	sub        *astisub.Subtitles
 }
 
 // download retrieves and parses the caption's WebVTT content.
@@ -224,40 +206,17 @@ func (c *caption) download(ctx context.Context) error {
 		return err
 	}
 
-	c.events = make([]event, 0, len(sub.Items))
-	for _, item := range sub.Items {
-		if item != nil {
-			c.events = append(c.events, event{Item: item})
-		}
-	}
-
+	c.sub = sub
 	return nil
 }
 
-// toDocuments converts a caption into a list of documents.
-func (c *caption) toDocuments() ([]schema.Document, error) {
-	return pool.OrderedRun(c.events, event.toDocument)
-}
-
-func (e event) AsDuration() time.Duration {
-	return e.StartAt
-}
-
-// toDocument converts an event into a document.
-func (e event) toDocument() (schema.Document, error) {
-	if e.Item == nil {
-		return schema.Document{}, errors.New("nil event")
+// toDocuments converts a downloaded caption into a list of documents,
+// one per event between the filter's start and end.
+func (c *caption) toDocuments(f filter) ([]schema.Document, error) {
+	if c.sub == nil {
+		return nil, errors.New("caption not downloaded")
 	}
-
-	return schema.Document{
-		PageContent: e.String(),
-		Metadata: map[string]any{
-			"Comment": e.Comments,
-			"StartAt": e.StartAt,
-			"EndAt":   e.EndAt,
-		},
-		Score: 0,
-	}, nil
+	return subtitle.Documents(c.sub, f.start, f.end)
 }
 
 // searchLectureByURL searches for a lecture by URL
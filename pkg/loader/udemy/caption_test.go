@@ -8,9 +8,7 @@ import (
 
 	"github.com/asticode/go-astisub"
 	"github.com/nt54hamnghi/seaq/pkg/util/timestamp"
-	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
-	"github.com/tmc/langchaingo/schema"
 )
 
 func Test_parseUdemyURL(t *testing.T) {
@@ -210,116 +208,59 @@ func Test_lecture_findCaption(t *testing.T) {
 	}
 }
 
-func Test_event_toDocument(t *testing.T) {
-	testCases := []struct {
-		name    string
-		item    *astisub.Item
-		want    schema.Document
-		wantErr error
-	}{
-		{
-			name:    "nil",
-			item:    nil,
-			wantErr: errors.New("nil event"),
-		},
-		{
-			name: "valid",
-			item: &astisub.Item{
-				StartAt: 1000 * time.Millisecond,
-				EndAt:   5000 * time.Millisecond,
-				Lines: []astisub.Line{
-					{Items: []astisub.LineItem{{Text: "Hello, world!"}}},
-				},
-				Comments: []string{"Test comment"},
-			},
-			want: schema.Document{
-				PageContent: "Hello, world!",
-				Metadata: map[string]any{
-					"Comment": []string{"Test comment"},
-					"StartAt": 1000 * time.Millisecond,
-					"EndAt":   5000 * time.Millisecond,
-				},
-				Score: 0,
-			},
-		},
-	}
-
-	r := require.New(t)
-
-	for _, tt := range testCases {
-		t.Run(tt.name, func(*testing.T) {
-			event := event{Item: tt.item}
-			got, err := event.toDocument()
-			if tt.wantErr != nil {
-				r.Equal(tt.wantErr, err)
-				return
-			}
-
-			r.NoError(err)
-			r.Equal(tt.want, got)
-		})
-	}
-}
-
-func Test_caption_filter(t *testing.T) {
-	events := []event{
-		{&astisub.Item{StartAt: 0}},
-		{&astisub.Item{StartAt: time.Second * 1}},
-		{&astisub.Item{StartAt: time.Second * 2}},
-		{&astisub.Item{StartAt: time.Second * 10}},
-	}
+func Test_caption_toDocuments(t *testing.T) {
+	sub := &astisub.Subtitles{Items: []*astisub.Item{
+		{StartAt: 0},
+		{StartAt: time.Second * 1},
+		{StartAt: time.Second * 2},
+		{StartAt: time.Second * 10},
+	}}
 
 	testCases := []struct {
-		name       string
-		filterOpts *filter
-		want       []event
+		name   string
+		filter filter
+		want   []time.Duration
 	}{
 		{
-			name:       "nil filter",
-			filterOpts: nil,
-			want:       events,
+			name: "no filter",
+			want: []time.Duration{0, time.Second, 2 * time.Second, 10 * time.Second},
 		},
 		{
-			name: "valid start",
-			filterOpts: &filter{
-				start: timestamp.Timestamp{Second: 2},
-			},
-			want: []event{
-				events[2], // 2 seconds
-				events[3], // 10 seconds
-			},
+			name:   "valid start",
+			filter: filter{start: timestamp.Timestamp{Second: 2}},
+			want:   []time.Duration{2 * time.Second, 10 * time.Second},
 		},
 		{
-			name: "valid end",
-			filterOpts: &filter{
-				end: timestamp.Timestamp{Second: 1},
-			},
-			want: []event{
-				events[0], // 0 seconds
-				events[1], // 1 second
-			},
+			name:   "valid end",
+			filter: filter{end: timestamp.Timestamp{Second: 1}},
+			want:   []time.Duration{0, time.Second},
 		},
 		{
 			name: "valid start and end",
-			filterOpts: &filter{
+			filter: filter{
 				start: timestamp.Timestamp{Second: 1},
 				end:   timestamp.Timestamp{Second: 2},
 			},
-			want: []event{
-				events[1], // 1 second
-				events[2], // 2 seconds
-			},
+			want: []time.Duration{time.Second, 2 * time.Second},
 		},
 	}
 
-	a := assert.New(t)
+	r := require.New(t)
 
 	for _, tt := range testCases {
 		t.Run(tt.name, func(*testing.T) {
-			c := &caption{events: events}
-			c.filter(tt.filterOpts)
+			c := &caption{sub: sub}
+			docs, err := c.toDocuments(tt.filter)
+			r.NoError(err)
 
-			a.Equal(tt.want, c.events)
+			got := make([]time.Duration, len(docs))
+			for i, d := range docs {
+				got[i] = d.Metadata["StartAt"].(time.Duration)
+			}
+			r.Equal(tt.want, got)
 		})
 	}
+
+	_, err := (&caption{}).toDocuments(filter{})
+	r.Error(err)
 }
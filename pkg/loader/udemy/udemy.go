@@ -79,9 +79,8 @@ func (l Loader) Load(ctx context.Context) ([]schema.Document, error) {
 		if err := caption.download(ctx); err != nil {
 			return nil, err
 		}
-		// filter the caption based on the start and end time
-		caption.filter(&l.filter)
-		return caption.toDocuments()
+		// keep the events between the start and end time
+		return caption.toDocuments(l.filter)
 	case article:
 		// get article from the lecture
 		article, err := lec.getArticle()
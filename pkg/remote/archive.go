@@ -0,0 +1,251 @@
+package remote
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+	"github.com/nt54hamnghi/seaq/pkg/util/reqx"
+)
+
+// maxArchiveSize is the size above which an archive is rejected.
+const maxArchiveSize = 64 << 20
+
+// maxArchiveFileSize is the size above which a file of an archive is rejected,
+// as archives aren't trusted and patterns are small text files.
+const maxArchiveFileSize = 1 << 20
+
+// newArchiveSource creates a source from a tar or zip archive,
+// read from a local file or downloaded over HTTP.
+// The archive is extracted into the user cache directory, replacing any earlier extraction.
+//
+// Archives with a single top-level directory, such as those of GitHub or GitLab releases,
+// are read from that directory.
+func newArchiveSource(location string) *fsSource {
+	return &fsSource{
+		load: func(ctx context.Context) (fs.FS, string, error) {
+			dir, err := cacheDir("archives", location)
+			if err != nil {
+				return nil, "", err
+			}
+
+			if err := extractArchive(ctx, location, dir); err != nil {
+				return nil, "", err
+			}
+
+			fsys, err := singleRoot(os.DirFS(dir))
+			return fsys, "", err
+		},
+	}
+}
+
+// extractArchive extracts the regular files of an archive into dir, based on its extension.
+func extractArchive(ctx context.Context, location string, dir string) error {
+	name := location
+	// ignore query strings of archive URLs
+	if u, err := url.Parse(location); err == nil && u.Scheme != "" {
+		name = u.Path
+	}
+	name = strings.ToLower(name)
+
+	var extract func(file string, dir string) error
+	switch {
+	case strings.HasSuffix(name, ".zip"):
+		extract = extractZip
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		extract = func(file string, dir string) error { return extractTar(file, dir, true) }
+	case strings.HasSuffix(name, ".tar"):
+		extract = func(file string, dir string) error { return extractTar(file, dir, false) }
+	default:
+		return errors.New("unsupported archive format, expected .tar.gz, .tgz, .tar or .zip")
+	}
+
+	tmp, err := os.MkdirTemp("", "seaq-archive-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	file, err := readArchive(ctx, location, tmp)
+	if err != nil {
+		return err
+	}
+
+	// a leftover of an earlier extraction would keep deleted patterns around
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+
+	if err := extract(file, dir); err != nil {
+		return fmt.Errorf("reading archive: %w", err)
+	}
+	return nil
+}
+
+// readArchive returns the path of a local archive, or downloads a remote archive into tmp.
+func readArchive(ctx context.Context, location string, tmp string) (string, error) {
+	u, err := url.Parse(location)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		info, err := os.Stat(location)
+		if err != nil {
+			return "", err
+		}
+		if info.Size() > maxArchiveSize {
+			return "", fmt.Errorf("archive is larger than %d bytes", maxArchiveSize)
+		}
+		return location, nil
+	}
+
+	log.Info("Downloading archive", "url", location)
+	res, err := reqx.Get(ctx, location, nil)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if err := res.ExpectSuccess(); err != nil {
+		return "", err
+	}
+
+	file := filepath.Join(tmp, "archive")
+	f, err := os.Create(file)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	// the server isn't trusted, reading stops past the limit
+	n, err := io.Copy(f, io.LimitReader(res.Body, maxArchiveSize+1))
+	if err != nil {
+		return "", fmt.Errorf("downloading archive: %w", err)
+	}
+	if n > maxArchiveSize {
+		return "", fmt.Errorf("archive is larger than %d bytes", maxArchiveSize)
+	}
+
+	return file, f.Close()
+}
+
+// extractTar extracts the regular files of a tar archive, optionally gzipped, into dir.
+func extractTar(file string, dir string, gzipped bool) error {
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if gzipped {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := writeEntry(dir, hdr.Name, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// extractZip extracts the regular files of a zip archive into dir.
+func extractZip(file string, dir string) error {
+	zr, err := zip.OpenReader(file)
+	if err != nil {
+		return err
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if !f.Mode().IsRegular() {
+			continue
+		}
+
+		rc, err := f.Open()
+		if err != nil {
+			return err
+		}
+		err = writeEntry(dir, f.Name, rc)
+		rc.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// writeEntry writes a file of an archive into dir.
+// Entries escaping the archive, e.g. "../etc/passwd", are skipped.
+func writeEntry(dir string, name string, r io.Reader) error {
+	name = path.Clean(strings.TrimPrefix(name, "./"))
+	if !fs.ValidPath(name) {
+		return nil
+	}
+
+	// the header size isn't trusted either, reading stops past the limit
+	data, err := io.ReadAll(io.LimitReader(r, maxArchiveFileSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > maxArchiveFileSize {
+		return fmt.Errorf("file %q in archive is larger than %d bytes", name, maxArchiveFileSize)
+	}
+
+	file := filepath.Join(dir, filepath.FromSlash(name))
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o644)
+}
+
+// singleRoot returns the only top-level directory of a file system if there is one,
+// or the file system itself otherwise.
+func singleRoot(fsys fs.FS) (fs.FS, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) != 1 || !entries[0].IsDir() || entries[0].Name() == patternsDir {
+		return fsys, nil
+	}
+
+	// an archive of a single pattern is kept as is
+	root := entries[0].Name()
+	if _, err := fs.Stat(fsys, path.Join(root, config.PatternFile)); err == nil {
+		return fsys, nil
+	}
+
+	return fs.Sub(fsys, root)
+}
@@ -0,0 +1,24 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+)
+
+// newDirSource creates a source from a local directory.
+func newDirSource(dir string) *fsSource {
+	return &fsSource{
+		load: func(context.Context) (fs.FS, string, error) {
+			info, err := os.Stat(dir)
+			if err != nil {
+				return nil, "", err
+			}
+			if !info.IsDir() {
+				return nil, "", fmt.Errorf("%q is not a directory", dir)
+			}
+			return os.DirFS(dir), "", nil
+		},
+	}
+}
@@ -0,0 +1,171 @@
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/github"
+)
+
+// patternsDir is the directory holding patterns in a source, if it exists.
+const patternsDir = "patterns"
+
+// strategiesDir is the directory holding strategies in a source.
+const strategiesDir = "strategies"
+
+// ErrNoStrategies is returned when listing the strategies of a source without a strategies directory.
+var ErrNoStrategies = errors.New("no strategies directory in source")
+
+// fsSource is a pattern source whose content is loaded as a file system.
+// The file system is loaded once, on first access.
+type fsSource struct {
+	// load returns the content of the source and the commit it was loaded from, if any
+	load func(ctx context.Context) (fs.FS, string, error)
+
+	once     sync.Once
+	root     fs.FS
+	fsys     fs.FS
+	revision string
+	err      error
+}
+
+func (s *fsSource) open(ctx context.Context) (fs.FS, error) {
+	s.once.Do(func() {
+		s.root, s.revision, s.err = s.load(ctx)
+		if s.err == nil {
+			s.fsys, s.err = patternsRoot(s.root)
+		}
+	})
+	return s.fsys, s.err
+}
+
+// Revision implements PatternSource.
+func (s *fsSource) Revision(ctx context.Context) (string, error) {
+	if _, err := s.open(ctx); err != nil {
+		return "", err
+	}
+	return s.revision, nil
+}
+
+// Patterns implements PatternSource.
+func (s *fsSource) Patterns(ctx context.Context) ([]Pattern, error) {
+	fsys, err := s.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := config.ListPatternsInFS(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	patterns := make([]Pattern, 0, len(names))
+	for _, name := range names {
+		content, err := fs.ReadFile(fsys, path.Join(name, config.PatternFile))
+		if err != nil {
+			return nil, err
+		}
+		// empty prompts are not patterns, as with GitHub sources
+		if len(content) == 0 {
+			continue
+		}
+		patterns = append(patterns, Pattern{Name: name, SHA: github.BlobSHA(content)})
+	}
+
+	return patterns, nil
+}
+
+// Download implements PatternSource.
+func (s *fsSource) Download(ctx context.Context, name string) (string, error) {
+	if err := config.ValidatePatternName(name); err != nil {
+		return "", err
+	}
+
+	fsys, err := s.open(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	content, err := fs.ReadFile(fsys, path.Join(name, config.PatternFile))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("pattern %q not found", name)
+		}
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// Strategies implements StrategySource.
+func (s *fsSource) Strategies(ctx context.Context) ([]Strategy, error) {
+	if _, err := s.open(ctx); err != nil {
+		return nil, err
+	}
+
+	entries, err := fs.ReadDir(s.root, strategiesDir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, ErrNoStrategies
+		}
+		return nil, err
+	}
+
+	strategies := make([]Strategy, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || !config.IsStrategyFile(e.Name()) {
+			continue
+		}
+		strategies = append(strategies, Strategy{
+			Name: strings.TrimSuffix(e.Name(), path.Ext(e.Name())),
+			File: e.Name(),
+		})
+	}
+
+	return strategies, nil
+}
+
+// DownloadStrategy implements StrategySource.
+func (s *fsSource) DownloadStrategy(ctx context.Context, file string) (string, error) {
+	if err := validateStrategyFile(file); err != nil {
+		return "", err
+	}
+
+	if _, err := s.open(ctx); err != nil {
+		return "", err
+	}
+
+	content, err := fs.ReadFile(s.root, path.Join(strategiesDir, file))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", fmt.Errorf("strategy file %q not found", file)
+		}
+		return "", err
+	}
+
+	return string(content), nil
+}
+
+// validateStrategyFile checks that a strategy file is directly in the strategies directory.
+func validateStrategyFile(file string) error {
+	if !fs.ValidPath(file) || strings.Contains(file, "/") || !config.IsStrategyFile(file) {
+		return fmt.Errorf("invalid strategy file %q", file)
+	}
+	return nil
+}
+
+// patternsRoot returns the top-level patterns directory of a file system if it exists,
+// or the file system itself otherwise.
+func patternsRoot(fsys fs.FS) (fs.FS, error) {
+	info, err := fs.Stat(fsys, patternsDir)
+	if err != nil || !info.IsDir() {
+		return fsys, nil // nolint: nilerr
+	}
+	return fs.Sub(fsys, patternsDir)
+}
@@ -0,0 +1,108 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/util/log"
+)
+
+// newGitSource creates a source from a git repository, at a branch, tag or commit SHA,
+// or at the default branch if ref is empty.
+// The repository is shallow-fetched into the user cache directory,
+// and the clone is updated on later uses.
+func newGitSource(repoURL string, ref string) *fsSource {
+	return &fsSource{
+		load: func(ctx context.Context) (fs.FS, string, error) {
+			dir, err := cacheDir("remotes", repoURL)
+			if err != nil {
+				return nil, "", err
+			}
+
+			revision, err := fetchClone(ctx, repoURL, ref, dir)
+			if err != nil {
+				return nil, "", err
+			}
+
+			return os.DirFS(dir), revision, nil
+		},
+	}
+}
+
+// cacheDir returns the directory of the user cache where a remote source is kept,
+// e.g. the clone of a git repository.
+func cacheDir(kind string, location string) (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(location))
+	return filepath.Join(dir, config.App, kind, hex.EncodeToString(sum[:8])), nil
+}
+
+// fetchClone checks out a ref of a git repository into dir, creating the clone if needed.
+// It returns the SHA of the checked out commit.
+func fetchClone(ctx context.Context, repoURL string, ref string, dir string) (string, error) {
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err != nil {
+		// a leftover of an interrupted clone would make git refuse to init
+		if err := os.RemoveAll(dir); err != nil {
+			return "", err
+		}
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+		if _, err := runGit(ctx, "-C", dir, "init", "--quiet"); err != nil {
+			return "", err
+		}
+		if _, err := runGit(ctx, "-C", dir, "remote", "add", "origin", repoURL); err != nil {
+			return "", err
+		}
+	}
+
+	if ref == "" {
+		ref = "HEAD"
+	}
+
+	// fetching a single ref also works for commit SHAs, unlike `git clone --branch`
+	log.Info("Fetching git repository", "url", repoURL, "ref", ref)
+	if _, err := runGit(ctx, "-C", dir, "fetch", "--quiet", "--depth", "1", "origin", ref); err != nil {
+		return "", err
+	}
+	if _, err := runGit(ctx, "-C", dir, "reset", "--quiet", "--hard", "FETCH_HEAD"); err != nil {
+		return "", err
+	}
+
+	revision, err := runGit(ctx, "-C", dir, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return revision, nil
+}
+
+// runGit runs a git command and returns its trimmed output.
+func runGit(ctx context.Context, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", args...)
+	// never prompt for credentials, which would hang the command
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return "", fmt.Errorf("running git: %w: %s", exitErr, bytes.TrimSpace(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("running git: %w", err)
+	}
+
+	return string(bytes.TrimSpace(out)), nil
+}
@@ -0,0 +1,139 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/github"
+)
+
+// githubSource is a GitHub repository, accessed through the GitHub API.
+//
+// The ref is resolved to a commit SHA on first access, and all patterns are read from that commit,
+// so that listing and downloading patterns stay consistent even if the ref moves in between.
+type githubSource struct {
+	repo github.Repository
+	// maxAge is how long pattern listings are cached, no caching if zero
+	maxAge time.Duration
+
+	mu       sync.Mutex
+	revision string
+}
+
+func newGitHubSource(rawURL string, ref string, maxAge time.Duration) (*githubSource, error) {
+	repo, err := github.ParseRepositoryURL(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing repository URL: %w", err)
+	}
+	return &githubSource{repo: repo.WithRef(ref), maxAge: maxAge}, nil
+}
+
+// resolve returns the repository pinned to the commit its ref points to.
+func (s *githubSource) resolve(ctx context.Context) (github.Repository, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.revision == "" {
+		sha, err := s.repo.ResolveRef(ctx)
+		if err != nil {
+			return github.Repository{}, err
+		}
+		s.revision = sha
+	}
+	return s.repo.WithRef(s.revision), nil
+}
+
+// Revision implements PatternSource.
+func (s *githubSource) Revision(ctx context.Context) (string, error) {
+	repo, err := s.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+	return repo.Ref, nil
+}
+
+// Patterns implements PatternSource.
+func (s *githubSource) Patterns(ctx context.Context) ([]Pattern, error) {
+	s.mu.Lock()
+	resolved := s.revision != ""
+	s.mu.Unlock()
+
+	var blobs []github.PatternBlob
+	if resolved {
+		repo, err := s.resolve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if blobs, err = repo.GetPatterns(ctx); err != nil {
+			return nil, err
+		}
+	} else {
+		// resolving and listing at once allows the listing to be cached
+		listing, err := s.repo.ListPatterns(ctx, s.maxAge)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.revision = listing.Revision
+		s.mu.Unlock()
+		blobs = listing.Patterns
+	}
+
+	patterns := make([]Pattern, len(blobs))
+	for i, b := range blobs {
+		patterns[i] = Pattern{Name: b.Name, SHA: b.SHA}
+	}
+	return patterns, nil
+}
+
+// Download implements PatternSource.
+func (s *githubSource) Download(ctx context.Context, name string) (string, error) {
+	repo, err := s.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+	return repo.DownloadPattern(ctx, name)
+}
+
+// Strategies implements StrategySource.
+func (s *githubSource) Strategies(ctx context.Context) ([]Strategy, error) {
+	repo, err := s.resolve(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := repo.ListFiles(ctx, strategiesDir)
+	if err != nil {
+		return nil, err
+	}
+
+	strategies := make([]Strategy, 0, len(files))
+	for _, f := range files {
+		if config.IsStrategyFile(f) {
+			strategies = append(strategies, Strategy{Name: strings.TrimSuffix(f, path.Ext(f)), File: f})
+		}
+	}
+	slices.SortFunc(strategies, func(a, b Strategy) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return strategies, nil
+}
+
+// DownloadStrategy implements StrategySource.
+func (s *githubSource) DownloadStrategy(ctx context.Context, file string) (string, error) {
+	if err := validateStrategyFile(file); err != nil {
+		return "", err
+	}
+
+	repo, err := s.resolve(ctx)
+	if err != nil {
+		return "", err
+	}
+	return repo.DownloadFile(ctx, path.Join(strategiesDir, file))
+}
@@ -0,0 +1,221 @@
+// Package remote provides access to pattern libraries hosted outside the local pattern repositories,
+// such as GitHub repositories, git repositories, archives and local directories.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Pattern is a pattern available in a remote source.
+type Pattern struct {
+	// Name is the name of the pattern, e.g. "summarize" or "team/review/security"
+	Name string
+	// SHA is the git blob SHA of the pattern's system.md
+	SHA string
+}
+
+// PatternSource is a remote library of patterns.
+//
+// Sources follow the layout of pattern repositories: a pattern is a directory
+// containing a system.md file, under a top-level `patterns` directory if there is one.
+type PatternSource interface {
+	// Patterns lists the patterns of the source, sorted by name.
+	Patterns(ctx context.Context) ([]Pattern, error)
+	// Download returns the content of a pattern's system.md.
+	Download(ctx context.Context, name string) (string, error)
+	// Revision returns the SHA of the commit patterns are read from,
+	// or an empty string if the source is not versioned.
+	Revision(ctx context.Context) (string, error)
+}
+
+// Strategy is a strategy available in a remote source.
+type Strategy struct {
+	// Name is the name of the strategy, e.g. "cot"
+	Name string
+	// File is the name of the strategy file, e.g. "cot.json"
+	File string
+}
+
+// StrategySource is a remote library of strategies, found in a top-level `strategies` directory.
+// All pattern sources returned by Open are also strategy sources.
+type StrategySource interface {
+	// Strategies lists the strategies of the source, sorted by name.
+	Strategies(ctx context.Context) ([]Strategy, error)
+	// DownloadStrategy returns the content of a strategy file.
+	DownloadStrategy(ctx context.Context, file string) (string, error)
+}
+
+// Kind is the kind of a pattern source.
+type Kind string
+
+const (
+	// KindGitHub is a GitHub repository, accessed through the GitHub API.
+	KindGitHub Kind = "github"
+	// KindGit is any git repository, accessed through a local clone.
+	KindGit Kind = "git"
+	// KindArchive is a tar or zip archive, local or downloaded over HTTP.
+	KindArchive Kind = "archive"
+	// KindDir is a local directory.
+	KindDir Kind = "dir"
+)
+
+// scpLike matches scp-like git URLs, such as git@gitlab.com:owner/repo.git
+var scpLike = regexp.MustCompile(`^[\w.-]+@[\w.-]+:`)
+
+// archiveExts are the supported archive file extensions.
+var archiveExts = []string{".tar.gz", ".tgz", ".tar", ".zip"}
+
+// Detect determines the kind of source a remote URL points to,
+// and returns the location to access it from.
+//
+// The kind is selected by URL scheme:
+//   - https://github.com/{owner}/{repo}: GitHub
+//   - git://, ssh://, git+https://, git+file://, user@host:path, or http(s) URLs of other hosts: git
+//   - URLs and paths ending with .tar.gz, .tgz, .tar or .zip: archive
+//   - file:// URLs and paths: local directory
+func Detect(raw string) (Kind, string, error) {
+	if raw == "" {
+		return "", "", errors.New("remote is empty")
+	}
+
+	if scpLike.MatchString(raw) {
+		return KindGit, raw, nil
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", "", fmt.Errorf("parsing remote %q: %w", raw, err)
+	}
+
+	switch scheme := strings.ToLower(u.Scheme); scheme {
+	case "git", "ssh":
+		return KindGit, raw, nil
+	case "git+https", "git+http", "git+ssh", "git+file":
+		return KindGit, raw[len("git+"):], nil
+	case "http", "https":
+		if isArchive(u.Path) {
+			return KindArchive, raw, nil
+		}
+		if u.Hostname() == "github.com" && !strings.HasSuffix(u.Path, ".git") {
+			return KindGitHub, raw, nil
+		}
+		return KindGit, raw, nil
+	case "file":
+		if isArchive(u.Path) {
+			return KindArchive, u.Path, nil
+		}
+		return KindDir, u.Path, nil
+	case "":
+		if isArchive(raw) {
+			return KindArchive, raw, nil
+		}
+		return KindDir, raw, nil
+	default:
+		return "", "", fmt.Errorf("unsupported remote scheme %q", u.Scheme)
+	}
+}
+
+// SplitRef splits a remote URL pinned to a branch, tag or commit SHA,
+// such as https://github.com/danielmiessler/fabric@v1.4.0, into the URL and the ref.
+// The ref is empty if the remote is not pinned.
+//
+// The ref follows the last @ after the host, so it may contain slashes, e.g. @release/v1.
+// Local paths and archives are never pinned, so an @ in them is left as is.
+func SplitRef(raw string) (string, string) {
+	if isArchive(raw) {
+		return raw, ""
+	}
+
+	// the path starts after the host, an @ before it is part of the URL, e.g. git@github.com:owner/repo
+	var start int
+	if loc := scpLike.FindStringIndex(raw); loc != nil {
+		start = loc[1]
+	} else {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme == "" || strings.EqualFold(u.Scheme, "file") {
+			return raw, ""
+		}
+		_, rest, ok := strings.Cut(raw, "://")
+		if !ok {
+			return raw, ""
+		}
+		slash := strings.Index(rest, "/")
+		if slash == -1 {
+			return raw, ""
+		}
+		start = len(raw) - len(rest) + slash
+	}
+
+	i := strings.LastIndex(raw[start:], "@")
+	if i == -1 {
+		return raw, ""
+	}
+	i += start
+	return raw[:i], raw[i+1:]
+}
+
+// Option configures a pattern source.
+type Option func(*options)
+
+type options struct {
+	listingMaxAge time.Duration
+}
+
+// WithListingCache caches pattern listings of GitHub sources locally for maxAge,
+// to avoid hitting the rate limit when listing often, e.g. for shell completion.
+// Listings are always fresh by default.
+func WithListingCache(maxAge time.Duration) Option {
+	return func(o *options) {
+		o.listingMaxAge = maxAge
+	}
+}
+
+// Open returns the pattern source a remote URL points to.
+// GitHub and git remotes can be pinned to a ref with an @ suffix, see SplitRef.
+// Nothing is fetched until patterns are listed or downloaded.
+func Open(raw string, opts ...Option) (PatternSource, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	raw, ref := SplitRef(raw)
+
+	kind, location, err := Detect(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	if ref != "" && kind != KindGitHub && kind != KindGit {
+		return nil, fmt.Errorf("%s remotes cannot be pinned to a ref", kind)
+	}
+
+	switch kind {
+	case KindGitHub:
+		return newGitHubSource(location, ref, o.listingMaxAge)
+	case KindGit:
+		return newGitSource(location, ref), nil
+	case KindArchive:
+		return newArchiveSource(location), nil
+	case KindDir:
+		return newDirSource(location), nil
+	default:
+		return nil, fmt.Errorf("unexpected: unknown source kind %q", kind)
+	}
+}
+
+func isArchive(p string) bool {
+	p = strings.ToLower(p)
+	for _, ext := range archiveExts {
+		if strings.HasSuffix(p, ext) {
+			return true
+		}
+	}
+	return false
+}
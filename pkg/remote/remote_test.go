@@ -0,0 +1,431 @@
+package remote
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/nt54hamnghi/seaq/pkg/github"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	testCases := []struct {
+		name         string
+		raw          string
+		wantKind     Kind
+		wantLocation string
+		wantErr      bool
+	}{
+		{
+			name:         "github",
+			raw:          "https://github.com/danielmiessler/fabric",
+			wantKind:     KindGitHub,
+			wantLocation: "https://github.com/danielmiessler/fabric",
+		},
+		{
+			name:         "github git URL",
+			raw:          "https://github.com/danielmiessler/fabric.git",
+			wantKind:     KindGit,
+			wantLocation: "https://github.com/danielmiessler/fabric.git",
+		},
+		{
+			name:         "gitlab",
+			raw:          "https://gitlab.com/owner/patterns",
+			wantKind:     KindGit,
+			wantLocation: "https://gitlab.com/owner/patterns",
+		},
+		{
+			name:         "scp-like",
+			raw:          "git@gitea.example.com:owner/patterns.git",
+			wantKind:     KindGit,
+			wantLocation: "git@gitea.example.com:owner/patterns.git",
+		},
+		{
+			name:         "ssh",
+			raw:          "ssh://git@example.com/owner/patterns.git",
+			wantKind:     KindGit,
+			wantLocation: "ssh://git@example.com/owner/patterns.git",
+		},
+		{
+			name:         "git+file",
+			raw:          "git+file:///srv/git/patterns",
+			wantKind:     KindGit,
+			wantLocation: "file:///srv/git/patterns",
+		},
+		{
+			name:         "http archive",
+			raw:          "https://example.com/releases/patterns.tar.gz",
+			wantKind:     KindArchive,
+			wantLocation: "https://example.com/releases/patterns.tar.gz",
+		},
+		{
+			name:         "file archive",
+			raw:          "file:///tmp/patterns.zip",
+			wantKind:     KindArchive,
+			wantLocation: "/tmp/patterns.zip",
+		},
+		{
+			name:         "archive path",
+			raw:          "./patterns.tgz",
+			wantKind:     KindArchive,
+			wantLocation: "./patterns.tgz",
+		},
+		{
+			name:         "file directory",
+			raw:          "file:///mnt/shared/patterns",
+			wantKind:     KindDir,
+			wantLocation: "/mnt/shared/patterns",
+		},
+		{
+			name:         "directory path",
+			raw:          "/mnt/shared/patterns",
+			wantKind:     KindDir,
+			wantLocation: "/mnt/shared/patterns",
+		},
+		{name: "empty", raw: "", wantErr: true},
+		{name: "unsupported scheme", raw: "ftp://example.com/patterns", wantErr: true},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			kind, location, err := Detect(tt.raw)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.wantKind, kind)
+			r.Equal(tt.wantLocation, location)
+		})
+	}
+}
+
+func TestSplitRef(t *testing.T) {
+	testCases := []struct {
+		name    string
+		raw     string
+		wantURL string
+		wantRef string
+	}{
+		{
+			name:    "not pinned",
+			raw:     "https://github.com/danielmiessler/fabric",
+			wantURL: "https://github.com/danielmiessler/fabric",
+		},
+		{
+			name:    "tag",
+			raw:     "https://github.com/danielmiessler/fabric@v1.4.0",
+			wantURL: "https://github.com/danielmiessler/fabric",
+			wantRef: "v1.4.0",
+		},
+		{
+			name:    "commit",
+			raw:     "https://github.com/danielmiessler/fabric@4f1d7b2",
+			wantURL: "https://github.com/danielmiessler/fabric",
+			wantRef: "4f1d7b2",
+		},
+		{
+			name:    "scp-like not pinned",
+			raw:     "git@gitlab.com:owner/patterns.git",
+			wantURL: "git@gitlab.com:owner/patterns.git",
+		},
+		{
+			name:    "scp-like pinned",
+			raw:     "git@gitlab.com:owner/patterns.git@main",
+			wantURL: "git@gitlab.com:owner/patterns.git",
+			wantRef: "main",
+		},
+		{
+			name:    "user info",
+			raw:     "https://user@example.com/owner/patterns",
+			wantURL: "https://user@example.com/owner/patterns",
+		},
+		{
+			name:    "user info pinned",
+			raw:     "https://user@example.com/owner/patterns@main",
+			wantURL: "https://user@example.com/owner/patterns",
+			wantRef: "main",
+		},
+		{
+			name:    "ref with a slash",
+			raw:     "https://github.com/o/r@release/v1",
+			wantURL: "https://github.com/o/r",
+			wantRef: "release/v1",
+		},
+		{
+			name:    "scp-like ref with a slash",
+			raw:     "git@gitlab.com:owner/patterns.git@feature/new-patterns",
+			wantURL: "git@gitlab.com:owner/patterns.git",
+			wantRef: "feature/new-patterns",
+		},
+		{
+			name:    "local directory",
+			raw:     "/home/user/patterns@2024",
+			wantURL: "/home/user/patterns@2024",
+		},
+		{
+			name:    "relative local directory",
+			raw:     "backup/patterns@v1",
+			wantURL: "backup/patterns@v1",
+		},
+		{
+			name:    "file URL",
+			raw:     "file:///home/user/patterns@2024",
+			wantURL: "file:///home/user/patterns@2024",
+		},
+		{
+			name:    "local archive",
+			raw:     "patterns@v1.tar.gz",
+			wantURL: "patterns@v1.tar.gz",
+		},
+		{
+			name:    "archive URL",
+			raw:     "https://example.com/releases/patterns@v1.zip",
+			wantURL: "https://example.com/releases/patterns@v1.zip",
+		},
+	}
+
+	r := require.New(t)
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			u, ref := SplitRef(tt.raw)
+			r.Equal(tt.wantURL, u)
+			r.Equal(tt.wantRef, ref)
+		})
+	}
+}
+
+func TestOpen_LocalWithAt(t *testing.T) {
+	r := require.New(t)
+
+	// an @ in a local path is part of the path, not a ref
+	dir := filepath.Join(t.TempDir(), "patterns@2024")
+	writeFiles(t, dir)
+
+	for _, raw := range []string{dir, "file://" + dir} {
+		source, err := Open(raw)
+		r.NoError(err)
+		requirePatterns(t, source)
+	}
+
+	_, err := Open("https://example.com/patterns.tar.gz@v1")
+	r.Error(err)
+}
+
+// testFiles is the content of a pattern library, as found in a repository.
+var testFiles = map[string]string{
+	"README.md":                           "# patterns",
+	"patterns/summarize/system.md":        "summarize",
+	"patterns/team/review/system.md":      "review",
+	"patterns/empty/system.md":            "",
+	"patterns/.hidden/secret/system.md":   "secret",
+	"patterns/team/review/notes/draft.md": "draft",
+	"strategies/cot.json":                 `{"prompt": "Think step by step."}`,
+	"strategies/tot.md":                   "Explore several branches.",
+	"strategies/README.txt":               "strategies",
+}
+
+func requirePatterns(t *testing.T, source PatternSource) {
+	t.Helper()
+	r := require.New(t)
+	ctx := context.Background()
+
+	got, err := source.Patterns(ctx)
+	r.NoError(err)
+	r.Equal([]Pattern{
+		{Name: "summarize", SHA: github.BlobSHA([]byte("summarize"))},
+		{Name: "team/review", SHA: github.BlobSHA([]byte("review"))},
+	}, got)
+
+	content, err := source.Download(ctx, "team/review")
+	r.NoError(err)
+	r.Equal("review", content)
+
+	_, err = source.Download(ctx, "unknown")
+	r.Error(err)
+
+	_, err = source.Download(ctx, "../secret")
+	r.Error(err)
+
+	strategies, ok := source.(StrategySource)
+	r.True(ok)
+
+	gotStrategies, err := strategies.Strategies(ctx)
+	r.NoError(err)
+	r.Equal([]Strategy{
+		{Name: "cot", File: "cot.json"},
+		{Name: "tot", File: "tot.md"},
+	}, gotStrategies)
+
+	content, err = strategies.DownloadStrategy(ctx, "tot.md")
+	r.NoError(err)
+	r.Equal("Explore several branches.", content)
+
+	_, err = strategies.DownloadStrategy(ctx, "unknown.md")
+	r.Error(err)
+
+	_, err = strategies.DownloadStrategy(ctx, "../patterns/summarize/system.md")
+	r.Error(err)
+}
+
+func writeFiles(t *testing.T, dir string) {
+	t.Helper()
+	for name, content := range testFiles {
+		p := filepath.Join(dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0o755))
+		require.NoError(t, os.WriteFile(p, []byte(content), 0o644))
+	}
+}
+
+func TestDirSource(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir)
+
+	source, err := Open(dir)
+	require.NoError(t, err)
+	requirePatterns(t, source)
+}
+
+func TestArchiveSource(t *testing.T) {
+	dir := t.TempDir()
+	r := require.New(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	// tar.gz with a single top-level directory, as GitHub release archives
+	var tgz bytes.Buffer
+	gz := gzip.NewWriter(&tgz)
+	tw := tar.NewWriter(gz)
+	for name, content := range testFiles {
+		r.NoError(tw.WriteHeader(&tar.Header{
+			Name:     "patterns-1.0.0/" + name,
+			Mode:     0o644,
+			Size:     int64(len(content)),
+			Typeflag: tar.TypeReg,
+		}))
+		_, err := tw.Write([]byte(content))
+		r.NoError(err)
+	}
+	r.NoError(tw.Close())
+	r.NoError(gz.Close())
+
+	tgzPath := filepath.Join(dir, "patterns.tar.gz")
+	r.NoError(os.WriteFile(tgzPath, tgz.Bytes(), 0o644))
+
+	// zip without top-level directory
+	var zipped bytes.Buffer
+	zw := zip.NewWriter(&zipped)
+	for name, content := range testFiles {
+		w, err := zw.Create(name)
+		r.NoError(err)
+		_, err = w.Write([]byte(content))
+		r.NoError(err)
+	}
+	r.NoError(zw.Close())
+
+	zipPath := filepath.Join(dir, "patterns.zip")
+	r.NoError(os.WriteFile(zipPath, zipped.Bytes(), 0o644))
+
+	for _, p := range []string{tgzPath, "file://" + zipPath} {
+		t.Run(filepath.Base(p), func(t *testing.T) {
+			source, err := Open(p)
+			require.NoError(t, err)
+			requirePatterns(t, source)
+		})
+	}
+
+	// a file over the limit fails the whole archive
+	var large bytes.Buffer
+	zw = zip.NewWriter(&large)
+	w, err := zw.Create("patterns/summarize/system.md")
+	r.NoError(err)
+	_, err = w.Write(bytes.Repeat([]byte("a"), maxArchiveFileSize+1))
+	r.NoError(err)
+	r.NoError(zw.Close())
+
+	largePath := filepath.Join(dir, "large.zip")
+	r.NoError(os.WriteFile(largePath, large.Bytes(), 0o644))
+
+	source, err := Open(largePath)
+	r.NoError(err)
+	_, err = source.Patterns(context.Background())
+	r.ErrorContains(err, "larger than")
+}
+
+func TestGitSource(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	r := require.New(t)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	t.Setenv("HOME", t.TempDir())
+
+	repo := t.TempDir()
+	git := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		out, err := cmd.CombinedOutput()
+		r.NoError(err, string(out))
+		return string(bytes.TrimSpace(out))
+	}
+	commit := func(msg string) {
+		git("add", ".")
+		git("-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", msg)
+	}
+
+	git("init", "--quiet")
+	writeFiles(t, repo)
+	commit("init")
+	git("tag", "v1")
+	first := git("rev-parse", "HEAD")
+
+	// a later upstream change must not affect pinned sources
+	r.NoError(os.WriteFile(filepath.Join(repo, "patterns", "summarize", "system.md"), []byte("changed"), 0o644))
+	commit("change")
+	latest := git("rev-parse", "HEAD")
+
+	ctx := context.Background()
+
+	testCases := []struct {
+		name         string
+		raw          string
+		wantRevision string
+		wantContent  string
+	}{
+		{name: "default branch", raw: "git+file://" + repo, wantRevision: latest, wantContent: "changed"},
+		{name: "tag", raw: "git+file://" + repo + "@v1", wantRevision: first, wantContent: "summarize"},
+		{name: "commit", raw: "git+file://" + repo + "@" + first, wantRevision: first, wantContent: "summarize"},
+		// reuses and updates the clone
+		{name: "default branch again", raw: "git+file://" + repo, wantRevision: latest, wantContent: "changed"},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(*testing.T) {
+			source, err := Open(tt.raw)
+			r.NoError(err)
+
+			content, err := source.Download(ctx, "summarize")
+			r.NoError(err)
+			r.Equal(tt.wantContent, content)
+
+			revision, err := source.Revision(ctx)
+			r.NoError(err)
+			r.Equal(tt.wantRevision, revision)
+		})
+	}
+
+	source, err := Open("git+file://" + repo + "@v1")
+	r.NoError(err)
+	requirePatterns(t, source)
+}
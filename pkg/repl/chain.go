@@ -6,6 +6,7 @@ import (
 	"fmt"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
 	"github.com/tmc/langchaingo/chains"
 	"github.com/tmc/langchaingo/llms"
 	"github.com/tmc/langchaingo/memory"
@@ -33,6 +34,9 @@ var ErrNilStream = errors.New("unexpected nil stream")
 // streamContentMsg contains the streaming content chunk from a chat operation
 type streamContentMsg string
 
+// streamThinkingMsg contains the streaming reasoning chunk from a chat operation
+type streamThinkingMsg string
+
 // streamEndMsg signals the end of a chat operation
 type streamEndMsg struct{}
 
@@ -43,20 +47,40 @@ type chain struct {
 	chains.ConversationalRetrievalQA
 	// buffer stores the accumulated response content
 	buffer string
+	// thinking stores the accumulated reasoning content
+	thinking string
 	// stream is used to send streaming chunks of the response
 	stream chan tea.Msg
 }
 
 // newChain creates a new conversational QA Chain
 // with the given language model and vector store.
-func newChain(model llms.Model, store vectorstores.VectorStore) *chain {
+// If thinkingBudget is positive, the model's reasoning mode is enabled
+// for answering questions and reasoning is streamed as streamThinkingMsg.
+// If strategy is not empty, it's layered on top of the answering prompt.
+func newChain(model llms.Model, store vectorstores.VectorStore, thinkingBudget int, strategy string) *chain {
+	c := &chain{
+		buffer: "",
+		stream: make(chan tea.Msg),
+	}
+
+	answerModel := model
+	if thinkingBudget > 0 {
+		answerModel = llm.WithThinking(model, thinkingBudget, c.sendThinking)
+	}
+
 	promptTemplate := prompts.NewPromptTemplate(
 		defaultTemplate,
 		[]string{"input_documents", "question"},
 	)
+	if strategy != "" {
+		// passed as a value rather than inlined, so it's never parsed as a template
+		promptTemplate.Template = "{{.strategy}}\n" + defaultTemplate
+		promptTemplate.PartialVariables = map[string]any{"strategy": strategy}
+	}
 
 	combineChain := chains.NewStuffDocuments(
-		chains.NewLLMChain(model, promptTemplate),
+		chains.NewLLMChain(answerModel, promptTemplate),
 	)
 
 	condenseChain := chains.LoadCondenseQuestionGenerator(model)
@@ -69,15 +93,24 @@ func newChain(model llms.Model, store vectorstores.VectorStore) *chain {
 		memory.WithChatHistory(memory.NewChatMessageHistory()),
 	)
 
-	return &chain{
-		ConversationalRetrievalQA: chains.NewConversationalRetrievalQA(
-			combineChain,
-			condenseChain,
-			retriever,
-			memory,
-		),
-		buffer: "",
-		stream: make(chan tea.Msg),
+	c.ConversationalRetrievalQA = chains.NewConversationalRetrievalQA(
+		combineChain,
+		condenseChain,
+		retriever,
+		memory,
+	)
+
+	return c
+}
+
+// sendThinking sends a reasoning chunk to the stream.
+func (c *chain) sendThinking(ctx context.Context, chunk []byte) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		c.stream <- streamThinkingMsg(chunk)
+		return nil
 	}
 }
 
@@ -103,8 +136,11 @@ func (c *chain) awaitNext() tea.Cmd {
 		}
 
 		output := <-c.stream
-		if msg, ok := output.(streamContentMsg); ok {
+		switch msg := output.(type) {
+		case streamContentMsg:
 			c.buffer += string(msg)
+		case streamThinkingMsg:
+			c.thinking += string(msg)
 		}
 		return output
 	}
@@ -2,6 +2,7 @@ package renderer
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/glamour"
 	"github.com/charmbracelet/glamour/styles"
@@ -39,6 +40,7 @@ type Renderer struct {
 	warning               lipgloss.Style
 	error                 lipgloss.Style
 	help                  lipgloss.Style
+	thinking              lipgloss.Style
 }
 
 func New(options ...glamour.TermRendererOption) *Renderer {
@@ -53,6 +55,12 @@ func New(options ...glamour.TermRendererOption) *Renderer {
 		warning:      lipgloss.NewStyle().Foreground(warningColor),
 		error:        lipgloss.NewStyle().Foreground(errorColor),
 		help:         lipgloss.NewStyle().Foreground(helpColor).Italic(true),
+		thinking: lipgloss.NewStyle().
+			Foreground(helpColor).
+			Faint(true).
+			Border(lipgloss.NormalBorder(), false, false, false, true).
+			BorderForeground(helpColor).
+			PaddingLeft(1),
 	}
 }
 
@@ -84,6 +92,16 @@ func (r *Renderer) RenderError(msg string) string {
 	return renderMessage(msg, r.error, errorPrefix)
 }
 
+// RenderThinking renders reasoning content as a dim panel wrapped to width.
+// The content is not wrapped if width is not positive.
+func (r *Renderer) RenderThinking(msg string, width int) string {
+	style := r.thinking
+	if width > 0 {
+		style = style.Width(width)
+	}
+	return style.Render(strings.TrimSpace(msg)) + "\n"
+}
+
 const helpMessage = `**Commands:**
 - /?, /help            : Show help message
 - /s, /save <txt|json> : Save your current conversation
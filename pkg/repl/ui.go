@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -46,8 +47,11 @@ type REPL struct {
 	cancelFunc   context.CancelFunc
 
 	// other options
-	noStream  bool
-	chainOpts []chains.ChainCallOption
+	noStream       bool
+	thinkingBudget int
+	thinkingWriter io.Writer
+	strategy       string
+	chainOpts      []chains.ChainCallOption
 }
 
 type Option func(*REPL) error
@@ -79,6 +83,29 @@ func WithNoStream(noStream bool) Option {
 	}
 }
 
+// WithThinking enables the model's reasoning mode with the given token budget.
+// Reasoning is shown in a dim panel above each answer and,
+// if w is not nil, also written to w.
+func WithThinking(budget int, w io.Writer) Option {
+	return func(r *REPL) error {
+		if budget <= 0 {
+			return errors.New("thinking budget must be positive")
+		}
+		r.thinkingBudget = budget
+		r.thinkingWriter = w
+		return nil
+	}
+}
+
+// WithStrategy layers a strategy's prompt, such as chain-of-thought,
+// on top of the prompt answering questions.
+func WithStrategy(prompt string) Option {
+	return func(r *REPL) error {
+		r.strategy = strings.TrimSpace(prompt)
+		return nil
+	}
+}
+
 func defaultREPL() (*REPL, error) {
 	store, err := rag.NewChromaStore()
 	if err != nil {
@@ -131,7 +158,7 @@ func New(name string, docs []schema.Document, opts ...Option) (*REPL, error) {
 	}
 
 	// initialize the chain
-	r.chain = newChain(r.model, r.store)
+	r.chain = newChain(r.model, r.store, r.thinkingBudget, r.strategy)
 
 	return r, nil
 }
@@ -168,6 +195,7 @@ func (r *REPL) cancel() tea.Cmd {
 	r.cancelFunc()
 	r.cancelFunc = nil
 	r.chain.buffer = ""
+	r.chain.thinking = ""
 	return nil
 }
 
@@ -299,6 +327,11 @@ func (r *REPL) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			r.spinner.Stop()
 		}
 		cmds = append(cmds, r.chain.awaitNext())
+	case streamThinkingMsg:
+		if !r.noStream {
+			r.spinner.Stop()
+		}
+		cmds = append(cmds, r.chain.awaitNext())
 	case streamEndMsg:
 		// In non-streaming mode, we only stop the spinner once we have the complete response
 		// In streaming mode, spinner was already stopped when content started arriving
@@ -309,6 +342,15 @@ func (r *REPL) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		output := r.chain.buffer
 		cmds := []tea.Cmd{}
 
+		if thinking := r.chain.thinking; thinking != "" {
+			if r.thinkingWriter != nil {
+				if _, err := io.WriteString(r.thinkingWriter, thinking+"\n\n"); err != nil {
+					cmds = append(cmds, tea.Println(r.renderer.RenderError(err.Error())))
+				}
+			}
+			cmds = append(cmds, tea.Println(r.renderer.RenderThinking(thinking, r.prompt.Width)))
+		}
+
 		if output != "" {
 			// ignore error because output is non-empty and role is always assistant
 			_ = r.conversation.addMessage(output, roleAssistant)
@@ -321,6 +363,7 @@ func (r *REPL) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		cmds = append(cmds, r.prompt.Focus())
 
 		r.chain.buffer = ""
+		r.chain.thinking = ""
 		return r, tea.Sequence(cmds...)
 	case error:
 		r.spinner.Stop()
@@ -362,8 +405,12 @@ func (r *REPL) View() string {
 
 	// When streaming is enabled (noStream is false),
 	// continuously update the view with the LLM's response as it's generated.
-	if !r.noStream && len(r.chain.buffer) != 0 {
-		return r.renderer.RenderContent(r.chain.buffer)
+	if !r.noStream && (len(r.chain.buffer) != 0 || len(r.chain.thinking) != 0) {
+		var view string
+		if len(r.chain.thinking) != 0 {
+			view = r.renderer.RenderThinking(r.chain.thinking, r.prompt.Width)
+		}
+		return view + r.renderer.RenderContent(r.chain.buffer)
 	}
 
 	return r.prompt.View() + "\n"
@@ -0,0 +1,247 @@
+// Package suggest ranks patterns by how well they suit an input.
+package suggest
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/tmc/langchaingo/schema"
+)
+
+// Kinds of input, matched against the input expected by patterns, see config.PatternMeta.
+const (
+	KindText       = "text"
+	KindCode       = "code"
+	KindTranscript = "transcript"
+)
+
+// Profile describes an input.
+type Profile struct {
+	// Kind is the kind of input: text, code or transcript.
+	Kind string
+	// Source is the source the input was fetched from, e.g. "youtube", empty if unknown.
+	Source string
+	// Language is the ISO 639-1 code of the input's language, empty if unknown.
+	Language string
+	// Words is the number of words of the input.
+	Words int
+}
+
+// String describes a profile in a few words, e.g. "transcript, 1200 words, from youtube, in en".
+func (p Profile) String() string {
+	parts := []string{p.Kind, fmt.Sprintf("%d words", p.Words)}
+	if p.Source != "" {
+		parts = append(parts, "from "+p.Source)
+	}
+	if p.Language != "" {
+		parts = append(parts, "in "+p.Language)
+	}
+	return strings.Join(parts, ", ")
+}
+
+var (
+	// timestampRe matches lines starting with a timestamp, e.g. "00:42" or "[1:02:03]".
+	timestampRe = regexp.MustCompile(`^\[?(\d{1,2}:)?\d{1,2}:\d{2}`)
+	// codeLineRe matches lines that are typical of source code.
+	codeLineRe = regexp.MustCompile(`[{};]$|^(func|def|class|import|package|return|const|let|var|#include|public|private)\b`)
+)
+
+// Inspect profiles an input and returns its text.
+//
+// The output of `seaq fetch --json` is recognized, in which case the source is
+// inferred from the metadata of the documents, and the text is their joined content.
+func Inspect(input string) (Profile, string) {
+	var p Profile
+
+	text := input
+	if docs, ok := parseDocuments(input); ok {
+		p.Source = detectSource(docs)
+		contents := make([]string, 0, len(docs))
+		for _, d := range docs {
+			contents = append(contents, d.PageContent)
+		}
+		text = strings.Join(contents, "\n")
+	}
+
+	p.Words = len(strings.Fields(text))
+	p.Language = detectLanguage(text)
+	p.Kind = detectKind(text, p.Source)
+
+	return p, text
+}
+
+// parseDocuments parses the output of `seaq fetch --json`.
+func parseDocuments(input string) ([]schema.Document, bool) {
+	trimmed := strings.TrimSpace(input)
+	if !strings.HasPrefix(trimmed, "[") {
+		return nil, false
+	}
+
+	var docs []schema.Document
+	if err := json.Unmarshal([]byte(trimmed), &docs); err != nil || len(docs) == 0 {
+		return nil, false
+	}
+	return docs, true
+}
+
+// detectSource infers the source of fetched documents from the keys of their metadata.
+func detectSource(docs []schema.Document) string {
+	for _, d := range docs {
+		has := func(key string) bool {
+			_, ok := d.Metadata[key]
+			return ok
+		}
+
+		switch {
+		case has("startMs"), has("videoId"):
+			return "youtube"
+		case has("StartAt"):
+			// captions of Udemy lectures, subtitle files and transcribed media
+			return "captions"
+		case has("subreddit"):
+			return "reddit"
+		case has("Hashtags"):
+			return "x"
+		case has("totalPages"):
+			return "pdf"
+		case has("language"):
+			return "repo"
+		case has("url"):
+			return "page"
+		}
+	}
+	return ""
+}
+
+// detectKind tells transcripts and code from other text,
+// by the share of lines starting with a timestamp or looking like code.
+func detectKind(text string, source string) string {
+	if source == "youtube" || source == "captions" {
+		return KindTranscript
+	}
+	if source == "repo" {
+		return KindCode
+	}
+	if strings.HasPrefix(text, "WEBVTT") || strings.Contains(text, " --> ") {
+		return KindTranscript
+	}
+
+	var lines, timestamps, code int
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		lines++
+		if timestampRe.MatchString(line) {
+			timestamps++
+		}
+		if codeLineRe.MatchString(line) {
+			code++
+		}
+	}
+
+	switch {
+	case lines == 0:
+		return KindText
+	case timestamps*10 >= lines*3:
+		return KindTranscript
+	case code*10 >= lines*3:
+		return KindCode
+	default:
+		return KindText
+	}
+}
+
+// scriptLanguages maps scripts to the language they most likely stand for.
+var scriptLanguages = []struct {
+	table    *unicode.RangeTable
+	language string
+}{
+	{unicode.Hiragana, "ja"},
+	{unicode.Katakana, "ja"},
+	{unicode.Hangul, "ko"},
+	{unicode.Han, "zh"},
+	{unicode.Cyrillic, "ru"},
+	{unicode.Arabic, "ar"},
+	{unicode.Hebrew, "he"},
+	{unicode.Greek, "el"},
+	{unicode.Devanagari, "hi"},
+	{unicode.Thai, "th"},
+}
+
+// stopwords are frequent words of languages written in the Latin script.
+var stopwords = map[string][]string{
+	"en": {"the", "and", "is", "of", "to", "in", "that", "it", "with", "for"},
+	"es": {"el", "la", "de", "que", "y", "en", "los", "las", "por", "con"},
+	"fr": {"le", "la", "les", "de", "et", "des", "est", "que", "une", "pour"},
+	"de": {"der", "die", "und", "das", "ist", "nicht", "mit", "ein", "zu", "den"},
+	"pt": {"o", "a", "de", "que", "e", "do", "da", "em", "um", "não"},
+	"it": {"il", "di", "che", "e", "la", "per", "un", "non", "sono", "una"},
+}
+
+// detectLanguage guesses the language of a text, from its script,
+// or from its most frequent stopwords for the Latin script.
+// It returns an empty string if the text is too short to tell.
+func detectLanguage(text string) string {
+	// a sample is enough, and keeps long inputs cheap
+	if len(text) > 10000 {
+		text = text[:10000]
+	}
+
+	scripts := make(map[string]int)
+	letters := 0
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters++
+		for _, s := range scriptLanguages {
+			if unicode.Is(s.table, r) {
+				scripts[s.language]++
+				break
+			}
+		}
+	}
+	if letters == 0 {
+		return ""
+	}
+
+	// Japanese is written with kanji as well as kana
+	if scripts["ja"] > 0 && scripts["ja"]+scripts["zh"] > letters/2 {
+		return "ja"
+	}
+	for lang, n := range scripts {
+		if n > letters/2 {
+			return lang
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, w := range strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	}) {
+		for lang, words := range stopwords {
+			for _, sw := range words {
+				if w == sw {
+					counts[lang]++
+				}
+			}
+		}
+	}
+
+	best, bestCount := "", 0
+	for lang, n := range counts {
+		if n > bestCount || (n == bestCount && lang < best) {
+			best, bestCount = lang, n
+		}
+	}
+	// too few stopwords to tell
+	if bestCount < 3 {
+		return ""
+	}
+	return best
+}
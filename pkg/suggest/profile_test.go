@@ -0,0 +1,133 @@
+package suggest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestInspect(t *testing.T) {
+	testCases := []struct {
+		name  string
+		input string
+		want  Profile
+		text  string
+	}{
+		{
+			name:  "youtube",
+			input: `[{"pageContent":"so today we talk about the ranking of the patterns and the reasons","metadata":{"startMs":0}}]`,
+			want:  Profile{Kind: KindTranscript, Source: "youtube", Language: "en", Words: 13},
+			text:  "so today we talk about the ranking of the patterns and the reasons",
+		},
+		{
+			name:  "captions",
+			input: `[{"pageContent":"welcome back","metadata":{"StartAt":"00:00:01.000","EndAt":"00:00:02.000"}}]`,
+			want:  Profile{Kind: KindTranscript, Source: "captions", Words: 2},
+			text:  "welcome back",
+		},
+		{
+			name: "reddit",
+			input: `[
+				{"pageContent":"first post","metadata":{"subreddit":"golang"}},
+				{"pageContent":"second post","metadata":{"subreddit":"golang"}}
+			]`,
+			want: Profile{Kind: KindText, Source: "reddit", Words: 4},
+			text: "first post\nsecond post",
+		},
+		{
+			name:  "pdf",
+			input: `[{"pageContent":"page one","metadata":{"file":"report.pdf","page":1,"totalPages":2}}]`,
+			want:  Profile{Kind: KindText, Source: "pdf", Words: 2},
+			text:  "page one",
+		},
+		{
+			name:  "repo",
+			input: `[{"pageContent":"## main.go\n\nfunc main() {}","metadata":{"path":"main.go","language":"Go","tokens":8}}]`,
+			want:  Profile{Kind: KindCode, Source: "repo", Words: 5},
+			text:  "## main.go\n\nfunc main() {}",
+		},
+		{
+			name:  "timestamps",
+			input: "00:01 hello there\n00:05 general kenobi\n[1:02:03] you are a bold one",
+			want:  Profile{Kind: KindTranscript, Words: 12},
+		},
+		{
+			name:  "webvtt",
+			input: "WEBVTT\n\n00:00:01.000 --> 00:00:02.000\nhello",
+			want:  Profile{Kind: KindTranscript, Words: 5},
+		},
+		{
+			name:  "code",
+			input: "package main\n\nfunc main() {\n\tfmt.Println(\"hello\")\n}",
+			want:  Profile{Kind: KindCode, Words: 7},
+		},
+		{
+			name:  "not json",
+			input: "[draft] the plan is to ship it with the next release, and to test it in the meantime",
+			want:  Profile{Kind: KindText, Language: "en", Words: 18},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  Profile{Kind: KindText},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			p, text := Inspect(tt.input)
+			r.Equal(tt.want, p)
+
+			if tt.text == "" {
+				tt.text = tt.input
+			}
+			r.Equal(tt.text, text)
+		})
+	}
+}
+
+func TestDetectLanguage(t *testing.T) {
+	testCases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{name: "english", text: "The cat is on the mat and it is happy with the sun", want: "en"},
+		{name: "french", text: "Le chat est sur le tapis et les enfants sont dans la maison pour une heure", want: "fr"},
+		{name: "spanish", text: "El perro de la casa y los gatos de las vecinas están en el jardín con la niña", want: "es"},
+		{name: "russian", text: "Привет, как дела?", want: "ru"},
+		{name: "japanese", text: "今日はいい天気ですね", want: "ja"},
+		{name: "chinese", text: "今天天气很好", want: "zh"},
+		{name: "too short", text: "hello world", want: ""},
+		{name: "no letters", text: "1 + 2 = 3", want: ""},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, detectLanguage(tt.text))
+		})
+	}
+}
+
+func TestProfile_String(t *testing.T) {
+	r := require.New(t)
+
+	p := Profile{Kind: KindTranscript, Source: "youtube", Language: "en", Words: 1200}
+	r.Equal("transcript, 1200 words, from youtube, in en", p.String())
+
+	p = Profile{Kind: KindText, Words: 3}
+	r.Equal("text, 3 words", p.String())
+}
+
+func TestExcerpt(t *testing.T) {
+	r := require.New(t)
+
+	r.Equal("short", excerpt("short"))
+
+	// a multi-byte character straddling the limit is left out
+	input := strings.Repeat("a", excerptLength-1) + "é"
+	r.Equal(strings.Repeat("a", excerptLength-1), excerpt(input))
+}
@@ -0,0 +1,315 @@
+package suggest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/nt54hamnghi/seaq/pkg/llm"
+	"github.com/tmc/langchaingo/embeddings"
+	"github.com/tmc/langchaingo/llms"
+)
+
+// Methods of ranking patterns.
+const (
+	// MethodClassifier asks a model to rank patterns.
+	MethodClassifier = "classifier"
+	// MethodEmbedding ranks patterns by similarity between their description and the input.
+	MethodEmbedding = "embedding"
+)
+
+// excerptLength bounds the input sent to models, the beginning of an input tells what it is about.
+const excerptLength = 4000
+
+// Candidate is a pattern that may be suggested.
+type Candidate struct {
+	Name string
+	Meta config.PatternMeta
+}
+
+// Suggestion is a pattern suggested for an input.
+type Suggestion struct {
+	Name string
+	// Score ranks suggestions, the higher the better.
+	Score float64
+	// Reasons explain why the pattern is suggested.
+	Reasons []string
+}
+
+// Ranker scores candidate patterns for an input.
+// Candidates left out of the returned suggestions are not suggested.
+type Ranker interface {
+	Rank(ctx context.Context, p Profile, input string, candidates []Candidate) ([]Suggestion, error)
+}
+
+// Suggest ranks the candidates for an input, and returns at most limit suggestions, best first.
+//
+// On top of the ranker's scores, patterns expecting the kind of input they're given,
+// or tagged with its source, are favored, and patterns expecting another kind are disfavored.
+func Suggest(
+	ctx context.Context,
+	ranker Ranker,
+	p Profile,
+	input string,
+	candidates []Candidate,
+	limit int,
+) ([]Suggestion, error) {
+	if len(candidates) == 0 {
+		return nil, errors.New("no patterns to suggest from")
+	}
+
+	suggestions, err := ranker.Rank(ctx, p, input, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, s := range suggestions {
+		j := slices.IndexFunc(candidates, func(c Candidate) bool { return c.Name == s.Name })
+		if j == -1 {
+			continue
+		}
+		meta := candidates[j].Meta
+
+		switch {
+		case meta.Input == "":
+		case strings.EqualFold(meta.Input, p.Kind):
+			s.Score += 0.1
+			s.Reasons = append(s.Reasons, fmt.Sprintf("expects %s input", meta.Input))
+		default:
+			s.Score -= 0.1
+		}
+		if p.Source != "" && meta.HasTag(p.Source) {
+			s.Score += 0.05
+			s.Reasons = append(s.Reasons, "tagged "+p.Source)
+		}
+
+		suggestions[i] = s
+	}
+
+	slices.SortStableFunc(suggestions, func(a, b Suggestion) int {
+		switch {
+		case a.Score > b.Score:
+			return -1
+		case a.Score < b.Score:
+			return 1
+		default:
+			return strings.Compare(a.Name, b.Name)
+		}
+	})
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions, nil
+}
+
+// describe summarizes a candidate in a line, for models to rank it.
+func (c Candidate) describe() string {
+	// pattern names are often descriptive, e.g. extract_wisdom
+	name := strings.NewReplacer("_", " ", "-", " ", "/", " ").Replace(c.Name)
+	if details := c.details(); details != "" {
+		return name + ": " + details
+	}
+	return name
+}
+
+// details joins the description, tags and expected input of a candidate.
+func (c Candidate) details() string {
+	var parts []string
+	if c.Meta.Description != "" {
+		parts = append(parts, c.Meta.Description)
+	}
+	if len(c.Meta.Tags) > 0 {
+		parts = append(parts, "["+strings.Join(c.Meta.Tags, ", ")+"]")
+	}
+	if c.Meta.Input != "" {
+		parts = append(parts, "(expects "+c.Meta.Input+" input)")
+	}
+	return strings.Join(parts, " ")
+}
+
+func excerpt(input string) string {
+	if len(input) <= excerptLength {
+		return input
+	}
+	// don't cut a multi-byte character
+	end := excerptLength
+	for end > 0 && !isRuneStart(input[end]) {
+		end--
+	}
+	return input[:end]
+}
+
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
+
+// EmbeddingRanker ranks patterns by cosine similarity between
+// the embedding of the input and that of the patterns' name, description and tags.
+type EmbeddingRanker struct {
+	Embedder embeddings.Embedder
+}
+
+// NewEmbeddingRanker creates a ranker embedding texts with a model, e.g. "openai/text-embedding-3-small".
+func NewEmbeddingRanker(model string) (*EmbeddingRanker, error) {
+	embedder, err := llm.NewEmbedder(model)
+	if err != nil {
+		return nil, err
+	}
+	return &EmbeddingRanker{Embedder: embedder}, nil
+}
+
+// Rank implements Ranker.
+func (r *EmbeddingRanker) Rank(ctx context.Context, _ Profile, input string, candidates []Candidate) ([]Suggestion, error) {
+	query, err := r.Embedder.EmbedQuery(ctx, excerpt(input))
+	if err != nil {
+		return nil, fmt.Errorf("embedding input: %w", err)
+	}
+
+	texts := make([]string, len(candidates))
+	for i, c := range candidates {
+		texts[i] = c.describe()
+	}
+	vectors, err := r.Embedder.EmbedDocuments(ctx, texts)
+	if err != nil {
+		return nil, fmt.Errorf("embedding patterns: %w", err)
+	}
+	if len(vectors) != len(candidates) {
+		return nil, fmt.Errorf("unexpected: got %d embeddings for %d patterns", len(vectors), len(candidates))
+	}
+
+	suggestions := make([]Suggestion, len(candidates))
+	for i, c := range candidates {
+		sim := cosine(query, vectors[i])
+		suggestions[i] = Suggestion{
+			Name:    c.Name,
+			Score:   sim,
+			Reasons: []string{fmt.Sprintf("similarity %.2f with its description", sim)},
+		}
+	}
+	return suggestions, nil
+}
+
+// cosine returns the cosine similarity of two vectors, 0 if either is zero.
+func cosine(a, b []float32) float64 {
+	var dot, na, nb float64
+	for i := range min(len(a), len(b)) {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(na) * math.Sqrt(nb))
+}
+
+// CompleteFunc generates the output of a model for messages.
+type CompleteFunc func(ctx context.Context, model string, msgs []llms.MessageContent) (string, error)
+
+// Complete runs a completion with a configured model.
+func Complete(ctx context.Context, model string, msgs []llms.MessageContent) (string, error) {
+	// nolint: contextcheck
+	m, err := llm.New(model)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if err := llm.CreateCompletion(ctx, m, &sb, msgs, llms.WithTemperature(0)); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// ClassifierRanker asks a model, preferably a cheap one, to pick the patterns suiting an input.
+type ClassifierRanker struct {
+	Model    string
+	Complete CompleteFunc
+	// Limit is how many patterns the model is asked to pick.
+	Limit int
+}
+
+const classifierPrompt = `You route inputs to the prompt patterns best suited to process them.
+
+Pick at most %d patterns from the list below for the input given by the user, best first.
+Reply with a JSON array only, without any other text:
+[{"pattern": "<name>", "score": <from 0 to 1>, "reason": "<one short sentence>"}]
+
+Input: %s
+
+Patterns:
+%s`
+
+// Rank implements Ranker.
+func (r *ClassifierRanker) Rank(ctx context.Context, p Profile, input string, candidates []Candidate) ([]Suggestion, error) {
+	var list strings.Builder
+	for _, c := range candidates {
+		fmt.Fprintln(&list, strings.TrimSpace("- "+c.Name+" "+c.details()))
+	}
+
+	limit := r.Limit
+	if limit <= 0 {
+		limit = 5
+	}
+
+	prompt := fmt.Sprintf(classifierPrompt, limit, p, list.String())
+	msgs, err := llm.PrepareMessages(r.Model, "", prompt, excerpt(input), "")
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := r.Complete(ctx, r.Model, msgs)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseClassification(out, candidates)
+}
+
+// classification is a pattern picked by a classifier model.
+type classification struct {
+	Pattern string  `json:"pattern"`
+	Score   float64 `json:"score"`
+	Reason  string  `json:"reason"`
+}
+
+// parseClassification reads the patterns picked by a classifier model,
+// leaving out those that are not candidates, which models sometimes make up.
+func parseClassification(out string, candidates []Candidate) ([]Suggestion, error) {
+	out = strings.TrimSpace(out)
+	// models often wrap JSON in a code block anyway
+	if start, end := strings.Index(out, "["), strings.LastIndex(out, "]"); start != -1 && end > start {
+		out = out[start : end+1]
+	}
+
+	var picked []classification
+	if err := json.Unmarshal([]byte(out), &picked); err != nil {
+		return nil, fmt.Errorf("parsing the reply of the classifier model: %w", err)
+	}
+
+	suggestions := make([]Suggestion, 0, len(picked))
+	for _, c := range picked {
+		known := slices.ContainsFunc(candidates, func(cand Candidate) bool { return cand.Name == c.Pattern })
+		taken := slices.ContainsFunc(suggestions, func(s Suggestion) bool { return s.Name == c.Pattern })
+		if !known || taken {
+			continue
+		}
+
+		s := Suggestion{Name: c.Pattern, Score: min(max(c.Score, 0), 1)}
+		if c.Reason != "" {
+			s.Reasons = []string{c.Reason}
+		}
+		suggestions = append(suggestions, s)
+	}
+
+	if len(suggestions) == 0 {
+		return nil, errors.New("the classifier model picked no known pattern")
+	}
+	return suggestions, nil
+}
@@ -0,0 +1,219 @@
+package suggest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nt54hamnghi/seaq/pkg/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tmc/langchaingo/llms"
+)
+
+var testCandidates = []Candidate{
+	{Name: "summarize", Meta: config.PatternMeta{Description: "Summarize a text", Input: KindText}},
+	{Name: "take_note", Meta: config.PatternMeta{Description: "Take notes of a video", Tags: []string{"youtube"}, Input: KindTranscript}},
+	{Name: "review_code", Meta: config.PatternMeta{Description: "Review code", Input: KindCode}},
+}
+
+// fakeRanker gives fixed scores to candidates.
+type fakeRanker map[string]float64
+
+func (f fakeRanker) Rank(_ context.Context, _ Profile, _ string, candidates []Candidate) ([]Suggestion, error) {
+	var suggestions []Suggestion
+	for _, c := range candidates {
+		if score, ok := f[c.Name]; ok {
+			suggestions = append(suggestions, Suggestion{Name: c.Name, Score: score})
+		}
+	}
+	return suggestions, nil
+}
+
+func TestSuggest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		ranker  fakeRanker
+		profile Profile
+		limit   int
+		want    []Suggestion
+	}{
+		{
+			name:    "ranker scores",
+			ranker:  fakeRanker{"summarize": 0.5, "take_note": 0.9},
+			profile: Profile{},
+			want: []Suggestion{
+				{Name: "take_note", Score: 0.8},
+				{Name: "summarize", Score: 0.4},
+			},
+		},
+		{
+			name:    "input kind and source",
+			ranker:  fakeRanker{"summarize": 0.6, "take_note": 0.5, "review_code": 0.6},
+			profile: Profile{Kind: KindTranscript, Source: "youtube"},
+			want: []Suggestion{
+				{Name: "take_note", Score: 0.65, Reasons: []string{"expects transcript input", "tagged youtube"}},
+				{Name: "review_code", Score: 0.5},
+				{Name: "summarize", Score: 0.5},
+			},
+		},
+		{
+			name:    "limit",
+			ranker:  fakeRanker{"summarize": 0.6, "take_note": 0.5, "review_code": 0.6},
+			profile: Profile{Kind: KindCode},
+			limit:   1,
+			want: []Suggestion{
+				{Name: "review_code", Score: 0.7, Reasons: []string{"expects code input"}},
+			},
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			got, err := Suggest(context.Background(), tt.ranker, tt.profile, "", testCandidates, tt.limit)
+			r.NoError(err)
+			r.Len(got, len(tt.want))
+			for i, s := range got {
+				r.Equal(tt.want[i].Name, s.Name)
+				r.InDelta(tt.want[i].Score, s.Score, 1e-9)
+				r.Equal(tt.want[i].Reasons, s.Reasons)
+			}
+		})
+	}
+}
+
+func TestSuggest_NoCandidates(t *testing.T) {
+	_, err := Suggest(context.Background(), fakeRanker{}, Profile{}, "", nil, 3)
+	require.Error(t, err)
+}
+
+// fakeEmbedder embeds texts by looking them up.
+type fakeEmbedder map[string][]float32
+
+func (f fakeEmbedder) EmbedDocuments(_ context.Context, texts []string) ([][]float32, error) {
+	vectors := make([][]float32, len(texts))
+	for i, t := range texts {
+		vectors[i] = f[t]
+	}
+	return vectors, nil
+}
+
+func (f fakeEmbedder) EmbedQuery(_ context.Context, text string) ([]float32, error) {
+	return f[text], nil
+}
+
+func TestEmbeddingRanker(t *testing.T) {
+	r := require.New(t)
+
+	ranker := &EmbeddingRanker{Embedder: fakeEmbedder{
+		"some input": {1, 0},
+		"summarize: Summarize a text (expects text input)":                      {1, 0},
+		"take note: Take notes of a video [youtube] (expects transcript input)": {0, 1},
+		"review code: Review code (expects code input)":                         {1, 1},
+	}}
+
+	got, err := ranker.Rank(context.Background(), Profile{}, "some input", testCandidates)
+	r.NoError(err)
+	r.Len(got, 3)
+
+	r.Equal("summarize", got[0].Name)
+	r.InDelta(1, got[0].Score, 1e-6)
+	r.Equal([]string{"similarity 1.00 with its description"}, got[0].Reasons)
+	r.InDelta(0, got[1].Score, 1e-6)
+	r.InDelta(0.707, got[2].Score, 1e-3)
+}
+
+func TestCosine(t *testing.T) {
+	r := require.New(t)
+
+	r.InDelta(1, cosine([]float32{1, 2}, []float32{2, 4}), 1e-6)
+	r.InDelta(-1, cosine([]float32{1, 0}, []float32{-1, 0}), 1e-6)
+	r.InDelta(0, cosine([]float32{1, 0}, []float32{0, 1}), 1e-6)
+	r.Zero(cosine([]float32{0, 0}, []float32{1, 1}))
+}
+
+func TestClassifierRanker(t *testing.T) {
+	r := require.New(t)
+
+	var prompt string
+	ranker := &ClassifierRanker{
+		Model: "openai/gpt-4o-mini",
+		Complete: func(_ context.Context, _ string, msgs []llms.MessageContent) (string, error) {
+			prompt = msgs[0].Parts[0].(llms.TextContent).Text
+			return "```json\n" + `[{"pattern": "take_note", "score": 0.9, "reason": "a video transcript"}]` + "\n```", nil
+		},
+		Limit: 2,
+	}
+
+	got, err := ranker.Rank(context.Background(), Profile{Kind: KindTranscript, Words: 3}, "hello there you", testCandidates)
+	r.NoError(err)
+	r.Equal([]Suggestion{{Name: "take_note", Score: 0.9, Reasons: []string{"a video transcript"}}}, got)
+
+	r.Contains(prompt, "Pick at most 2 patterns")
+	r.Contains(prompt, "Input: transcript, 3 words")
+	r.Contains(prompt, "- take_note Take notes of a video [youtube] (expects transcript input)")
+
+	ranker.Complete = func(context.Context, string, []llms.MessageContent) (string, error) {
+		return "", errors.New("unavailable")
+	}
+	_, err = ranker.Rank(context.Background(), Profile{}, "hello", testCandidates)
+	r.Error(err)
+}
+
+func TestParseClassification(t *testing.T) {
+	testCases := []struct {
+		name    string
+		out     string
+		want    []Suggestion
+		wantErr bool
+	}{
+		{
+			name: "valid",
+			out:  `[{"pattern": "summarize", "score": 0.8, "reason": "short text"}, {"pattern": "review_code", "score": 0.2}]`,
+			want: []Suggestion{
+				{Name: "summarize", Score: 0.8, Reasons: []string{"short text"}},
+				{Name: "review_code", Score: 0.2},
+			},
+		},
+		{
+			name: "surrounding text",
+			out:  "Here you go:\n[{\"pattern\": \"summarize\", \"score\": 0.8}]\nHope it helps",
+			want: []Suggestion{{Name: "summarize", Score: 0.8}},
+		},
+		{
+			name: "unknown and duplicate",
+			out:  `[{"pattern": "made_up", "score": 1}, {"pattern": "summarize", "score": 0.5}, {"pattern": "summarize", "score": 0.4}]`,
+			want: []Suggestion{{Name: "summarize", Score: 0.5}},
+		},
+		{
+			name: "clamped score",
+			out:  `[{"pattern": "summarize", "score": 7}, {"pattern": "take_note", "score": -1}]`,
+			want: []Suggestion{{Name: "summarize", Score: 1}, {Name: "take_note", Score: 0}},
+		},
+		{
+			name:    "no known pattern",
+			out:     `[{"pattern": "made_up", "score": 1}]`,
+			wantErr: true,
+		},
+		{
+			name:    "not json",
+			out:     "summarize",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			r := require.New(t)
+
+			got, err := parseClassification(tt.out, testCandidates)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.want, got)
+		})
+	}
+}
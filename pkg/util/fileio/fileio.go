@@ -64,6 +64,15 @@ func IsStdinPiped() (bool, error) {
 	return stat.Mode()&os.ModeCharDevice == 0, nil
 }
 
+// IsTerminal reports whether f is a terminal.
+func IsTerminal(f *os.File) bool {
+	stat, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
+
 func ReadPipedStdin() (string, error) {
 	isPiped, err := IsStdinPiped()
 	if err != nil {
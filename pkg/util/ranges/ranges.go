@@ -0,0 +1,101 @@
+package ranges
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Range is an inclusive range of numbers, starting at 1, such as page numbers.
+// An End of 0 means the range runs to the last number.
+type Range struct {
+	Start int
+	End   int
+}
+
+// Ranges is a selection of numbers. An empty selection selects all numbers.
+type Ranges []Range
+
+// Parse parses a selection, as a comma-separated list of
+// numbers and ranges, e.g. "3-10", "1,4-6" or "12-" for 12 to the end.
+func Parse(spec string) (Ranges, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	var rs Ranges
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+
+		first, last, isRange := strings.Cut(part, "-")
+		start, err := parseNumber(first)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q: %w", spec, err)
+		}
+
+		r := Range{Start: start, End: start}
+		if isRange {
+			r.End = 0
+			if last = strings.TrimSpace(last); last != "" {
+				if r.End, err = parseNumber(last); err != nil {
+					return nil, fmt.Errorf("invalid selection %q: %w", spec, err)
+				}
+				if r.End < r.Start {
+					return nil, fmt.Errorf("invalid selection %q: range %s is reversed", spec, part)
+				}
+			}
+		}
+		rs = append(rs, r)
+	}
+
+	return rs, nil
+}
+
+func parseNumber(s string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive number", s)
+	}
+	return n, nil
+}
+
+// Contains reports whether a number is selected.
+func (rs Ranges) Contains(n int) bool {
+	if len(rs) == 0 {
+		return true
+	}
+	for _, r := range rs {
+		if n >= r.Start && (r.End == 0 || n <= r.End) {
+			return true
+		}
+	}
+	return false
+}
+
+// Exceeds returns the first start of a range greater than total, if any,
+// e.g. a page beyond the end of a document.
+func (rs Ranges) Exceeds(total int) (int, bool) {
+	for _, r := range rs {
+		if r.Start > total {
+			return r.Start, true
+		}
+	}
+	return 0, false
+}
+
+// String formats the selection as accepted by Parse.
+func (rs Ranges) String() string {
+	parts := make([]string, 0, len(rs))
+	for _, r := range rs {
+		switch r.End {
+		case r.Start:
+			parts = append(parts, strconv.Itoa(r.Start))
+		case 0:
+			parts = append(parts, strconv.Itoa(r.Start)+"-")
+		default:
+			parts = append(parts, strconv.Itoa(r.Start)+"-"+strconv.Itoa(r.End))
+		}
+	}
+	return strings.Join(parts, ",")
+}
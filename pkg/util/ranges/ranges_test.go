@@ -0,0 +1,64 @@
+package ranges
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		spec    string
+		want    Ranges
+		wantErr bool
+	}{
+		{spec: "", want: nil},
+		{spec: "3", want: Ranges{{Start: 3, End: 3}}},
+		{spec: "3-10", want: Ranges{{Start: 3, End: 10}}},
+		{spec: "12-", want: Ranges{{Start: 12}}},
+		{spec: "1, 4-6", want: Ranges{{Start: 1, End: 1}, {Start: 4, End: 6}}},
+		{spec: "0", wantErr: true},
+		{spec: "10-3", wantErr: true},
+		{spec: "-3", wantErr: true},
+		{spec: "a-b", wantErr: true},
+		{spec: "1,,2", wantErr: true},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.spec, func(t *testing.T) {
+			r := require.New(t)
+
+			got, err := Parse(tt.spec)
+			if tt.wantErr {
+				r.Error(err)
+				return
+			}
+			r.NoError(err)
+			r.Equal(tt.want, got)
+			r.Equal(tt.spec != "", got.String() != "")
+		})
+	}
+}
+
+func TestRanges(t *testing.T) {
+	r := require.New(t)
+
+	var all Ranges
+	r.True(all.Contains(42))
+	_, ok := all.Exceeds(1)
+	r.False(ok)
+
+	rs := Ranges{{Start: 2, End: 3}, {Start: 7}}
+	r.False(rs.Contains(1))
+	r.True(rs.Contains(2))
+	r.True(rs.Contains(3))
+	r.False(rs.Contains(4))
+	r.True(rs.Contains(100))
+	r.Equal("2-3,7-", rs.String())
+
+	n, ok := rs.Exceeds(5)
+	r.True(ok)
+	r.Equal(7, n)
+	_, ok = rs.Exceeds(7)
+	r.False(ok)
+}
@@ -0,0 +1,33 @@
+// Package ziptest provides helpers to test readers of zip-based documents.
+package ziptest
+
+import (
+	"archive/zip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Write writes an archive of files, given as name and content pairs, in order,
+// to a temporary file with the given name. It returns the path of the file.
+func Write(t testing.TB, name string, files ...[2]string) string {
+	t.Helper()
+
+	file := filepath.Join(t.TempDir(), name)
+	f, err := os.Create(file)
+	require.NoError(t, err)
+	defer f.Close()
+
+	zw := zip.NewWriter(f)
+	for _, file := range files {
+		w, err := zw.Create(file[0])
+		require.NoError(t, err)
+		_, err = w.Write([]byte(file[1]))
+		require.NoError(t, err)
+	}
+	require.NoError(t, zw.Close())
+
+	return file
+}
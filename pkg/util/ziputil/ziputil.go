@@ -0,0 +1,77 @@
+// Package ziputil reads the files of zip-based documents, such as EPUB and Office documents.
+package ziputil
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+)
+
+// MaxFileSize is the size above which reading a file of an archive fails,
+// as a small archive can hold files of any size, e.g. zip bombs.
+const MaxFileSize = 64 << 20
+
+// ErrNotFound is returned when opening a file an archive doesn't have.
+var ErrNotFound = errors.New("file not found in archive")
+
+// Open opens a file of an archive. Reading fails past MaxFileSize.
+func Open(zr *zip.Reader, name string) (io.ReadCloser, error) {
+	f, err := zr.Open(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &limitedFile{ReadCloser: f, r: io.LimitReader(f, MaxFileSize+1), name: name}, nil
+}
+
+// ReadFile reads a file of an archive.
+func ReadFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := Open(zr, name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// DecodeXML decodes an XML file of an archive into v.
+// Options configure the decoder, e.g. to accept HTML entities.
+func DecodeXML(zr *zip.Reader, name string, v any, opts ...func(*xml.Decoder)) error {
+	f, err := Open(zr, name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	d := xml.NewDecoder(f)
+	for _, opt := range opts {
+		opt(d)
+	}
+	if err := d.Decode(v); err != nil {
+		return fmt.Errorf("parsing %s: %w", name, err)
+	}
+	return nil
+}
+
+// limitedFile is a file of an archive whose reads fail past MaxFileSize.
+// The size in the archive's header isn't trusted, only the bytes actually read are counted.
+type limitedFile struct {
+	io.ReadCloser
+	r    io.Reader
+	read int64
+	name string
+}
+
+func (f *limitedFile) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	f.read += int64(n)
+	if f.read > MaxFileSize {
+		return n, fmt.Errorf("%s is larger than %d bytes", f.name, MaxFileSize)
+	}
+	return n, err
+}
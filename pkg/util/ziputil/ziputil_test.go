@@ -0,0 +1,53 @@
+package ziputil
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/nt54hamnghi/seaq/pkg/util/ziputil/ziptest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadFile(t *testing.T) {
+	r := require.New(t)
+
+	file := ziptest.Write(t, "doc.zip",
+		[2]string{"small.txt", "hello"},
+		[2]string{"large.txt", strings.Repeat("a", MaxFileSize+1)},
+	)
+	zr, err := zip.OpenReader(file)
+	r.NoError(err)
+	defer zr.Close()
+
+	data, err := ReadFile(&zr.Reader, "small.txt")
+	r.NoError(err)
+	r.Equal("hello", string(data))
+
+	_, err = ReadFile(&zr.Reader, "large.txt")
+	r.ErrorContains(err, "large.txt is larger than")
+
+	_, err = ReadFile(&zr.Reader, "missing.txt")
+	r.ErrorIs(err, ErrNotFound)
+}
+
+func TestDecodeXML(t *testing.T) {
+	r := require.New(t)
+
+	file := ziptest.Write(t, "doc.zip", [2]string{"doc.xml", "<doc><title>Go&nbsp;101</title></doc>"})
+	zr, err := zip.OpenReader(file)
+	r.NoError(err)
+	defer zr.Close()
+
+	var doc struct {
+		Title string `xml:"title"`
+	}
+	r.Error(DecodeXML(&zr.Reader, "doc.xml", &doc))
+
+	r.NoError(DecodeXML(&zr.Reader, "doc.xml", &doc, func(d *xml.Decoder) {
+		d.Strict = false
+		d.Entity = xml.HTMLEntity
+	}))
+	r.Equal("Go\u00a0101", doc.Title)
+}